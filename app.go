@@ -5,16 +5,38 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"trading-dashboard/pkg/apperror"
 	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/detect"
+	"trading-dashboard/pkg/limits"
+	"trading-dashboard/pkg/marketdata"
 	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/pnl"
 	"trading-dashboard/pkg/repositories"
+	"trading-dashboard/pkg/sizing"
+	"trading-dashboard/pkg/stats"
+	"trading-dashboard/pkg/validation"
 )
 
 // App struct
 type App struct {
-	ctx context.Context
+	ctx        context.Context
+	store      database.Store
+	risks      *repositories.RiskRepository
+	scoring    *repositories.RiskScoringRepository
+	sizing     *repositories.SizingRepository
+	sectors    *repositories.SectorRepository
+	snapshots  *repositories.MarketSnapshotRepository
+	patterns   *repositories.ChartPatternRepository
+	stocks     *repositories.StockRepository
+	watchlists *repositories.WatchlistRepository
+	prices     *repositories.PriceRepository
+	trades     *repositories.TradeRepository
+	events     *repositories.TradeEventRepository
+	breaker    *repositories.CircuitBreakerRepository
 }
 
 // NewApp creates a new App application struct
@@ -62,11 +84,24 @@ func (a *App) startup(ctx context.Context) {
 
 	// Initialize database
 	log.Println("Initializing database...")
-	if err := database.Initialize(); err != nil {
+	store, err := database.Initialize()
+	if err != nil {
 		log.Fatalf("FATAL: Failed to initialize database: %v", err)
 	}
+	a.useStore(store)
 	log.Println("Database initialized successfully")
 
+	// Build secondary indexes for records saved before they existed
+	if err := a.risks.EnsureIndexes(); err != nil {
+		log.Printf("ERROR: Failed to build risk assessment indexes: %v", err)
+	}
+	if err := a.trades.EnsureIndexes(); err != nil {
+		log.Printf("ERROR: Failed to build trade indexes: %v", err)
+	}
+	if err := a.stocks.EnsureIndexes(); err != nil {
+		log.Printf("ERROR: Failed to build stock rating indexes: %v", err)
+	}
+
 	// Log app data and database path
 	appDataDir, err := os.UserConfigDir()
 	if err == nil {
@@ -86,6 +121,23 @@ func (a *App) startup(ctx context.Context) {
 	}
 }
 
+// useStore wires the repositories to store
+func (a *App) useStore(store database.Store) {
+	a.store = store
+	a.scoring = repositories.NewRiskScoringRepository(store)
+	a.risks = repositories.NewRiskRepository(store, a.scoring)
+	a.sizing = repositories.NewSizingRepository(store, a.risks)
+	a.sectors = repositories.NewSectorRepository(store)
+	a.snapshots = repositories.NewMarketSnapshotRepository(store)
+	a.patterns = repositories.NewChartPatternRepository(store)
+	a.stocks = repositories.NewStockRepository(store, a.sectors, a.snapshots, a.patterns)
+	a.watchlists = repositories.NewWatchlistRepository(store, a.stocks)
+	a.prices = repositories.NewPriceRepository(store)
+	a.trades = repositories.NewTradeRepository(store, a.sizing, a.risks, a.stocks)
+	a.events = repositories.NewTradeEventRepository(store, a.trades)
+	a.breaker = repositories.NewCircuitBreakerRepository(store, a.risks, a.events)
+}
+
 // Helper to get file size
 func getFileSize(path string) int64 {
 	info, err := os.Stat(path)
@@ -99,7 +151,7 @@ func getFileSize(path string) int64 {
 func (a *App) shutdown(ctx context.Context) {
 	log.Println("Application shutting down...")
 	// Close database connection
-	database.Close()
+	database.Close(a.store)
 	log.Println("Database connection closed")
 	log.Println("===== Application Terminated =====")
 }
@@ -114,7 +166,7 @@ func (a *App) GetVersion() string {
 // SaveRiskAssessment saves a risk assessment
 func (a *App) SaveRiskAssessment(assessment models.RiskAssessment) (*models.RiskAssessment, error) {
 	log.Printf("API: SaveRiskAssessment called with ID=%s", assessment.ID)
-	err := repositories.SaveRiskAssessment(&assessment)
+	err := a.risks.SaveRiskAssessment(&assessment)
 	if err != nil {
 		log.Printf("ERROR: SaveRiskAssessment failed: %v", err)
 		return nil, err
@@ -126,7 +178,7 @@ func (a *App) SaveRiskAssessment(assessment models.RiskAssessment) (*models.Risk
 // GetLatestRiskAssessment gets the latest risk assessment
 func (a *App) GetLatestRiskAssessment() (*models.RiskAssessment, error) {
 	log.Println("API: GetLatestRiskAssessment called")
-	result, err := repositories.GetLatestRiskAssessment()
+	result, err := a.risks.GetLatestRiskAssessment()
 	if err != nil {
 		log.Printf("ERROR: GetLatestRiskAssessment failed: %v", err)
 		return nil, err
@@ -138,7 +190,7 @@ func (a *App) GetLatestRiskAssessment() (*models.RiskAssessment, error) {
 // GetAllRiskAssessments gets all risk assessments
 func (a *App) GetAllRiskAssessments() ([]*models.RiskAssessment, error) {
 	log.Println("API: GetAllRiskAssessments called")
-	result, err := repositories.GetAllRiskAssessments()
+	result, err := a.risks.GetAllRiskAssessments()
 	if err != nil {
 		log.Printf("ERROR: GetAllRiskAssessments failed: %v", err)
 		return nil, err
@@ -147,12 +199,73 @@ func (a *App) GetAllRiskAssessments() ([]*models.RiskAssessment, error) {
 	return result, nil
 }
 
+// GetRiskAssessmentHistory returns the earlier versions of an assessment
+func (a *App) GetRiskAssessmentHistory(id string) ([]*models.RiskAssessmentRevision, error) {
+	log.Printf("API: GetRiskAssessmentHistory called with ID=%s", id)
+	result, err := a.risks.GetRiskAssessmentHistory(id)
+	if err != nil {
+		log.Printf("ERROR: GetRiskAssessmentHistory failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetRiskAssessmentHistory returned %d records", len(result))
+	return result, nil
+}
+
+// GetTodayCheckInStatus reports whether today's risk assessment is missing
+func (a *App) GetTodayCheckInStatus() (*repositories.CheckInStatus, error) {
+	log.Println("API: GetTodayCheckInStatus called")
+	result, err := a.risks.GetCheckInStatus(time.Now())
+	if err != nil {
+		log.Printf("ERROR: GetTodayCheckInStatus failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetTodayCheckInStatus returned missing=%t for %s", result.Missing, result.Date)
+	return result, nil
+}
+
+// GetRiskScoringModel returns the model new risk assessments are scored with
+func (a *App) GetRiskScoringModel() (*models.RiskScoringModel, error) {
+	log.Println("API: GetRiskScoringModel called")
+	result, err := a.scoring.GetActiveRiskScoringModel()
+	if err != nil {
+		log.Printf("ERROR: GetRiskScoringModel failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetRiskScoringModel returned version %s", result.Version)
+	return result, nil
+}
+
+// GetAllRiskScoringModels returns every saved scoring model
+func (a *App) GetAllRiskScoringModels() ([]*models.RiskScoringModel, error) {
+	log.Println("API: GetAllRiskScoringModels called")
+	result, err := a.scoring.GetAllRiskScoringModels()
+	if err != nil {
+		log.Printf("ERROR: GetAllRiskScoringModels failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetAllRiskScoringModels returned %d records", len(result))
+	return result, nil
+}
+
+// SaveRiskScoringModel saves a scoring model and makes it active.
+// Existing assessments keep the score and version they were saved with.
+func (a *App) SaveRiskScoringModel(model models.RiskScoringModel) (*models.RiskScoringModel, error) {
+	log.Printf("API: SaveRiskScoringModel called with version=%s", model.Version)
+	err := a.scoring.SaveRiskScoringModel(&model)
+	if err != nil {
+		log.Printf("ERROR: SaveRiskScoringModel failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: SaveRiskScoringModel activated version %s", model.Version)
+	return &model, nil
+}
+
 // Stock Rating API Methods
 
 // SaveStockRating saves a stock rating
 func (a *App) SaveStockRating(rating models.StockRating) (*models.StockRating, error) {
 	log.Printf("API: SaveStockRating called with ticker=%s", rating.Ticker)
-	err := repositories.SaveStockRating(&rating)
+	err := a.stocks.SaveStockRating(&rating)
 	if err != nil {
 		log.Printf("ERROR: SaveStockRating failed: %v", err)
 		return nil, err
@@ -164,19 +277,19 @@ func (a *App) SaveStockRating(rating models.StockRating) (*models.StockRating, e
 // GetStockRating gets a stock rating by ID
 func (a *App) GetStockRating(id string) (*models.StockRating, error) {
 	log.Printf("API: GetStockRating called with ID=%s", id)
-	return repositories.GetStockRating(id)
+	return a.stocks.GetStockRating(id)
 }
 
 // GetStockRatingsByTicker gets all stock ratings for a specific ticker
 func (a *App) GetStockRatingsByTicker(ticker string) ([]*models.StockRating, error) {
 	log.Printf("API: GetStockRatingsByTicker called with ticker=%s", ticker)
-	return repositories.GetStockRatingsByTicker(ticker)
+	return a.stocks.GetStockRatingsByTicker(ticker)
 }
 
 // GetAllStockRatings gets all stock ratings
 func (a *App) GetAllStockRatings() ([]*models.StockRating, error) {
 	log.Println("API: GetAllStockRatings called")
-	result, err := repositories.GetAllStockRatings()
+	result, err := a.stocks.GetAllStockRatings()
 	if err != nil {
 		log.Printf("ERROR: GetAllStockRatings failed: %v", err)
 		return nil, err
@@ -185,12 +298,502 @@ func (a *App) GetAllStockRatings() ([]*models.StockRating, error) {
 	return result, nil
 }
 
+// GetStockRatingHistory returns the earlier versions of a stock rating
+func (a *App) GetStockRatingHistory(id string) ([]*models.StockRatingRevision, error) {
+	log.Printf("API: GetStockRatingHistory called with ID=%s", id)
+	result, err := a.stocks.GetStockRatingHistory(id)
+	if err != nil {
+		log.Printf("ERROR: GetStockRatingHistory failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetStockRatingHistory returned %d records", len(result))
+	return result, nil
+}
+
+// GetStockRatingTrend returns a ticker's enthusiasm, sentiment and
+// pattern over time, flagging saves that crossed the enthusiasm threshold
+func (a *App) GetStockRatingTrend(ticker string, threshold int) (*stats.RatingTrend, error) {
+	log.Printf("API: GetStockRatingTrend called with ticker=%s threshold=%d", ticker, threshold)
+	versions, err := a.stocks.GetStockRatingVersions(ticker)
+	if err != nil {
+		log.Printf("ERROR: GetStockRatingTrend failed: %v", err)
+		return nil, err
+	}
+	result := stats.BuildRatingTrend(strings.ToUpper(ticker), versions, threshold)
+	log.Printf("SUCCESS: GetStockRatingTrend returned %d points and %d crossings", len(result.Points), len(result.Crossings))
+	return result, nil
+}
+
+// Market Data API Methods
+
+// parseInterval reads an interval passed to the API
+func parseInterval(interval string) (marketdata.Interval, error) {
+	parsed, err := marketdata.ParseInterval(interval)
+	if err != nil {
+		return "", apperror.Wrap(apperror.Invalid, err)
+	}
+	return parsed, nil
+}
+
+// ImportPriceBars imports OHLCV bars of one interval from CSV text.
+// ticker is used when the file has no ticker or symbol column.
+func (a *App) ImportPriceBars(csvText, ticker, interval string) (*marketdata.ImportResult, error) {
+	log.Printf("API: ImportPriceBars called with ticker=%s interval=%s (%d bytes)", ticker, interval, len(csvText))
+	parsed, err := parseInterval(interval)
+	if err != nil {
+		log.Printf("ERROR: ImportPriceBars failed: %v", err)
+		return nil, err
+	}
+	result, err := a.prices.ImportBarsCSV(strings.NewReader(csvText), marketdata.ImportOptions{Ticker: ticker, Interval: parsed})
+	if err != nil {
+		log.Printf("ERROR: ImportPriceBars failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: ImportPriceBars imported %d bars, skipped %d", result.Imported, result.Skipped)
+	return result, nil
+}
+
+// GetPriceBars gets a ticker's bars of one interval between two times
+// (inclusive; zero for no bound), oldest first
+func (a *App) GetPriceBars(ticker, interval string, start, end time.Time) ([]marketdata.Bar, error) {
+	log.Printf("API: GetPriceBars called with ticker=%s interval=%s range %v to %v", ticker, interval, start, end)
+	parsed, err := parseInterval(interval)
+	if err != nil {
+		log.Printf("ERROR: GetPriceBars failed: %v", err)
+		return nil, err
+	}
+	result, err := a.prices.GetBars(ticker, parsed, start, end)
+	if err != nil {
+		log.Printf("ERROR: GetPriceBars failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetPriceBars returned %d bars", len(result))
+	return result, nil
+}
+
+// GetLatestPriceBar gets a ticker's last bar of one interval at or before asOf
+func (a *App) GetLatestPriceBar(ticker, interval string, asOf time.Time) (*marketdata.Bar, error) {
+	log.Printf("API: GetLatestPriceBar called with ticker=%s interval=%s asOf=%v", ticker, interval, asOf)
+	parsed, err := parseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	return a.prices.GetLatestBar(ticker, parsed, asOf)
+}
+
+// GetPriceSeries lists the tickers and intervals with stored bars
+func (a *App) GetPriceSeries() ([]*marketdata.Series, error) {
+	log.Println("API: GetPriceSeries called")
+	result, err := a.prices.GetPriceSeries()
+	if err != nil {
+		log.Printf("ERROR: GetPriceSeries failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetPriceSeries returned %d series", len(result))
+	return result, nil
+}
+
+// DeletePriceBars deletes a ticker's bars of one interval
+func (a *App) DeletePriceBars(ticker, interval string) error {
+	log.Printf("API: DeletePriceBars called with ticker=%s interval=%s", ticker, interval)
+	parsed, err := parseInterval(interval)
+	if err != nil {
+		return err
+	}
+	if err := a.prices.DeleteBars(ticker, parsed); err != nil {
+		log.Printf("ERROR: DeletePriceBars failed: %v", err)
+		return err
+	}
+	log.Printf("SUCCESS: DeletePriceBars deleted %s %s", ticker, parsed)
+	return nil
+}
+
+// Pattern Detection API Methods
+
+// dailyBarsAsOf gets the year of daily bars up to and including the bar
+// of asOf's trading day (now when zero) that patterns are detected in
+func (a *App) dailyBarsAsOf(ticker string, asOf time.Time) ([]marketdata.Bar, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	end := models.DayStart(asOf) // Daily bars start at midnight UTC of their day
+	bars, err := a.prices.GetBars(ticker, marketdata.Daily, end.AddDate(-1, -1, 0), end)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, apperror.Newf(apperror.NotFound, "no daily price bars for %s up to %s; import prices first",
+			strings.ToUpper(ticker), models.TradingDay(asOf))
+	}
+	return bars, nil
+}
+
+// DetectChartPatterns finds chart patterns in a ticker's daily bars up to
+// asOf (now when zero), most confident first
+func (a *App) DetectChartPatterns(ticker string, asOf time.Time) ([]detect.Detection, error) {
+	log.Printf("API: DetectChartPatterns called with ticker=%s asOf=%v", ticker, asOf)
+	bars, err := a.dailyBarsAsOf(ticker, asOf)
+	if err != nil {
+		log.Printf("ERROR: DetectChartPatterns failed: %v", err)
+		return nil, err
+	}
+	result := detect.Detect(bars)
+	log.Printf("SUCCESS: DetectChartPatterns found %d patterns in %d bars", len(result), len(bars))
+	return result, nil
+}
+
+// ReviewStockRatingPattern checks a rating's chart pattern against the
+// ticker's daily bars up to the rating's day and suggests the patterns
+// found instead. The rating need not be saved.
+func (a *App) ReviewStockRatingPattern(rating models.StockRating) (*detect.Review, error) {
+	log.Printf("API: ReviewStockRatingPattern called with ticker=%s pattern=%s", rating.Ticker, rating.Pattern)
+	pattern := rating.Pattern
+	if pattern == "" && rating.PatternID != "" {
+		library, err := a.patterns.GetChartPattern(rating.PatternID)
+		if err != nil {
+			log.Printf("ERROR: ReviewStockRatingPattern failed: %v", err)
+			return nil, err
+		}
+		pattern = library.Name
+	}
+	bars, err := a.dailyBarsAsOf(rating.Ticker, rating.Date)
+	if err != nil {
+		log.Printf("ERROR: ReviewStockRatingPattern failed: %v", err)
+		return nil, err
+	}
+	result := detect.ReviewPattern(bars, pattern)
+	log.Printf("SUCCESS: ReviewStockRatingPattern: %s", result.Message)
+	return result, nil
+}
+
+// Watchlist API Methods
+
+// GetAllWatchlists gets every watchlist
+func (a *App) GetAllWatchlists() ([]*models.Watchlist, error) {
+	log.Println("API: GetAllWatchlists called")
+	result, err := a.watchlists.GetAllWatchlists()
+	if err != nil {
+		log.Printf("ERROR: GetAllWatchlists failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetAllWatchlists returned %d records", len(result))
+	return result, nil
+}
+
+// GetWatchlist gets a watchlist by ID
+func (a *App) GetWatchlist(id string) (*models.Watchlist, error) {
+	log.Printf("API: GetWatchlist called with ID=%s", id)
+	return a.watchlists.GetWatchlist(id)
+}
+
+// SaveWatchlist creates or replaces a watchlist
+func (a *App) SaveWatchlist(watchlist models.Watchlist) (*models.Watchlist, error) {
+	log.Printf("API: SaveWatchlist called with name=%s", watchlist.Name)
+	err := a.watchlists.SaveWatchlist(&watchlist)
+	if err != nil {
+		log.Printf("ERROR: SaveWatchlist failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: SaveWatchlist saved with ID=%s", watchlist.ID)
+	return &watchlist, nil
+}
+
+// DeleteWatchlist deletes a watchlist
+func (a *App) DeleteWatchlist(id string) error {
+	log.Printf("API: DeleteWatchlist called with ID=%s", id)
+	err := a.watchlists.DeleteWatchlist(id)
+	if err != nil {
+		log.Printf("ERROR: DeleteWatchlist failed: %v", err)
+		return err
+	}
+	log.Printf("SUCCESS: DeleteWatchlist deleted ID=%s", id)
+	return nil
+}
+
+// AddWatchlistItem adds a ticker to a watchlist or updates its levels
+func (a *App) AddWatchlistItem(watchlistID string, item models.WatchlistItem) (*models.Watchlist, error) {
+	log.Printf("API: AddWatchlistItem called with watchlist=%s ticker=%s", watchlistID, item.Ticker)
+	result, err := a.watchlists.AddWatchlistItem(watchlistID, item)
+	if err != nil {
+		log.Printf("ERROR: AddWatchlistItem failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: AddWatchlistItem saved, watchlist has %d tickers", len(result.Items))
+	return result, nil
+}
+
+// RemoveWatchlistItem removes a ticker from a watchlist
+func (a *App) RemoveWatchlistItem(watchlistID, ticker string) (*models.Watchlist, error) {
+	log.Printf("API: RemoveWatchlistItem called with watchlist=%s ticker=%s", watchlistID, ticker)
+	result, err := a.watchlists.RemoveWatchlistItem(watchlistID, ticker)
+	if err != nil {
+		log.Printf("ERROR: RemoveWatchlistItem failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: RemoveWatchlistItem saved, watchlist has %d tickers", len(result.Items))
+	return result, nil
+}
+
+// RankTickers ranks tickers by their latest enthusiasm rating, filtered
+// by sector, pattern or watchlist
+func (a *App) RankTickers(query models.RankQuery) ([]*models.RankedTicker, error) {
+	log.Printf("API: RankTickers called with sector=%s pattern=%s watchlist=%s", query.Sector, query.Pattern, query.WatchlistID)
+	result, err := a.watchlists.RankTickers(query)
+	if err != nil {
+		log.Printf("ERROR: RankTickers failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: RankTickers returned %d tickers", len(result))
+	return result, nil
+}
+
+// Chart Pattern API Methods
+
+// GetAllChartPatterns gets the chart pattern library, including inactive patterns
+func (a *App) GetAllChartPatterns() ([]*models.ChartPattern, error) {
+	log.Println("API: GetAllChartPatterns called")
+	result, err := a.patterns.GetAllChartPatterns()
+	if err != nil {
+		log.Printf("ERROR: GetAllChartPatterns failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetAllChartPatterns returned %d records", len(result))
+	return result, nil
+}
+
+// GetChartPattern gets a chart pattern by ID
+func (a *App) GetChartPattern(id string) (*models.ChartPattern, error) {
+	log.Printf("API: GetChartPattern called with ID=%s", id)
+	return a.patterns.GetChartPattern(id)
+}
+
+// SaveChartPattern creates or updates a chart pattern. Ratings already
+// scored with the pattern keep their points.
+func (a *App) SaveChartPattern(pattern models.ChartPattern) (*models.ChartPattern, error) {
+	log.Printf("API: SaveChartPattern called with name=%s", pattern.Name)
+	err := a.patterns.SaveChartPattern(&pattern)
+	if err != nil {
+		log.Printf("ERROR: SaveChartPattern failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: SaveChartPattern saved with ID=%s", pattern.ID)
+	return &pattern, nil
+}
+
+// DeleteChartPattern deletes a chart pattern; deactivate it instead to
+// keep it listed with older ratings
+func (a *App) DeleteChartPattern(id string) error {
+	log.Printf("API: DeleteChartPattern called with ID=%s", id)
+	err := a.patterns.DeleteChartPattern(id)
+	if err != nil {
+		log.Printf("ERROR: DeleteChartPattern failed: %v", err)
+		return err
+	}
+	log.Printf("SUCCESS: DeleteChartPattern deleted ID=%s", id)
+	return nil
+}
+
+// Market Snapshot API Methods
+
+// SaveMarketSnapshot saves the market and sector sentiments for a day
+func (a *App) SaveMarketSnapshot(snapshot models.MarketSnapshot) (*models.MarketSnapshot, error) {
+	log.Printf("API: SaveMarketSnapshot called with date=%s", snapshot.Day())
+	err := a.snapshots.SaveMarketSnapshot(&snapshot)
+	if err != nil {
+		log.Printf("ERROR: SaveMarketSnapshot failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: SaveMarketSnapshot saved with ID=%s", snapshot.ID)
+	return &snapshot, nil
+}
+
+// GetMarketSnapshotForDay gets the market snapshot for a day (YYYY-MM-DD)
+func (a *App) GetMarketSnapshotForDay(day string) (*models.MarketSnapshot, error) {
+	log.Printf("API: GetMarketSnapshotForDay called with day=%s", day)
+	return a.snapshots.GetMarketSnapshotForDay(day)
+}
+
+// GetMarketSnapshots gets the market snapshots between two days
+// (YYYY-MM-DD, inclusive; empty for no bound), oldest first, for
+// charting sector rotation
+func (a *App) GetMarketSnapshots(startDay, endDay string) ([]*models.MarketSnapshot, error) {
+	log.Printf("API: GetMarketSnapshots called with range %s to %s", startDay, endDay)
+	result, err := a.snapshots.GetMarketSnapshots(startDay, endDay)
+	if err != nil {
+		log.Printf("ERROR: GetMarketSnapshots failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetMarketSnapshots returned %d records", len(result))
+	return result, nil
+}
+
+// Sector API Methods
+
+// GetTickerSector gets the sector of a ticker
+func (a *App) GetTickerSector(ticker string) (*models.TickerSector, error) {
+	log.Printf("API: GetTickerSector called with ticker=%s", ticker)
+	return a.sectors.GetTickerSector(ticker)
+}
+
+// GetAllTickerSectors gets the ticker to sector table
+func (a *App) GetAllTickerSectors() ([]*models.TickerSector, error) {
+	log.Println("API: GetAllTickerSectors called")
+	result, err := a.sectors.GetAllTickerSectors()
+	if err != nil {
+		log.Printf("ERROR: GetAllTickerSectors failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetAllTickerSectors returned %d records", len(result))
+	return result, nil
+}
+
+// SetTickerSector assigns a ticker to a sector
+func (a *App) SetTickerSector(ticker, sector string) (*models.TickerSector, error) {
+	log.Printf("API: SetTickerSector called with ticker=%s sector=%s", ticker, sector)
+	result, err := a.sectors.SetTickerSector(ticker, sector)
+	if err != nil {
+		log.Printf("ERROR: SetTickerSector failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: SetTickerSector saved %s as %s", result.Ticker, result.Sector)
+	return result, nil
+}
+
+// DeleteTickerSector removes an edited sector so the built-in one applies again
+func (a *App) DeleteTickerSector(ticker string) error {
+	log.Printf("API: DeleteTickerSector called with ticker=%s", ticker)
+	return a.sectors.DeleteTickerSector(ticker)
+}
+
+// ImportTickerSectors imports "ticker,sector" CSV text
+func (a *App) ImportTickerSectors(csvText string) (int, error) {
+	log.Printf("API: ImportTickerSectors called with %d bytes", len(csvText))
+	count, err := a.sectors.ImportTickerSectors(strings.NewReader(csvText))
+	if err != nil {
+		log.Printf("ERROR: ImportTickerSectors failed: %v", err)
+		return 0, err
+	}
+	log.Printf("SUCCESS: ImportTickerSectors imported %d tickers", count)
+	return count, nil
+}
+
+// Position Sizing API Methods
+
+// GetSizingRules returns the position-sizing rules
+func (a *App) GetSizingRules() (*sizing.Rules, error) {
+	log.Println("API: GetSizingRules called")
+	result, err := a.sizing.GetSizingRules()
+	if err != nil {
+		log.Printf("ERROR: GetSizingRules failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetSizingRules returned method=%s enforcement=%s", result.Method, result.Enforcement)
+	return result, nil
+}
+
+// SaveSizingRules saves the position-sizing rules
+func (a *App) SaveSizingRules(rules sizing.Rules) (*sizing.Rules, error) {
+	log.Printf("API: SaveSizingRules called with method=%s", rules.Method)
+	err := a.sizing.SaveSizingRules(&rules)
+	if err != nil {
+		log.Printf("ERROR: SaveSizingRules failed: %v", err)
+		return nil, err
+	}
+	log.Println("SUCCESS: SaveSizingRules saved")
+	return &rules, nil
+}
+
+// RecommendPositionSize sizes a trade that can lose at most
+// maxLossPerUnit dollars per contract/spread/share. volatility is the
+// underlying's annualized volatility in percent (0 if unknown).
+func (a *App) RecommendPositionSize(maxLossPerUnit, volatility float64) (*sizing.Recommendation, error) {
+	log.Printf("API: RecommendPositionSize called with maxLossPerUnit=%.2f volatility=%.2f", maxLossPerUnit, volatility)
+	result, err := a.sizing.Recommend(sizing.Request{MaxLossPerUnit: maxLossPerUnit, Volatility: volatility})
+	if err != nil {
+		log.Printf("ERROR: RecommendPositionSize failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: RecommendPositionSize returned %d units", result.Units)
+	return result, nil
+}
+
+// Circuit Breaker API Methods
+
+// GetTradingStatus reports whether trading is locked by a loss limit or
+// today's risk score
+func (a *App) GetTradingStatus() (*repositories.TradingStatus, error) {
+	log.Println("API: GetTradingStatus called")
+	result, err := a.breaker.GetTradingStatus()
+	if err != nil {
+		log.Printf("ERROR: GetTradingStatus failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetTradingStatus returned locked=%t", result.Locked)
+	return result, nil
+}
+
+// OverrideTradingLock lifts the active trading locks; reason is required
+// and recorded
+func (a *App) OverrideTradingLock(reason string) (*models.LockOverride, error) {
+	log.Println("API: OverrideTradingLock called")
+	result, err := a.breaker.OverrideLocks(reason)
+	if err != nil {
+		log.Printf("ERROR: OverrideTradingLock failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: OverrideTradingLock recorded %s", result.ID)
+	return result, nil
+}
+
+// GetLockOverrides returns every recorded trading lock override
+func (a *App) GetLockOverrides() ([]*models.LockOverride, error) {
+	log.Println("API: GetLockOverrides called")
+	result, err := a.breaker.GetLockOverrides()
+	if err != nil {
+		log.Printf("ERROR: GetLockOverrides failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetLockOverrides returned %d records", len(result))
+	return result, nil
+}
+
+// GetLossLimitRules returns the loss limit and risk score lock rules
+func (a *App) GetLossLimitRules() (*limits.Rules, error) {
+	log.Println("API: GetLossLimitRules called")
+	result, err := a.breaker.GetLossLimitRules()
+	if err != nil {
+		log.Printf("ERROR: GetLossLimitRules failed: %v", err)
+		return nil, err
+	}
+	log.Println("SUCCESS: GetLossLimitRules returned rules")
+	return result, nil
+}
+
+// SaveLossLimitRules saves the loss limit and risk score lock rules
+func (a *App) SaveLossLimitRules(rules limits.Rules) (*limits.Rules, error) {
+	log.Printf("API: SaveLossLimitRules called with daily=%.2f weekly=%.2f", rules.DailyLossLimit, rules.WeeklyLossLimit)
+	err := a.breaker.SaveLossLimitRules(&rules)
+	if err != nil {
+		log.Printf("ERROR: SaveLossLimitRules failed: %v", err)
+		return nil, err
+	}
+	log.Println("SUCCESS: SaveLossLimitRules saved")
+	return &rules, nil
+}
+
 // Trade Calendar API Methods
 
 // SaveTrade saves a trade
 func (a *App) SaveTrade(trade models.Trade) (*models.Trade, error) {
 	log.Printf("API: SaveTrade called with ticker=%s", trade.Ticker)
-	err := repositories.SaveTrade(&trade)
+	// New trades open positions, which a trading lock forbids
+	if trade.ID == "" {
+		if err := a.breaker.CheckCanOpen(); err != nil {
+			log.Printf("ERROR: SaveTrade refused: %v", err)
+			return nil, err
+		}
+	}
+	err := a.trades.SaveTrade(&trade)
 	if err != nil {
 		log.Printf("ERROR: SaveTrade failed: %v", err)
 		return nil, err
@@ -202,7 +805,7 @@ func (a *App) SaveTrade(trade models.Trade) (*models.Trade, error) {
 // GetTrade gets a trade by ID
 func (a *App) GetTrade(id string) (*models.Trade, error) {
 	log.Printf("API: GetTrade called with ID=%s", id)
-	return repositories.GetTrade(id)
+	return a.trades.GetTrade(id)
 }
 
 // GetTradesByDateRange gets trades within a date range
@@ -211,16 +814,16 @@ func (a *App) GetTradesByDateRange(startDateStr, endDateStr string) ([]*models.T
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
 		log.Printf("ERROR: Failed to parse start date: %v", err)
-		return nil, err
+		return nil, apperror.Newf(apperror.Invalid, "invalid start date %q: %w", startDateStr, err)
 	}
 
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
 		log.Printf("ERROR: Failed to parse end date: %v", err)
-		return nil, err
+		return nil, apperror.Newf(apperror.Invalid, "invalid end date %q: %w", endDateStr, err)
 	}
 
-	result, err := repositories.GetTradesByDateRange(startDate, endDate)
+	result, err := a.trades.GetTradesByDateRange(startDate, endDate)
 	if err != nil {
 		log.Printf("ERROR: GetTradesByDateRange failed: %v", err)
 		return nil, err
@@ -232,13 +835,13 @@ func (a *App) GetTradesByDateRange(startDateStr, endDateStr string) ([]*models.T
 // GetTradesByTicker gets trades for a specific ticker
 func (a *App) GetTradesByTicker(ticker string) ([]*models.Trade, error) {
 	log.Printf("API: GetTradesByTicker called with ticker=%s", ticker)
-	return repositories.GetTradesByTicker(ticker)
+	return a.trades.GetTradesByTicker(ticker)
 }
 
 // GetAllTrades gets all trades
 func (a *App) GetAllTrades() ([]*models.Trade, error) {
 	log.Println("API: GetAllTrades called")
-	result, err := repositories.GetAllTrades()
+	result, err := a.trades.GetAllTrades()
 	if err != nil {
 		log.Printf("ERROR: GetAllTrades failed: %v", err)
 		return nil, err
@@ -247,10 +850,10 @@ func (a *App) GetAllTrades() ([]*models.Trade, error) {
 	return result, nil
 }
 
-// DeleteTrade deletes a trade
+// DeleteTrade deletes a trade and its lifecycle events
 func (a *App) DeleteTrade(id string) error {
 	log.Printf("API: DeleteTrade called with ID=%s", id)
-	err := repositories.DeleteTrade(id)
+	err := a.events.DeleteTrade(id)
 	if err != nil {
 		log.Printf("ERROR: DeleteTrade failed: %v", err)
 		return err
@@ -258,3 +861,146 @@ func (a *App) DeleteTrade(id string) error {
 	log.Printf("SUCCESS: DeleteTrade completed for ID=%s", id)
 	return nil
 }
+
+// Trade Lifecycle API Methods
+
+// RecordTradeEvent records an open, adjust, roll, close, expire,
+// assignment or exercise event against a trade
+func (a *App) RecordTradeEvent(event models.TradeEvent) (*models.TradeEvent, error) {
+	log.Printf("API: RecordTradeEvent called with trade=%s type=%s", event.TradeID, event.Type)
+	// Events that add contracts open positions, which a trading lock forbids
+	opens, err := a.events.OpensContracts(&event)
+	if err != nil {
+		log.Printf("ERROR: RecordTradeEvent failed: %v", err)
+		return nil, err
+	}
+	if opens {
+		if err := a.breaker.CheckCanOpen(); err != nil {
+			log.Printf("ERROR: RecordTradeEvent refused: %v", err)
+			return nil, err
+		}
+	}
+	err = a.events.RecordTradeEvent(&event)
+	if err != nil {
+		log.Printf("ERROR: RecordTradeEvent failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: RecordTradeEvent saved with ID=%s", event.ID)
+	return &event, nil
+}
+
+// GetTradeEvents gets the lifecycle events of a trade
+func (a *App) GetTradeEvents(tradeID string) ([]*models.TradeEvent, error) {
+	log.Printf("API: GetTradeEvents called with trade=%s", tradeID)
+	result, err := a.events.GetTradeEvents(tradeID)
+	if err != nil {
+		log.Printf("ERROR: GetTradeEvents failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetTradeEvents returned %d records", len(result))
+	return result, nil
+}
+
+// GetTradePosition gets the current position, realized P&L and holding
+// period of a trade derived from its events
+func (a *App) GetTradePosition(tradeID string) (*models.Position, error) {
+	log.Printf("API: GetTradePosition called with trade=%s", tradeID)
+	result, err := a.events.GetPosition(tradeID)
+	if err != nil {
+		log.Printf("ERROR: GetTradePosition failed: %v", err)
+		return nil, err
+	}
+	log.Printf("SUCCESS: GetTradePosition returned status=%s realized=%.2f", result.Status, result.RealizedPnl)
+	return result, nil
+}
+
+// GetTradePnl returns a trade's realized P&L and, for open legs with a
+// mark, its unrealized P&L
+func (a *App) GetTradePnl(tradeID string, marks []pnl.Mark) (*pnl.TradePnl, error) {
+	log.Printf("API: GetTradePnl called with trade=%s marks=%d", tradeID, len(marks))
+	trade, err := a.trades.GetTrade(tradeID)
+	if err != nil {
+		log.Printf("ERROR: GetTradePnl failed: %v", err)
+		return nil, err
+	}
+	pos, err := a.events.GetPosition(trade.ID)
+	if err != nil {
+		log.Printf("ERROR: GetTradePnl failed: %v", err)
+		return nil, err
+	}
+	result := pnl.ForTrade(trade, pos, marks)
+	log.Printf("SUCCESS: GetTradePnl returned realized=%.2f unrealized=%.2f", result.Realized, result.Unrealized)
+	return &result, nil
+}
+
+// GetPnlReport returns P&L per trade, per day and per strategy across
+// every trade. marks value open legs; open trades without marks are
+// listed in the report's Unmarked field.
+func (a *App) GetPnlReport(marks []pnl.Mark) (*pnl.Report, error) {
+	log.Printf("API: GetPnlReport called with marks=%d", len(marks))
+	trades, positions, err := a.events.GetAllPositions()
+	if err != nil {
+		log.Printf("ERROR: GetPnlReport failed: %v", err)
+		return nil, err
+	}
+	result := pnl.Build(trades, positions, marks)
+	log.Printf("SUCCESS: GetPnlReport returned %d trades realized=%.2f", len(result.Trades), result.TotalRealized)
+	return result, nil
+}
+
+// GetPerformanceStats returns win rate, expectancy, drawdown and other
+// statistics for closed trades, overall and by strategy, direction,
+// sector and ticker
+func (a *App) GetPerformanceStats() (*stats.Report, error) {
+	log.Println("API: GetPerformanceStats called")
+	trades, positions, err := a.events.GetAllPositions()
+	if err != nil {
+		log.Printf("ERROR: GetPerformanceStats failed: %v", err)
+		return nil, err
+	}
+	result := stats.Compute(trades, positions)
+	log.Printf("SUCCESS: GetPerformanceStats returned %d closed trades", result.Overall.Trades)
+	return result, nil
+}
+
+// GetRiskOutcomeCorrelation relates each day's risk assessment to the
+// closed trades entered that day, per factor and per score
+func (a *App) GetRiskOutcomeCorrelation() (*stats.RiskCorrelationReport, error) {
+	log.Println("API: GetRiskOutcomeCorrelation called")
+	assessments, err := a.risks.GetAllRiskAssessments()
+	if err != nil {
+		log.Printf("ERROR: GetRiskOutcomeCorrelation failed: %v", err)
+		return nil, err
+	}
+	trades, positions, err := a.events.GetAllPositions()
+	if err != nil {
+		log.Printf("ERROR: GetRiskOutcomeCorrelation failed: %v", err)
+		return nil, err
+	}
+	result := stats.CorrelateRisk(assessments, trades, positions)
+	log.Printf("SUCCESS: GetRiskOutcomeCorrelation returned %d assessed days", len(result.Days))
+	return result, nil
+}
+
+// Validation API Methods
+
+// ValidateRiskAssessment returns the field errors of an assessment
+// without saving it (empty when valid)
+func (a *App) ValidateRiskAssessment(assessment models.RiskAssessment) []validation.FieldError {
+	return fieldErrors(assessment.Validate())
+}
+
+// ValidateStockRating returns the field errors of a stock rating
+// without saving it (empty when valid)
+func (a *App) ValidateStockRating(rating models.StockRating) []validation.FieldError {
+	return fieldErrors(rating.Validate())
+}
+
+// ValidateTrade returns the field errors of a trade without saving it
+// (empty when valid)
+func (a *App) ValidateTrade(trade models.Trade) []validation.FieldError {
+	if trade.ExpirationDate.IsZero() && len(trade.Legs) > 0 {
+		trade.ExpirationDate = trade.NearestExpiration()
+	}
+	return fieldErrors(trade.Validate())
+}
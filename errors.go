@@ -0,0 +1,25 @@
+package main
+
+import (
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/validation"
+)
+
+// formatError is the Wails error formatter. Every error returned by an
+// App method reaches the frontend as an apperror.Error: a stable code
+// (not_found, invalid, conflict, locked, internal), the message and,
+// for invalid input, the bad fields.
+func formatError(err error) any {
+	return apperror.From(err)
+}
+
+// fieldErrors returns the field errors of err as a list (empty if valid)
+func fieldErrors(err error) []validation.FieldError {
+	if err == nil {
+		return []validation.FieldError{}
+	}
+	if fields, ok := validation.Fields(err); ok {
+		return fields
+	}
+	return []validation.FieldError{{Code: validation.CodeInvalid, Message: err.Error()}}
+}
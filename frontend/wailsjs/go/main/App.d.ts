@@ -1,31 +1,142 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {time} from '../models';
+import {detect} from '../models';
+import {marketdata} from '../models';
+import {limits} from '../models';
+import {stats} from '../models';
+import {pnl} from '../models';
+import {sizing} from '../models';
+import {repositories} from '../models';
+import {validation} from '../models';
+
+export function AddWatchlistItem(arg1:string,arg2:models.WatchlistItem):Promise<models.Watchlist>;
+
+export function DeleteChartPattern(arg1:string):Promise<void>;
+
+export function DeletePriceBars(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteTickerSector(arg1:string):Promise<void>;
 
 export function DeleteTrade(arg1:string):Promise<void>;
 
+export function DeleteWatchlist(arg1:string):Promise<void>;
+
+export function DetectChartPatterns(arg1:string,arg2:time.Time):Promise<Array<detect.Detection>>;
+
+export function GetAllChartPatterns():Promise<Array<models.ChartPattern>>;
+
 export function GetAllRiskAssessments():Promise<Array<models.RiskAssessment>>;
 
+export function GetAllRiskScoringModels():Promise<Array<models.RiskScoringModel>>;
+
 export function GetAllStockRatings():Promise<Array<models.StockRating>>;
 
+export function GetAllTickerSectors():Promise<Array<models.TickerSector>>;
+
 export function GetAllTrades():Promise<Array<models.Trade>>;
 
+export function GetAllWatchlists():Promise<Array<models.Watchlist>>;
+
+export function GetChartPattern(arg1:string):Promise<models.ChartPattern>;
+
+export function GetLatestPriceBar(arg1:string,arg2:string,arg3:time.Time):Promise<marketdata.Bar>;
+
 export function GetLatestRiskAssessment():Promise<models.RiskAssessment>;
 
+export function GetLockOverrides():Promise<Array<models.LockOverride>>;
+
+export function GetLossLimitRules():Promise<limits.Rules>;
+
+export function GetMarketSnapshotForDay(arg1:string):Promise<models.MarketSnapshot>;
+
+export function GetMarketSnapshots(arg1:string,arg2:string):Promise<Array<models.MarketSnapshot>>;
+
+export function GetPerformanceStats():Promise<stats.Report>;
+
+export function GetPnlReport(arg1:Array<pnl.Mark>):Promise<pnl.Report>;
+
+export function GetPriceBars(arg1:string,arg2:string,arg3:time.Time,arg4:time.Time):Promise<Array<marketdata.Bar>>;
+
+export function GetPriceSeries():Promise<Array<marketdata.Series>>;
+
+export function GetRiskAssessmentHistory(arg1:string):Promise<Array<models.RiskAssessmentRevision>>;
+
+export function GetRiskOutcomeCorrelation():Promise<stats.RiskCorrelationReport>;
+
+export function GetRiskScoringModel():Promise<models.RiskScoringModel>;
+
+export function GetSizingRules():Promise<sizing.Rules>;
+
 export function GetStockRating(arg1:string):Promise<models.StockRating>;
 
+export function GetStockRatingHistory(arg1:string):Promise<Array<models.StockRatingRevision>>;
+
+export function GetStockRatingTrend(arg1:string,arg2:number):Promise<stats.RatingTrend>;
+
 export function GetStockRatingsByTicker(arg1:string):Promise<Array<models.StockRating>>;
 
+export function GetTickerSector(arg1:string):Promise<models.TickerSector>;
+
+export function GetTodayCheckInStatus():Promise<repositories.CheckInStatus>;
+
 export function GetTrade(arg1:string):Promise<models.Trade>;
 
+export function GetTradeEvents(arg1:string):Promise<Array<models.TradeEvent>>;
+
+export function GetTradePnl(arg1:string,arg2:Array<pnl.Mark>):Promise<pnl.TradePnl>;
+
+export function GetTradePosition(arg1:string):Promise<models.Position>;
+
 export function GetTradesByDateRange(arg1:string,arg2:string):Promise<Array<models.Trade>>;
 
 export function GetTradesByTicker(arg1:string):Promise<Array<models.Trade>>;
 
+export function GetTradingStatus():Promise<repositories.TradingStatus>;
+
 export function GetVersion():Promise<string>;
 
+export function GetWatchlist(arg1:string):Promise<models.Watchlist>;
+
+export function ImportPriceBars(arg1:string,arg2:string,arg3:string):Promise<marketdata.ImportResult>;
+
+export function ImportTickerSectors(arg1:string):Promise<number>;
+
+export function OverrideTradingLock(arg1:string):Promise<models.LockOverride>;
+
+export function RankTickers(arg1:models.RankQuery):Promise<Array<models.RankedTicker>>;
+
+export function RecommendPositionSize(arg1:number,arg2:number):Promise<sizing.Recommendation>;
+
+export function RecordTradeEvent(arg1:models.TradeEvent):Promise<models.TradeEvent>;
+
+export function RemoveWatchlistItem(arg1:string,arg2:string):Promise<models.Watchlist>;
+
+export function ReviewStockRatingPattern(arg1:models.StockRating):Promise<detect.Review>;
+
+export function SaveChartPattern(arg1:models.ChartPattern):Promise<models.ChartPattern>;
+
+export function SaveLossLimitRules(arg1:limits.Rules):Promise<limits.Rules>;
+
+export function SaveMarketSnapshot(arg1:models.MarketSnapshot):Promise<models.MarketSnapshot>;
+
 export function SaveRiskAssessment(arg1:models.RiskAssessment):Promise<models.RiskAssessment>;
 
+export function SaveRiskScoringModel(arg1:models.RiskScoringModel):Promise<models.RiskScoringModel>;
+
+export function SaveSizingRules(arg1:sizing.Rules):Promise<sizing.Rules>;
+
 export function SaveStockRating(arg1:models.StockRating):Promise<models.StockRating>;
 
 export function SaveTrade(arg1:models.Trade):Promise<models.Trade>;
+
+export function SaveWatchlist(arg1:models.Watchlist):Promise<models.Watchlist>;
+
+export function SetTickerSector(arg1:string,arg2:string):Promise<models.TickerSector>;
+
+export function ValidateRiskAssessment(arg1:models.RiskAssessment):Promise<Array<validation.FieldError>>;
+
+export function ValidateStockRating(arg1:models.StockRating):Promise<Array<validation.FieldError>>;
+
+export function ValidateTrade(arg1:models.Trade):Promise<Array<validation.FieldError>>;
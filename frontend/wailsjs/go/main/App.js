@@ -2,38 +2,162 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddWatchlistItem(arg1, arg2) {
+  return window['go']['main']['App']['AddWatchlistItem'](arg1, arg2);
+}
+
+export function DeleteChartPattern(arg1) {
+  return window['go']['main']['App']['DeleteChartPattern'](arg1);
+}
+
+export function DeletePriceBars(arg1, arg2) {
+  return window['go']['main']['App']['DeletePriceBars'](arg1, arg2);
+}
+
+export function DeleteTickerSector(arg1) {
+  return window['go']['main']['App']['DeleteTickerSector'](arg1);
+}
+
 export function DeleteTrade(arg1) {
   return window['go']['main']['App']['DeleteTrade'](arg1);
 }
 
+export function DeleteWatchlist(arg1) {
+  return window['go']['main']['App']['DeleteWatchlist'](arg1);
+}
+
+export function DetectChartPatterns(arg1, arg2) {
+  return window['go']['main']['App']['DetectChartPatterns'](arg1, arg2);
+}
+
+export function GetAllChartPatterns() {
+  return window['go']['main']['App']['GetAllChartPatterns']();
+}
+
 export function GetAllRiskAssessments() {
   return window['go']['main']['App']['GetAllRiskAssessments']();
 }
 
+export function GetAllRiskScoringModels() {
+  return window['go']['main']['App']['GetAllRiskScoringModels']();
+}
+
 export function GetAllStockRatings() {
   return window['go']['main']['App']['GetAllStockRatings']();
 }
 
+export function GetAllTickerSectors() {
+  return window['go']['main']['App']['GetAllTickerSectors']();
+}
+
 export function GetAllTrades() {
   return window['go']['main']['App']['GetAllTrades']();
 }
 
+export function GetAllWatchlists() {
+  return window['go']['main']['App']['GetAllWatchlists']();
+}
+
+export function GetChartPattern(arg1) {
+  return window['go']['main']['App']['GetChartPattern'](arg1);
+}
+
+export function GetLatestPriceBar(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetLatestPriceBar'](arg1, arg2, arg3);
+}
+
 export function GetLatestRiskAssessment() {
   return window['go']['main']['App']['GetLatestRiskAssessment']();
 }
 
+export function GetLockOverrides() {
+  return window['go']['main']['App']['GetLockOverrides']();
+}
+
+export function GetLossLimitRules() {
+  return window['go']['main']['App']['GetLossLimitRules']();
+}
+
+export function GetMarketSnapshotForDay(arg1) {
+  return window['go']['main']['App']['GetMarketSnapshotForDay'](arg1);
+}
+
+export function GetMarketSnapshots(arg1, arg2) {
+  return window['go']['main']['App']['GetMarketSnapshots'](arg1, arg2);
+}
+
+export function GetPerformanceStats() {
+  return window['go']['main']['App']['GetPerformanceStats']();
+}
+
+export function GetPnlReport(arg1) {
+  return window['go']['main']['App']['GetPnlReport'](arg1);
+}
+
+export function GetPriceBars(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetPriceBars'](arg1, arg2, arg3, arg4);
+}
+
+export function GetPriceSeries() {
+  return window['go']['main']['App']['GetPriceSeries']();
+}
+
+export function GetRiskAssessmentHistory(arg1) {
+  return window['go']['main']['App']['GetRiskAssessmentHistory'](arg1);
+}
+
+export function GetRiskOutcomeCorrelation() {
+  return window['go']['main']['App']['GetRiskOutcomeCorrelation']();
+}
+
+export function GetRiskScoringModel() {
+  return window['go']['main']['App']['GetRiskScoringModel']();
+}
+
+export function GetSizingRules() {
+  return window['go']['main']['App']['GetSizingRules']();
+}
+
 export function GetStockRating(arg1) {
   return window['go']['main']['App']['GetStockRating'](arg1);
 }
 
+export function GetStockRatingHistory(arg1) {
+  return window['go']['main']['App']['GetStockRatingHistory'](arg1);
+}
+
+export function GetStockRatingTrend(arg1, arg2) {
+  return window['go']['main']['App']['GetStockRatingTrend'](arg1, arg2);
+}
+
 export function GetStockRatingsByTicker(arg1) {
   return window['go']['main']['App']['GetStockRatingsByTicker'](arg1);
 }
 
+export function GetTickerSector(arg1) {
+  return window['go']['main']['App']['GetTickerSector'](arg1);
+}
+
+export function GetTodayCheckInStatus() {
+  return window['go']['main']['App']['GetTodayCheckInStatus']();
+}
+
 export function GetTrade(arg1) {
   return window['go']['main']['App']['GetTrade'](arg1);
 }
 
+export function GetTradeEvents(arg1) {
+  return window['go']['main']['App']['GetTradeEvents'](arg1);
+}
+
+export function GetTradePnl(arg1, arg2) {
+  return window['go']['main']['App']['GetTradePnl'](arg1, arg2);
+}
+
+export function GetTradePosition(arg1) {
+  return window['go']['main']['App']['GetTradePosition'](arg1);
+}
+
 export function GetTradesByDateRange(arg1, arg2) {
   return window['go']['main']['App']['GetTradesByDateRange'](arg1, arg2);
 }
@@ -42,14 +166,74 @@ export function GetTradesByTicker(arg1) {
   return window['go']['main']['App']['GetTradesByTicker'](arg1);
 }
 
+export function GetTradingStatus() {
+  return window['go']['main']['App']['GetTradingStatus']();
+}
+
 export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
 
+export function GetWatchlist(arg1) {
+  return window['go']['main']['App']['GetWatchlist'](arg1);
+}
+
+export function ImportPriceBars(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportPriceBars'](arg1, arg2, arg3);
+}
+
+export function ImportTickerSectors(arg1) {
+  return window['go']['main']['App']['ImportTickerSectors'](arg1);
+}
+
+export function OverrideTradingLock(arg1) {
+  return window['go']['main']['App']['OverrideTradingLock'](arg1);
+}
+
+export function RankTickers(arg1) {
+  return window['go']['main']['App']['RankTickers'](arg1);
+}
+
+export function RecommendPositionSize(arg1, arg2) {
+  return window['go']['main']['App']['RecommendPositionSize'](arg1, arg2);
+}
+
+export function RecordTradeEvent(arg1) {
+  return window['go']['main']['App']['RecordTradeEvent'](arg1);
+}
+
+export function RemoveWatchlistItem(arg1, arg2) {
+  return window['go']['main']['App']['RemoveWatchlistItem'](arg1, arg2);
+}
+
+export function ReviewStockRatingPattern(arg1) {
+  return window['go']['main']['App']['ReviewStockRatingPattern'](arg1);
+}
+
+export function SaveChartPattern(arg1) {
+  return window['go']['main']['App']['SaveChartPattern'](arg1);
+}
+
+export function SaveLossLimitRules(arg1) {
+  return window['go']['main']['App']['SaveLossLimitRules'](arg1);
+}
+
+export function SaveMarketSnapshot(arg1) {
+  return window['go']['main']['App']['SaveMarketSnapshot'](arg1);
+}
+
 export function SaveRiskAssessment(arg1) {
   return window['go']['main']['App']['SaveRiskAssessment'](arg1);
 }
 
+export function SaveRiskScoringModel(arg1) {
+  return window['go']['main']['App']['SaveRiskScoringModel'](arg1);
+}
+
+export function SaveSizingRules(arg1) {
+  return window['go']['main']['App']['SaveSizingRules'](arg1);
+}
+
 export function SaveStockRating(arg1) {
   return window['go']['main']['App']['SaveStockRating'](arg1);
 }
@@ -57,3 +241,23 @@ export function SaveStockRating(arg1) {
 export function SaveTrade(arg1) {
   return window['go']['main']['App']['SaveTrade'](arg1);
 }
+
+export function SaveWatchlist(arg1) {
+  return window['go']['main']['App']['SaveWatchlist'](arg1);
+}
+
+export function SetTickerSector(arg1, arg2) {
+  return window['go']['main']['App']['SetTickerSector'](arg1, arg2);
+}
+
+export function ValidateRiskAssessment(arg1) {
+  return window['go']['main']['App']['ValidateRiskAssessment'](arg1);
+}
+
+export function ValidateStockRating(arg1) {
+  return window['go']['main']['App']['ValidateStockRating'](arg1);
+}
+
+export function ValidateTrade(arg1) {
+  return window['go']['main']['App']['ValidateTrade'](arg1);
+}
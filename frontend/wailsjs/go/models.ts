@@ -1,30 +1,1498 @@
+export namespace detect {
+	
+	export class Level {
+	    name: string;
+	    price: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Level(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.price = source["price"];
+	    }
+	}
+	export class Detection {
+	    pattern: string;
+	    bias: string;
+	    confidence: number;
+	    start: time.Time;
+	    end: time.Time;
+	    levels: Level[];
+	    summary: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Detection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pattern = source["pattern"];
+	        this.bias = source["bias"];
+	        this.confidence = source["confidence"];
+	        this.start = this.convertValues(source["start"], time.Time);
+	        this.end = this.convertValues(source["end"], time.Time);
+	        this.levels = this.convertValues(source["levels"], Level);
+	        this.summary = source["summary"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Review {
+	    pattern: string;
+	    supported: boolean;
+	    detection?: Detection;
+	    suggestions: Detection[];
+	    bars: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Review(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pattern = source["pattern"];
+	        this.supported = source["supported"];
+	        this.detection = this.convertValues(source["detection"], Detection);
+	        this.suggestions = this.convertValues(source["suggestions"], Detection);
+	        this.bars = source["bars"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace limits {
+	
+	export class Rules {
+	    dailyLossLimit: number;
+	    weeklyLossLimit: number;
+	    riskScoreCheck: boolean;
+	    minRiskScore: number;
+	    requireCheckIn: boolean;
+	    cooldownMinutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dailyLossLimit = source["dailyLossLimit"];
+	        this.weeklyLossLimit = source["weeklyLossLimit"];
+	        this.riskScoreCheck = source["riskScoreCheck"];
+	        this.minRiskScore = source["minRiskScore"];
+	        this.requireCheckIn = source["requireCheckIn"];
+	        this.cooldownMinutes = source["cooldownMinutes"];
+	    }
+	}
+
+}
+
+export namespace marketdata {
+	
+	export class Bar {
+	    ticker: string;
+	    interval: string;
+	    time: time.Time;
+	    open: number;
+	    high: number;
+	    low: number;
+	    close: number;
+	    volume: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Bar(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ticker = source["ticker"];
+	        this.interval = source["interval"];
+	        this.time = this.convertValues(source["time"], time.Time);
+	        this.open = source["open"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.close = source["close"];
+	        this.volume = source["volume"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportResult {
+	    interval: string;
+	    tickers: string[];
+	    imported: number;
+	    skipped: number;
+	    first: time.Time;
+	    last: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.interval = source["interval"];
+	        this.tickers = source["tickers"];
+	        this.imported = source["imported"];
+	        this.skipped = source["skipped"];
+	        this.first = this.convertValues(source["first"], time.Time);
+	        this.last = this.convertValues(source["last"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Series {
+	    ticker: string;
+	    interval: string;
+	    bars: number;
+	    first: time.Time;
+	    last: time.Time;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Series(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ticker = source["ticker"];
+	        this.interval = source["interval"];
+	        this.bars = source["bars"];
+	        this.first = this.convertValues(source["first"], time.Time);
+	        this.last = this.convertValues(source["last"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace models {
 	
-	export class RiskAssessment {
-	    id: string;
-	    // Go type: time
-	    date: any;
-	    emotional: number;
-	    fomo: number;
-	    bias: number;
-	    physical: number;
-	    pnl: number;
-	    overallScore: number;
+	export class ChartPattern {
+	    id: string;
+	    name: string;
+	    bias: string;
+	    points: number;
+	    description: string;
+	    active: boolean;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChartPattern(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.bias = source["bias"];
+	        this.points = source["points"];
+	        this.description = source["description"];
+	        this.active = source["active"];
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EnthusiasmComponent {
+	    name: string;
+	    points: number;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnthusiasmComponent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.points = source["points"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class EntryContext {
+	    linkedAt: time.Time;
+	    stockRatingId: string;
+	    ratingDate: string;
+	    enthusiasmRating: number;
+	    riskAssessmentId: string;
+	    riskScore: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EntryContext(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.linkedAt = this.convertValues(source["linkedAt"], time.Time);
+	        this.stockRatingId = source["stockRatingId"];
+	        this.ratingDate = source["ratingDate"];
+	        this.enthusiasmRating = source["enthusiasmRating"];
+	        this.riskAssessmentId = source["riskAssessmentId"];
+	        this.riskScore = source["riskScore"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Leg {
+	    optionType: string;
+	    strike: number;
+	    expiration: time.Time;
+	    quantity: number;
+	    side: string;
+	    fillPrice: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Leg(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.optionType = source["optionType"];
+	        this.strike = source["strike"];
+	        this.expiration = this.convertValues(source["expiration"], time.Time);
+	        this.quantity = source["quantity"];
+	        this.side = source["side"];
+	        this.fillPrice = source["fillPrice"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LockOverride {
+	    id: string;
+	    date: time.Time;
+	    reason: string;
+	    lockIds: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LockOverride(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.reason = source["reason"];
+	        this.lockIds = source["lockIds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MarketSnapshot {
+	    id: string;
+	    date: time.Time;
+	    marketSentiment: number;
+	    basicMaterials: number;
+	    communicationServices: number;
+	    consumerCyclical: number;
+	    consumerDefensive: number;
+	    energy: number;
+	    financial: number;
+	    healthcare: number;
+	    industrials: number;
+	    realEstate: number;
+	    technology: number;
+	    utilities: number;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new MarketSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.marketSentiment = source["marketSentiment"];
+	        this.basicMaterials = source["basicMaterials"];
+	        this.communicationServices = source["communicationServices"];
+	        this.consumerCyclical = source["consumerCyclical"];
+	        this.consumerDefensive = source["consumerDefensive"];
+	        this.energy = source["energy"];
+	        this.financial = source["financial"];
+	        this.healthcare = source["healthcare"];
+	        this.industrials = source["industrials"];
+	        this.realEstate = source["realEstate"];
+	        this.technology = source["technology"];
+	        this.utilities = source["utilities"];
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Realization {
+	    date: time.Time;
+	    eventType: string;
+	    gross: number;
+	    costs: number;
+	    net: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Realization(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.eventType = source["eventType"];
+	        this.gross = source["gross"];
+	        this.costs = source["costs"];
+	        this.net = source["net"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Position {
+	    tradeId: string;
+	    status: string;
+	    openLegs: Leg[];
+	    multiplier: number;
+	    realizedPnl: number;
+	    costs: number;
+	    openedAt: time.Time;
+	    closedAt: time.Time;
+	    holdingDays: number;
+	    eventCount: number;
+	    realizations: Realization[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Position(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tradeId = source["tradeId"];
+	        this.status = source["status"];
+	        this.openLegs = this.convertValues(source["openLegs"], Leg);
+	        this.multiplier = source["multiplier"];
+	        this.realizedPnl = source["realizedPnl"];
+	        this.costs = source["costs"];
+	        this.openedAt = this.convertValues(source["openedAt"], time.Time);
+	        this.closedAt = this.convertValues(source["closedAt"], time.Time);
+	        this.holdingDays = source["holdingDays"];
+	        this.eventCount = source["eventCount"];
+	        this.realizations = this.convertValues(source["realizations"], Realization);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RankQuery {
+	    sector: string;
+	    pattern: string;
+	    watchlistId: string;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RankQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sector = source["sector"];
+	        this.pattern = source["pattern"];
+	        this.watchlistId = source["watchlistId"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class WatchlistItem {
+	    ticker: string;
+	    targetEntry: number;
+	    invalidationLevel: number;
+	    notes: string;
+	    addedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ticker = source["ticker"];
+	        this.targetEntry = source["targetEntry"];
+	        this.invalidationLevel = source["invalidationLevel"];
+	        this.notes = source["notes"];
+	        this.addedAt = this.convertValues(source["addedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StockRating {
+	    id: string;
+	    date: time.Time;
+	    ticker: string;
+	    snapshotId: string;
+	    marketSentiment: number;
+	    basicMaterials: number;
+	    communicationServices: number;
+	    consumerCyclical: number;
+	    consumerDefensive: number;
+	    energy: number;
+	    financial: number;
+	    healthcare: number;
+	    industrials: number;
+	    realEstate: number;
+	    technology: number;
+	    utilities: number;
+	    stockSentiment: number;
+	    pattern: string;
+	    patternId: string;
+	    patternPoints: number;
+	    patternBias: string;
+	    direction: string;
+	    sector: string;
+	    enthusiasmRating: number;
+	    enthusiasmBreakdown: EnthusiasmComponent[];
+	    enthusiasmVersion: string;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockRating(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.ticker = source["ticker"];
+	        this.snapshotId = source["snapshotId"];
+	        this.marketSentiment = source["marketSentiment"];
+	        this.basicMaterials = source["basicMaterials"];
+	        this.communicationServices = source["communicationServices"];
+	        this.consumerCyclical = source["consumerCyclical"];
+	        this.consumerDefensive = source["consumerDefensive"];
+	        this.energy = source["energy"];
+	        this.financial = source["financial"];
+	        this.healthcare = source["healthcare"];
+	        this.industrials = source["industrials"];
+	        this.realEstate = source["realEstate"];
+	        this.technology = source["technology"];
+	        this.utilities = source["utilities"];
+	        this.stockSentiment = source["stockSentiment"];
+	        this.pattern = source["pattern"];
+	        this.patternId = source["patternId"];
+	        this.patternPoints = source["patternPoints"];
+	        this.patternBias = source["patternBias"];
+	        this.direction = source["direction"];
+	        this.sector = source["sector"];
+	        this.enthusiasmRating = source["enthusiasmRating"];
+	        this.enthusiasmBreakdown = this.convertValues(source["enthusiasmBreakdown"], EnthusiasmComponent);
+	        this.enthusiasmVersion = source["enthusiasmVersion"];
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RankedTicker {
+	    rank: number;
+	    ticker: string;
+	    rating?: StockRating;
+	    item?: WatchlistItem;
+	
+	    static createFrom(source: any = {}) {
+	        return new RankedTicker(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rank = source["rank"];
+	        this.ticker = source["ticker"];
+	        this.rating = this.convertValues(source["rating"], StockRating);
+	        this.item = this.convertValues(source["item"], WatchlistItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RiskAssessment {
+	    id: string;
+	    date: time.Time;
+	    emotional: number;
+	    fomo: number;
+	    bias: number;
+	    physical: number;
+	    pnl: number;
+	    overallScore: number;
+	    scoringVersion: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskAssessment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.emotional = source["emotional"];
+	        this.fomo = source["fomo"];
+	        this.bias = source["bias"];
+	        this.physical = source["physical"];
+	        this.pnl = source["pnl"];
+	        this.overallScore = source["overallScore"];
+	        this.scoringVersion = source["scoringVersion"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RiskAssessmentRevision {
+	    id: string;
+	    assessmentId: string;
+	    editedAt: time.Time;
+	    previous: RiskAssessment;
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskAssessmentRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.assessmentId = source["assessmentId"];
+	        this.editedAt = this.convertValues(source["editedAt"], time.Time);
+	        this.previous = this.convertValues(source["previous"], RiskAssessment);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RiskPenalty {
+	    factor: string;
+	    atOrBelow: number;
+	    cap: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskPenalty(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.factor = source["factor"];
+	        this.atOrBelow = source["atOrBelow"];
+	        this.cap = source["cap"];
+	    }
+	}
+	export class RiskScoringModel {
+	    version: string;
+	    weights: Record<string, number>;
+	    penalties: RiskPenalty[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskScoringModel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.weights = source["weights"];
+	        this.penalties = this.convertValues(source["penalties"], RiskPenalty);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SizeCheck {
+	    checkedAt: time.Time;
+	    units: number;
+	    recommendedUnits: number;
+	    exceeded: boolean;
+	    reasons: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checkedAt = this.convertValues(source["checkedAt"], time.Time);
+	        this.units = source["units"];
+	        this.recommendedUnits = source["recommendedUnits"];
+	        this.exceeded = source["exceeded"];
+	        this.reasons = source["reasons"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StockRatingRevision {
+	    id: string;
+	    ratingId: string;
+	    editedAt: time.Time;
+	    previous: StockRating;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockRatingRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.ratingId = source["ratingId"];
+	        this.editedAt = this.convertValues(source["editedAt"], time.Time);
+	        this.previous = this.convertValues(source["previous"], StockRating);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TickerSector {
+	    ticker: string;
+	    sector: string;
+	    builtIn: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TickerSector(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ticker = source["ticker"];
+	        this.sector = source["sector"];
+	        this.builtIn = source["builtIn"];
+	    }
+	}
+	export class Trade {
+	    id: string;
+	    entryDate: time.Time;
+	    ticker: string;
+	    sector: string;
+	    entryPrice: number;
+	    notes: string;
+	    expirationDate: time.Time;
+	    strategyType: string;
+	    spreadType: string;
+	    direction: string;
+	    legs: Leg[];
+	    multiplier: number;
+	    commission: number;
+	    fees: number;
+	    maxLoss: number;
+	    sizeCheck?: SizeCheck;
+	    entryContext?: EntryContext;
+	
+	    static createFrom(source: any = {}) {
+	        return new Trade(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.entryDate = this.convertValues(source["entryDate"], time.Time);
+	        this.ticker = source["ticker"];
+	        this.sector = source["sector"];
+	        this.entryPrice = source["entryPrice"];
+	        this.notes = source["notes"];
+	        this.expirationDate = this.convertValues(source["expirationDate"], time.Time);
+	        this.strategyType = source["strategyType"];
+	        this.spreadType = source["spreadType"];
+	        this.direction = source["direction"];
+	        this.legs = this.convertValues(source["legs"], Leg);
+	        this.multiplier = source["multiplier"];
+	        this.commission = source["commission"];
+	        this.fees = source["fees"];
+	        this.maxLoss = source["maxLoss"];
+	        this.sizeCheck = this.convertValues(source["sizeCheck"], SizeCheck);
+	        this.entryContext = this.convertValues(source["entryContext"], EntryContext);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TradeEvent {
+	    id: string;
+	    tradeId: string;
+	    type: string;
+	    date: time.Time;
+	    fills: Leg[];
+	    underlyingPrice: number;
+	    commission: number;
+	    fees: number;
+	    notes: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradeEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.tradeId = source["tradeId"];
+	        this.type = source["type"];
+	        this.date = this.convertValues(source["date"], time.Time);
+	        this.fills = this.convertValues(source["fills"], Leg);
+	        this.underlyingPrice = source["underlyingPrice"];
+	        this.commission = source["commission"];
+	        this.fees = source["fees"];
+	        this.notes = source["notes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TradingLock {
+	    id: string;
+	    rule: string;
+	    period: string;
+	    reason: string;
+	    trippedAt: time.Time;
+	    until: time.Time;
+	    loss: number;
+	    overrideId: string;
+	    lossAtOverride: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradingLock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.rule = source["rule"];
+	        this.period = source["period"];
+	        this.reason = source["reason"];
+	        this.trippedAt = this.convertValues(source["trippedAt"], time.Time);
+	        this.until = this.convertValues(source["until"], time.Time);
+	        this.loss = source["loss"];
+	        this.overrideId = source["overrideId"];
+	        this.lossAtOverride = source["lossAtOverride"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Watchlist {
+	    id: string;
+	    name: string;
+	    items: WatchlistItem[];
+	    notes: string;
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Watchlist(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.items = this.convertValues(source["items"], WatchlistItem);
+	        this.notes = source["notes"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace pnl {
+	
+	export class DailyPnl {
+	    date: string;
+	    gross: number;
+	    costs: number;
+	    realized: number;
+	    trades: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyPnl(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.gross = source["gross"];
+	        this.costs = source["costs"];
+	        this.realized = source["realized"];
+	        this.trades = source["trades"];
+	    }
+	}
+	export class Mark {
+	    tradeId: string;
+	    optionType: string;
+	    strike: number;
+	    expiration: time.Time;
+	    price: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Mark(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tradeId = source["tradeId"];
+	        this.optionType = source["optionType"];
+	        this.strike = source["strike"];
+	        this.expiration = this.convertValues(source["expiration"], time.Time);
+	        this.price = source["price"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StrategyPnl {
+	    strategyType: string;
+	    trades: number;
+	    closed: number;
+	    realized: number;
+	    costs: number;
+	    unrealized: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StrategyPnl(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.strategyType = source["strategyType"];
+	        this.trades = source["trades"];
+	        this.closed = source["closed"];
+	        this.realized = source["realized"];
+	        this.costs = source["costs"];
+	        this.unrealized = source["unrealized"];
+	        this.total = source["total"];
+	    }
+	}
+	export class TradePnl {
+	    tradeId: string;
+	    ticker: string;
+	    strategyType: string;
+	    status: string;
+	    multiplier: number;
+	    realized: number;
+	    costs: number;
+	    unrealized: number;
+	    marked: boolean;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradePnl(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tradeId = source["tradeId"];
+	        this.ticker = source["ticker"];
+	        this.strategyType = source["strategyType"];
+	        this.status = source["status"];
+	        this.multiplier = source["multiplier"];
+	        this.realized = source["realized"];
+	        this.costs = source["costs"];
+	        this.unrealized = source["unrealized"];
+	        this.marked = source["marked"];
+	        this.total = source["total"];
+	    }
+	}
+	export class Report {
+	    trades: TradePnl[];
+	    daily: DailyPnl[];
+	    byStrategy: StrategyPnl[];
+	    totalRealized: number;
+	    totalCosts: number;
+	    totalUnrealized: number;
+	    unmarked: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trades = this.convertValues(source["trades"], TradePnl);
+	        this.daily = this.convertValues(source["daily"], DailyPnl);
+	        this.byStrategy = this.convertValues(source["byStrategy"], StrategyPnl);
+	        this.totalRealized = source["totalRealized"];
+	        this.totalCosts = source["totalCosts"];
+	        this.totalUnrealized = source["totalUnrealized"];
+	        this.unmarked = source["unmarked"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace repositories {
+	
+	export class CheckInStatus {
+	    date: string;
+	    missing: boolean;
+	    assessment?: models.RiskAssessment;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckInStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.missing = source["missing"];
+	        this.assessment = this.convertValues(source["assessment"], models.RiskAssessment);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TradingStatus {
+	    locked: boolean;
+	    locks: models.TradingLock[];
+	    until: time.Time;
+	    realizedToday: number;
+	    realizedWeek: number;
+	    checkInRequired: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradingStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.locked = source["locked"];
+	        this.locks = this.convertValues(source["locks"], models.TradingLock);
+	        this.until = this.convertValues(source["until"], time.Time);
+	        this.realizedToday = source["realizedToday"];
+	        this.realizedWeek = source["realizedWeek"];
+	        this.checkInRequired = source["checkInRequired"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace sizing {
+	
+	export class Recommendation {
+	    units: number;
+	    riskBudget: number;
+	    riskPercent: number;
+	    scoreScale: number;
+	    score: number;
+	    assessmentId: string;
+	    method: string;
+	    reasons: string[];
+	    maxLossPerUnit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Recommendation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.units = source["units"];
+	        this.riskBudget = source["riskBudget"];
+	        this.riskPercent = source["riskPercent"];
+	        this.scoreScale = source["scoreScale"];
+	        this.score = source["score"];
+	        this.assessmentId = source["assessmentId"];
+	        this.method = source["method"];
+	        this.reasons = source["reasons"];
+	        this.maxLossPerUnit = source["maxLossPerUnit"];
+	    }
+	}
+	export class Rules {
+	    accountEquity: number;
+	    method: string;
+	    riskPercent: number;
+	    kellyWinRate: number;
+	    kellyPayoffRatio: number;
+	    kellyFraction: number;
+	    targetVolatility: number;
+	    minScoreScale: number;
+	    enforcement: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.accountEquity = source["accountEquity"];
+	        this.method = source["method"];
+	        this.riskPercent = source["riskPercent"];
+	        this.kellyWinRate = source["kellyWinRate"];
+	        this.kellyPayoffRatio = source["kellyPayoffRatio"];
+	        this.kellyFraction = source["kellyFraction"];
+	        this.targetVolatility = source["targetVolatility"];
+	        this.minScoreScale = source["minScoreScale"];
+	        this.enforcement = source["enforcement"];
+	    }
+	}
+
+}
+
+export namespace stats {
+	
+	export class AssessedDay {
+	    date: string;
+	    assessment?: models.RiskAssessment;
+	    trades: number;
+	    wins: number;
+	    netPnl: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new RiskAssessment(source);
+	        return new AssessedDay(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.date = this.convertValues(source["date"], null);
-	        this.emotional = source["emotional"];
-	        this.fomo = source["fomo"];
-	        this.bias = source["bias"];
-	        this.physical = source["physical"];
-	        this.pnl = source["pnl"];
-	        this.overallScore = source["overallScore"];
+	        this.date = source["date"];
+	        this.assessment = this.convertValues(source["assessment"], models.RiskAssessment);
+	        this.trades = source["trades"];
+	        this.wins = source["wins"];
+	        this.netPnl = source["netPnl"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -45,51 +1513,162 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class StockRating {
-	    id: string;
-	    // Go type: time
-	    date: any;
-	    ticker: string;
-	    marketSentiment: number;
-	    basicMaterials: number;
-	    communicationServices: number;
-	    consumerCyclical: number;
-	    consumerDefensive: number;
-	    energy: number;
-	    financial: number;
-	    healthcare: number;
-	    industrials: number;
-	    realEstate: number;
-	    technology: number;
-	    utilities: number;
+	export class ScoreBucket {
+	    score: number;
+	    days: number;
+	    trades: number;
+	    winRate: number;
+	    totalPnl: number;
+	    averagePnl: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScoreBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.score = source["score"];
+	        this.days = source["days"];
+	        this.trades = source["trades"];
+	        this.winRate = source["winRate"];
+	        this.totalPnl = source["totalPnl"];
+	        this.averagePnl = source["averagePnl"];
+	    }
+	}
+	export class FactorCorrelation {
+	    factor: string;
+	    correlation: number;
+	    samples: number;
+	    buckets: ScoreBucket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FactorCorrelation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.factor = source["factor"];
+	        this.correlation = source["correlation"];
+	        this.samples = source["samples"];
+	        this.buckets = this.convertValues(source["buckets"], ScoreBucket);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Summary {
+	    trades: number;
+	    wins: number;
+	    losses: number;
+	    winRate: number;
+	    netPnl: number;
+	    averageWin: number;
+	    averageLoss: number;
+	    expectancy: number;
+	    profitFactor: number;
+	    maxDrawdown: number;
+	    longestLosingStreak: number;
+	    tradingDays: number;
+	    sharpeRatio: number;
+	    sortinoRatio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Summary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trades = source["trades"];
+	        this.wins = source["wins"];
+	        this.losses = source["losses"];
+	        this.winRate = source["winRate"];
+	        this.netPnl = source["netPnl"];
+	        this.averageWin = source["averageWin"];
+	        this.averageLoss = source["averageLoss"];
+	        this.expectancy = source["expectancy"];
+	        this.profitFactor = source["profitFactor"];
+	        this.maxDrawdown = source["maxDrawdown"];
+	        this.longestLosingStreak = source["longestLosingStreak"];
+	        this.tradingDays = source["tradingDays"];
+	        this.sharpeRatio = source["sharpeRatio"];
+	        this.sortinoRatio = source["sortinoRatio"];
+	    }
+	}
+	export class Group {
+	    key: string;
+	    summary: Summary;
+	
+	    static createFrom(source: any = {}) {
+	        return new Group(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.summary = this.convertValues(source["summary"], Summary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RatingPoint {
+	    savedAt: time.Time;
+	    date: string;
+	    ratingId: string;
+	    current: boolean;
+	    enthusiasmRating: number;
 	    stockSentiment: number;
+	    sectorSentiment: number;
+	    marketSentiment: number;
 	    pattern: string;
-	    enthusiasmRating: number;
+	    direction: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new StockRating(source);
+	        return new RatingPoint(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.date = this.convertValues(source["date"], null);
-	        this.ticker = source["ticker"];
-	        this.marketSentiment = source["marketSentiment"];
-	        this.basicMaterials = source["basicMaterials"];
-	        this.communicationServices = source["communicationServices"];
-	        this.consumerCyclical = source["consumerCyclical"];
-	        this.consumerDefensive = source["consumerDefensive"];
-	        this.energy = source["energy"];
-	        this.financial = source["financial"];
-	        this.healthcare = source["healthcare"];
-	        this.industrials = source["industrials"];
-	        this.realEstate = source["realEstate"];
-	        this.technology = source["technology"];
-	        this.utilities = source["utilities"];
+	        this.savedAt = this.convertValues(source["savedAt"], time.Time);
+	        this.date = source["date"];
+	        this.ratingId = source["ratingId"];
+	        this.current = source["current"];
+	        this.enthusiasmRating = source["enthusiasmRating"];
 	        this.stockSentiment = source["stockSentiment"];
+	        this.sectorSentiment = source["sectorSentiment"];
+	        this.marketSentiment = source["marketSentiment"];
 	        this.pattern = source["pattern"];
-	        this.enthusiasmRating = source["enthusiasmRating"];
+	        this.direction = source["direction"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -110,36 +1689,142 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class Trade {
-	    id: string;
-	    // Go type: time
-	    entryDate: any;
+	export class ThresholdCrossing {
+	    savedAt: time.Time;
+	    date: string;
+	    ratingId: string;
+	    from: number;
+	    to: number;
+	    crossed: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ThresholdCrossing(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.savedAt = this.convertValues(source["savedAt"], time.Time);
+	        this.date = source["date"];
+	        this.ratingId = source["ratingId"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.crossed = source["crossed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RatingTrend {
 	    ticker: string;
-	    sector: string;
-	    entryPrice: number;
-	    notes: string;
-	    // Go type: time
-	    expirationDate: any;
-	    strategyType: string;
-	    spreadType: string;
-	    direction: string;
+	    threshold: number;
+	    points: RatingPoint[];
+	    crossings: ThresholdCrossing[];
+	    above: boolean;
+	    latestCrossing?: ThresholdCrossing;
 	
 	    static createFrom(source: any = {}) {
-	        return new Trade(source);
+	        return new RatingTrend(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.entryDate = this.convertValues(source["entryDate"], null);
 	        this.ticker = source["ticker"];
-	        this.sector = source["sector"];
-	        this.entryPrice = source["entryPrice"];
-	        this.notes = source["notes"];
-	        this.expirationDate = this.convertValues(source["expirationDate"], null);
-	        this.strategyType = source["strategyType"];
-	        this.spreadType = source["spreadType"];
-	        this.direction = source["direction"];
+	        this.threshold = source["threshold"];
+	        this.points = this.convertValues(source["points"], RatingPoint);
+	        this.crossings = this.convertValues(source["crossings"], ThresholdCrossing);
+	        this.above = source["above"];
+	        this.latestCrossing = this.convertValues(source["latestCrossing"], ThresholdCrossing);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    overall: Summary;
+	    byStrategy: Group[];
+	    byDirection: Group[];
+	    bySector: Group[];
+	    byTicker: Group[];
+	    byEnthusiasm: Group[];
+	    byRiskScore: Group[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.overall = this.convertValues(source["overall"], Summary);
+	        this.byStrategy = this.convertValues(source["byStrategy"], Group);
+	        this.byDirection = this.convertValues(source["byDirection"], Group);
+	        this.bySector = this.convertValues(source["bySector"], Group);
+	        this.byTicker = this.convertValues(source["byTicker"], Group);
+	        this.byEnthusiasm = this.convertValues(source["byEnthusiasm"], Group);
+	        this.byRiskScore = this.convertValues(source["byRiskScore"], Group);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RiskCorrelationReport {
+	    days: AssessedDay[];
+	    factors: FactorCorrelation[];
+	    unassessedTrades: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskCorrelationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.days = this.convertValues(source["days"], AssessedDay);
+	        this.factors = this.convertValues(source["factors"], FactorCorrelation);
+	        this.unassessedTrades = source["unassessedTrades"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -160,6 +1845,46 @@ export namespace models {
 		    return a;
 		}
 	}
+	
+	
+
+}
+
+export namespace time {
+	
+	export class Time {
+	
+	
+	    static createFrom(source: any = {}) {
+	        return new Time(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	
+	    }
+	}
+
+}
+
+export namespace validation {
+	
+	export class FieldError {
+	    field: string;
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
 
 }
 
@@ -0,0 +1,100 @@
+// Package apperror gives errors returned through the Wails API a stable
+// code, so the frontend can tell a missing record from bad input or a
+// storage failure
+package apperror
+
+import (
+	"errors"
+	"fmt"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/validation"
+)
+
+// Code classifies an error. Codes are part of the API; never rename one.
+type Code string
+
+// Error codes
+const (
+	NotFound Code = "not_found" // The record does not exist
+	Invalid  Code = "invalid"   // The input was rejected
+	Conflict Code = "conflict"  // The request clashes with existing data or state
+	Locked   Code = "locked"    // Trading is locked by the circuit breaker
+	Internal Code = "internal"  // Storage or other unexpected failure
+)
+
+// Error is an error with a code. Fields lists the bad fields of
+// Invalid errors from the validation package.
+type Error struct {
+	Code    Code                    `json:"code"`
+	Message string                  `json:"message"`
+	Fields  []validation.FieldError `json:"fields,omitempty"`
+	err     error
+}
+
+// Error returns the message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error this one classifies
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// New returns an error with code and message. Use it for sentinel
+// errors; wrapping them with %w keeps the code.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf returns an error with code and a formatted message
+func Newf(code Code, format string, args ...any) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Message: err.Error(), err: errors.Unwrap(err)}
+}
+
+// Wrap classifies err with code, keeping its message. It returns nil
+// for a nil err.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{Code: code, Message: err.Error(), err: err}
+	if fields, ok := validation.Fields(err); ok {
+		e.Fields = fields
+	}
+	return e
+}
+
+// From classifies any error: coded errors keep their code (with the
+// full wrapped message), validation errors are Invalid, missing records
+// are NotFound and everything else is Internal
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	result := &Error{Code: Internal, Message: err.Error(), err: err}
+
+	var coded *Error
+	switch {
+	case errors.As(err, &coded):
+		result.Code = coded.Code
+		result.Fields = coded.Fields
+	case errors.Is(err, database.ErrNotFound):
+		result.Code = NotFound
+	}
+	if fields, ok := validation.Fields(err); ok {
+		result.Code = Invalid
+		result.Fields = fields
+	}
+	return result
+}
+
+// CodeOf returns the code From would give err
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	return From(err).Code
+}
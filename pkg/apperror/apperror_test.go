@@ -0,0 +1,76 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/validation"
+)
+
+var errLocked = New(Locked, "trading is locked")
+
+func TestFrom(t *testing.T) {
+	fields := validation.Errors{{Field: "ticker", Code: validation.CodeRequired, Message: "ticker is required"}}
+	tests := []struct {
+		name    string
+		err     error
+		code    Code
+		message string
+		fields  []validation.FieldError
+	}{
+		{"missing record", database.ErrNotFound, NotFound, database.ErrNotFound.Error(), nil},
+		{"wrapped missing record", fmt.Errorf("loading trade trade_1: %w", database.ErrNotFound),
+			NotFound, "loading trade trade_1: " + database.ErrNotFound.Error(), nil},
+		{"validation errors", fields, Invalid, "ticker is required", fields},
+		{"wrapped validation errors", fmt.Errorf("saving trade: %w", fields), Invalid, "saving trade: ticker is required", fields},
+		{"coded error", errLocked, Locked, "trading is locked", nil},
+		{"wrapped coded error keeps the full message", fmt.Errorf("saving trade: %w", errLocked),
+			Locked, "saving trade: trading is locked", nil},
+		{"code wrapping a missing record", Newf(Conflict, "rating %s: %w", "stock_1", database.ErrNotFound),
+			Conflict, "rating stock_1: " + database.ErrNotFound.Error(), nil},
+		{"anything else", errors.New("disk full"), Internal, "disk full", nil},
+	}
+	for _, tt := range tests {
+		got := From(tt.err)
+		if got.Code != tt.code || got.Message != tt.message || !reflect.DeepEqual(got.Fields, tt.fields) {
+			t.Errorf("%s: From = %+v, want %s %q with fields %v", tt.name, got, tt.code, tt.message, tt.fields)
+		}
+		// Field errors are slices, so errors.Is cannot compare them
+		if tt.fields == nil && !errors.Is(got, tt.err) {
+			t.Errorf("%s: From result does not unwrap to %v", tt.name, tt.err)
+		}
+		if code := CodeOf(tt.err); code != tt.code {
+			t.Errorf("%s: CodeOf = %s, want %s", tt.name, code, tt.code)
+		}
+	}
+	if From(nil) != nil || CodeOf(nil) != "" {
+		t.Error("nil error was classified")
+	}
+}
+
+func TestNewf(t *testing.T) {
+	err := Newf(NotFound, "trade %s: %w", "trade_1", database.ErrNotFound)
+	if !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Newf(%%w) does not unwrap to the wrapped error")
+	}
+	if plain := Newf(Invalid, "bad %s", "input"); plain.Unwrap() != nil || plain.Message != "bad input" {
+		t.Errorf("Newf without %%w = %+v, want message %q and nothing wrapped", plain, "bad input")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(Internal, nil) != nil {
+		t.Error("Wrap(nil) is not nil")
+	}
+	fields := validation.Errors{{Field: "strike", Code: validation.CodeOutOfRange, Message: "strike cannot be negative"}}
+	var coded *Error
+	if err := Wrap(Invalid, fields); !errors.As(err, &coded) || coded.Code != Invalid || !reflect.DeepEqual(coded.Fields, []validation.FieldError(fields)) {
+		t.Errorf("Wrap(validation errors) = %+v, want Invalid with their fields", err)
+	}
+	if err := Wrap(Conflict, errors.New("already closed")); !errors.As(err, &coded) || coded.Code != Conflict || coded.Message != "already closed" {
+		t.Errorf("Wrap = %+v, want Conflict keeping the message", err)
+	}
+}
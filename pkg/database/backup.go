@@ -0,0 +1,79 @@
+package database
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// backupEntry is one line of a backup file
+type backupEntry struct {
+	Key   string `json:"k"`
+	Value string `json:"v"`
+}
+
+// Backup writes every key in store to path as JSON lines
+func Backup(store Store, path string) error {
+	if store == nil {
+		return ErrNotInitialized
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	count := 0
+	err = store.Scan("", func(key string, value []byte) error {
+		count++
+		return enc.Encode(backupEntry{Key: key, Value: string(value)})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	log.Printf("SUCCESS: Backed up %d keys to %s", count, path)
+	return f.Close()
+}
+
+// Restore loads a file written by Backup into store. Existing keys with
+// the same name are overwritten; other keys are left alone.
+func Restore(store Store, path string) error {
+	if store == nil {
+		return ErrNotInitialized
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	count := 0
+	for dec.More() {
+		var entry backupEntry
+		if err := dec.Decode(&entry); err != nil {
+			return fmt.Errorf("failed to read backup entry %d: %w", count+1, err)
+		}
+		if err := store.Set(entry.Key, []byte(entry.Value)); err != nil {
+			return err
+		}
+		count++
+	}
+
+	log.Printf("SUCCESS: Restored %d keys from %s", count, path)
+	return nil
+}
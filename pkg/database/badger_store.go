@@ -0,0 +1,157 @@
+package database
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// BadgerStore is a Store backed by BadgerDB
+type BadgerStore struct {
+	db *badger.DB
+}
+
+// OpenBadgerStore opens (or creates) a BadgerDB database in dir
+func OpenBadgerStore(dir string) (*BadgerStore, error) {
+	log.Printf("DEBUG: Initializing BadgerDB at: %s", dir)
+
+	// Configure BadgerDB options
+	opts := badger.DefaultOptions(dir).WithLoggingLevel(badger.INFO)
+
+	// Open the database
+	log.Printf("DEBUG: Opening BadgerDB...")
+	db, err := badger.Open(opts)
+	if err != nil {
+		log.Printf("ERROR: Failed to open BadgerDB: %v", err)
+		return nil, fmt.Errorf("failed to open BadgerDB: %w", err)
+	}
+
+	log.Println("DEBUG: BadgerDB initialized successfully")
+	return &BadgerStore{db: db}, nil
+}
+
+// Set stores a key-value pair in BadgerDB
+func (s *BadgerStore) Set(key string, value []byte) error {
+	log.Printf("DEBUG: Setting key: %s (value size: %d bytes)", key, len(value))
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), value)
+	})
+}
+
+// Get retrieves a value by key from BadgerDB
+func (s *BadgerStore) Get(key string) ([]byte, error) {
+	log.Printf("DEBUG: Getting key: %s", key)
+	var valCopy []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		valCopy, err = item.ValueCopy(nil)
+		return err
+	})
+
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		log.Printf("ERROR: DB view operation failed for key %s: %v", key, err)
+		return nil, err
+	}
+	return valCopy, nil
+}
+
+// Delete removes a key-value pair from BadgerDB
+func (s *BadgerStore) Delete(key string) error {
+	log.Printf("DEBUG: Deleting key: %s", key)
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+// Scan iterates over all items with a specific prefix
+func (s *BadgerStore) Scan(prefix string, fn func(key string, value []byte) error) error {
+	log.Printf("DEBUG: Scanning keys with prefix: %s", prefix)
+	return s.iterate([]byte(prefix), []byte(prefix), nil, fn)
+}
+
+// ScanRange iterates over all items with keys in [start, end)
+func (s *BadgerStore) ScanRange(start, end string, fn func(key string, value []byte) error) error {
+	log.Printf("DEBUG: Scanning keys in range: [%s, %s)", start, end)
+	var endBytes []byte
+	if end != "" {
+		endBytes = []byte(end)
+	}
+	return s.iterate(nil, []byte(start), endBytes, fn)
+}
+
+// iterate walks keys from start, staying within prefix (if set) and below end (if set)
+func (s *BadgerStore) iterate(prefix, start, end []byte, fn func(key string, value []byte) error) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = true
+		opts.Prefix = prefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			if end != nil && bytes.Compare(item.Key(), end) >= 0 {
+				break
+			}
+			key := string(item.Key())
+
+			// Make a copy to use outside the transaction
+			valCopy, err := item.ValueCopy(nil)
+			if err != nil {
+				log.Printf("ERROR: Failed to process value for key %s: %v", key, err)
+				return err
+			}
+			if err := fn(key, valCopy); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Update runs fn inside a BadgerDB read-write transaction
+func (s *BadgerStore) Update(fn func(tx Tx) error) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return fn(badgerTx{txn: txn})
+	})
+}
+
+// badgerTx adapts a BadgerDB transaction to Tx
+type badgerTx struct {
+	txn *badger.Txn
+}
+
+func (t badgerTx) Get(key string) ([]byte, error) {
+	item, err := t.txn.Get([]byte(key))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+func (t badgerTx) Set(key string, value []byte) error {
+	return t.txn.Set([]byte(key), value)
+}
+
+func (t badgerTx) Delete(key string) error {
+	return t.txn.Delete([]byte(key))
+}
+
+// Close closes the BadgerDB connection
+func (s *BadgerStore) Close() error {
+	log.Println("DEBUG: Closing BadgerDB connection...")
+	return s.db.Close()
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Collection is a typed set of JSON records that share a key prefix.
+// Record IDs are the full store keys, so every ID in a collection starts
+// with its prefix (e.g. "trade_...").
+type Collection[T any] struct {
+	store   Store
+	prefix  string
+	indexes []index[T]
+}
+
+// index maps a record to the terms it is filed under
+type index[T any] struct {
+	name  string
+	terms func(value *T) []string
+}
+
+// NewCollection creates a collection of T stored under prefix
+func NewCollection[T any](store Store, prefix string) *Collection[T] {
+	return &Collection[T]{store: store, prefix: prefix}
+}
+
+// Prefix returns the key prefix shared by the collection's records
+func (c *Collection[T]) Prefix() string {
+	return c.prefix
+}
+
+// Put stores value under id, replacing any existing record. Index
+// entries are updated in the same transaction as the record.
+func (c *Collection[T]) Put(id string, value *T) error {
+	if err := c.checkID(id); err != nil {
+		return err
+	}
+	if c.store == nil {
+		return ErrNotInitialized
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal value for key %s: %v", id, err)
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	log.Printf("DEBUG: Setting key: %s (value size: %d bytes)", id, len(bytes))
+	return c.store.Update(func(tx Tx) error {
+		if err := c.unindex(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Set(id, bytes); err != nil {
+			return err
+		}
+		return c.index(tx, id, value)
+	})
+}
+
+// Get retrieves the record stored under id
+func (c *Collection[T]) Get(id string) (*T, error) {
+	if err := c.checkID(id); err != nil {
+		return nil, err
+	}
+	value := new(T)
+	if err := Get(c.store, id, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// List returns every record in the collection in key order. An empty
+// collection returns an empty (non-nil) slice.
+func (c *Collection[T]) List() ([]*T, error) {
+	results := []*T{}
+	err := c.Scan(func(id string, value *T) error {
+		results = append(results, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("DEBUG: Successfully unmarshaled %d items with prefix: %s", len(results), c.prefix)
+	return results, nil
+}
+
+// ListPrefix returns the records whose IDs start with prefix, which
+// must itself start with the collection prefix
+func (c *Collection[T]) ListPrefix(prefix string) ([]*T, error) {
+	if err := c.checkID(prefix); err != nil {
+		return nil, err
+	}
+	if c.store == nil {
+		return nil, ErrNotInitialized
+	}
+
+	results := []*T{}
+	err := c.store.Scan(prefix, func(key string, raw []byte) error {
+		value := new(T)
+		if err := json.Unmarshal(raw, value); err != nil {
+			return fmt.Errorf("failed to unmarshal value for key %s: %w", key, err)
+		}
+		results = append(results, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Delete removes the record stored under id and its index entries
+func (c *Collection[T]) Delete(id string) error {
+	if err := c.checkID(id); err != nil {
+		return err
+	}
+	if c.store == nil {
+		return ErrNotInitialized
+	}
+
+	log.Printf("DEBUG: Deleting key: %s", id)
+	return c.store.Update(func(tx Tx) error {
+		return c.DeleteTx(tx, id)
+	})
+}
+
+// DeleteTx removes a record and its index entries within tx, so the
+// delete commits together with the transaction's other writes
+func (c *Collection[T]) DeleteTx(tx Tx, id string) error {
+	if err := c.checkID(id); err != nil {
+		return err
+	}
+	if err := c.unindex(tx, id); err != nil {
+		return err
+	}
+	return tx.Delete(id)
+}
+
+// Scan decodes every record in key order and passes it to fn
+func (c *Collection[T]) Scan(fn func(id string, value *T) error) error {
+	if c.store == nil {
+		return ErrNotInitialized
+	}
+
+	log.Printf("DEBUG: Getting all keys with prefix: %s", c.prefix)
+	return c.store.Scan(c.prefix, func(key string, raw []byte) error {
+		value := new(T)
+		if err := json.Unmarshal(raw, value); err != nil {
+			log.Printf("ERROR: Failed to unmarshal %T for key %s: %v", value, key, err)
+			return fmt.Errorf("failed to unmarshal value for key %s: %w", key, err)
+		}
+		return fn(key, value)
+	})
+}
+
+// checkID rejects IDs that would land outside the collection's prefix
+func (c *Collection[T]) checkID(id string) error {
+	if !strings.HasPrefix(id, c.prefix) {
+		return fmt.Errorf("id %q does not belong to collection %q", id, c.prefix)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testRecord struct {
+	ID     string `json:"id"`
+	Ticker string `json:"ticker"`
+	Qty    int    `json:"qty"`
+}
+
+// tickers returns the records' tickers in order
+func tickers(records []*testRecord) []string {
+	var out []string
+	for _, r := range records {
+		out = append(out, r.Ticker)
+	}
+	return out
+}
+
+func TestCollection(t *testing.T) {
+	store := NewMemoryStore()
+	trades := NewCollection[testRecord](store, "trade_")
+	records := []*testRecord{
+		{ID: "trade_b", Ticker: "MSFT", Qty: 2},
+		{ID: "trade_a", Ticker: "AAPL", Qty: 1},
+		{ID: "trade_a/1", Ticker: "AAPL", Qty: 3},
+	}
+	for _, r := range records {
+		if err := trades.Put(r.ID, r); err != nil {
+			t.Fatalf("Put(%s): %v", r.ID, err)
+		}
+	}
+	// Records of another collection are never listed
+	if err := Set(store, "tradex_1", &testRecord{ID: "tradex_1", Ticker: "TSLA"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := trades.Get("trade_b")
+	if err != nil || !reflect.DeepEqual(got, records[0]) {
+		t.Errorf("Get(trade_b) = %+v, %v; want %+v", got, err, records[0])
+	}
+	if _, err := trades.Get("trade_missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(trade_missing) error = %v, want ErrNotFound", err)
+	}
+
+	all, err := trades.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if want := []string{"AAPL", "AAPL", "MSFT"}; !reflect.DeepEqual(tickers(all), want) {
+		t.Errorf("List = %v, want %v in key order", tickers(all), want)
+	}
+
+	children, err := trades.ListPrefix("trade_a/")
+	if err != nil || len(children) != 1 || children[0].Qty != 3 {
+		t.Errorf("ListPrefix(trade_a/) = %+v, %v; want only trade_a/1", children, err)
+	}
+
+	var scanned []string
+	err = trades.Scan(func(id string, value *testRecord) error {
+		if id != value.ID {
+			t.Errorf("Scan passed id %s for record %s", id, value.ID)
+		}
+		scanned = append(scanned, id)
+		return nil
+	})
+	if want := []string{"trade_a", "trade_a/1", "trade_b"}; err != nil || !reflect.DeepEqual(scanned, want) {
+		t.Errorf("Scan visited %v, %v; want %v", scanned, err, want)
+	}
+
+	if err := trades.Delete("trade_a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := trades.Get("trade_a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+	if err := trades.Delete("trade_a"); err != nil {
+		t.Errorf("second Delete = %v, want nil", err)
+	}
+
+	empty, err := NewCollection[testRecord](store, "risk_").List()
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("List of an empty collection = %#v, %v; want an empty slice", empty, err)
+	}
+}
+
+func TestCollectionRejectsForeignIDs(t *testing.T) {
+	trades := NewCollection[testRecord](NewMemoryStore(), "trade_")
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Put", func() error { return trades.Put("risk_1", &testRecord{}) }},
+		{"Get", func() error { _, err := trades.Get("risk_1"); return err }},
+		{"ListPrefix", func() error { _, err := trades.ListPrefix("risk_"); return err }},
+		{"Delete", func() error { return trades.Delete("risk_1") }},
+		{"empty ID", func() error { return trades.Put("", &testRecord{}) }},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); err == nil {
+			t.Errorf("%s outside the collection prefix succeeded", tt.name)
+		}
+	}
+}
+
+func TestCollectionWithoutStore(t *testing.T) {
+	trades := NewCollection[testRecord](nil, "trade_")
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Put", func() error { return trades.Put("trade_1", &testRecord{}) }},
+		{"List", func() error { _, err := trades.List(); return err }},
+		{"ListPrefix", func() error { _, err := trades.ListPrefix("trade_"); return err }},
+		{"Delete", func() error { return trades.Delete("trade_1") }},
+		{"EnsureIndexes", trades.EnsureIndexes},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, ErrNotInitialized) {
+			t.Errorf("%s error = %v, want ErrNotInitialized", tt.name, err)
+		}
+	}
+}
+
+func TestCollectionScanStopsOnError(t *testing.T) {
+	trades := NewCollection[testRecord](NewMemoryStore(), "trade_")
+	for _, id := range []string{"trade_1", "trade_2"} {
+		if err := trades.Put(id, &testRecord{ID: id}); err != nil {
+			t.Fatalf("Put(%s): %v", id, err)
+		}
+	}
+	errStop := errors.New("stop")
+	calls := 0
+	err := trades.Scan(func(id string, value *testRecord) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Errorf("Scan = %v after %d calls, want fn's error after 1", err, calls)
+	}
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Supported storage engines
+const (
+	EngineBadger = "badger"
+	EngineMemory = "memory"
+	EngineSQLite = "sqlite"
+)
+
+// ConfigFileName is the name of the optional config file in the app directory
+const ConfigFileName = "config.json"
+
+// Config selects and configures the storage engine
+type Config struct {
+	Engine  string `json:"engine"`  // badger, memory or sqlite
+	DataDir string `json:"dataDir"` // Directory holding the database files
+
+	// BackupDir receives a full export before schema migrations run
+	BackupDir string `json:"backupDir"`
+	// MigrationDryRun reports pending migrations without applying them
+	// and stops the database from opening
+	MigrationDryRun bool `json:"migrationDryRun"`
+}
+
+// AppDir returns the per-user application directory
+func AppDir() string {
+	// Get user-specific app data directory
+	appDataDir, err := os.UserConfigDir()
+	if err != nil {
+		log.Printf("ERROR: Could not get user config directory: %v", err)
+		// Fall back to relative path if user directory can't be determined
+		appDataDir = "."
+	}
+	return filepath.Join(appDataDir, "TradingDashboard")
+}
+
+// DefaultConfig returns the BadgerDB configuration used when no config file exists
+func DefaultConfig() Config {
+	return Config{
+		Engine:    EngineBadger,
+		DataDir:   filepath.Join(AppDir(), "data"),
+		BackupDir: filepath.Join(AppDir(), "backups"),
+	}
+}
+
+// LoadConfig reads config.json from the app directory, filling in
+// defaults for anything it leaves out
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	path := filepath.Join(AppDir(), ConfigFileName)
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("DEBUG: No config file at %s, using defaults", path)
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if cfg.Engine == "" {
+		cfg.Engine = EngineBadger
+	}
+	if cfg.DataDir == "" {
+		cfg.DataDir = DefaultConfig().DataDir
+	}
+	if cfg.BackupDir == "" {
+		cfg.BackupDir = DefaultConfig().BackupDir
+	}
+
+	log.Printf("DEBUG: Loaded config from %s (engine=%s)", path, cfg.Engine)
+	return cfg, nil
+}
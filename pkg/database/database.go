@@ -2,33 +2,81 @@ package database
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"time"
-	"trading-dashboard/pkg/models"
-
-	"github.com/dgraph-io/badger/v4"
 )
 
-// DB is the database instance
-var DB *badger.DB
+// Initialize loads the config, opens the configured store and checks
+// that it is operational
+func Initialize() (Store, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return nil, err
+	}
+	return Open(cfg)
+}
+
+// Open opens the store selected by cfg.Engine, checks that it is
+// operational and brings its schema up to date
+func Open(cfg Config) (Store, error) {
+	var store Store
+	switch cfg.Engine {
+	case EngineMemory:
+		log.Println("DEBUG: Using in-memory store")
+		store = NewMemoryStore()
+	case EngineBadger, EngineSQLite:
+		if err := prepareDataDir(cfg.DataDir); err != nil {
+			return nil, err
+		}
+		var err error
+		if cfg.Engine == EngineBadger {
+			store, err = OpenBadgerStore(cfg.DataDir)
+		} else {
+			store, err = OpenSQLiteStore(filepath.Join(cfg.DataDir, "trading.sqlite"))
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown database engine %q", cfg.Engine)
+	}
+
+	if err := selfTest(store); err != nil {
+		store.Close()
+		return nil, err
+	}
+
+	if err := migrate(store, cfg); err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// migrate runs (or dry-runs) the pending schema migrations for cfg
+func migrate(store Store, cfg Config) error {
+	opts := MigrateOptions{DryRun: cfg.MigrationDryRun}
+	if cfg.Engine != EngineMemory {
+		opts.BackupDir = cfg.BackupDir
+	}
 
-// Initialize sets up the database connection
-func Initialize() error {
-	// Get user-specific app data directory
-	appDataDir, err := os.UserConfigDir()
+	report, err := Migrate(store, opts)
 	if err != nil {
-		log.Printf("ERROR: Could not get user config directory: %v", err)
-		// Fall back to relative path if user directory can't be determined
-		appDataDir = "."
+		return err
 	}
-	log.Printf("DEBUG: Using app data directory: %s", appDataDir)
+	if cfg.MigrationDryRun && len(report.Applied) > 0 {
+		return fmt.Errorf("migration dry run finished (v%d -> v%d: %d added, %d changed, %d removed); "+
+			"set migrationDryRun to false to apply", report.FromVersion, report.ToVersion,
+			report.Added, report.Changed, report.Removed)
+	}
+	return nil
+}
 
-	// Create a specific subdirectory for our application
-	dataDir := filepath.Join(appDataDir, "TradingDashboard", "data")
+// prepareDataDir creates the data directory and checks it is writable
+func prepareDataDir(dataDir string) error {
 	log.Printf("DEBUG: Full data directory path: %s", dataDir)
 
 	// Create directory if it doesn't exist
@@ -45,30 +93,18 @@ func Initialize() error {
 	}
 	// Clean up test file
 	os.Remove(testFile)
+	return nil
+}
 
-	log.Printf("DEBUG: Initializing BadgerDB at: %s", dataDir)
-
-	// Configure BadgerDB options
-	opts := badger.DefaultOptions(dataDir).WithLoggingLevel(badger.INFO)
-
-	// Open the database
-	log.Printf("DEBUG: Opening BadgerDB...")
-	db, err := badger.Open(opts)
-	if err != nil {
-		log.Printf("ERROR: Failed to open BadgerDB: %v", err)
-		return fmt.Errorf("failed to open BadgerDB: %w", err)
-	}
-
-	DB = db
-	log.Println("DEBUG: BadgerDB initialized successfully")
-
+// selfTest writes, reads and deletes a test key
+func selfTest(store Store) error {
 	// Test database connection with a simple set/get
 	testKey := "test_init_key"
 	testValue := "test_value"
 	log.Printf("DEBUG: Testing database with key: %s", testKey)
 
 	// Try to write to DB
-	err = Set(testKey, testValue)
+	err := Set(store, testKey, testValue)
 	if err != nil {
 		log.Printf("ERROR: Failed to write test value: %v", err)
 		return fmt.Errorf("failed to write test value: %w", err)
@@ -76,7 +112,7 @@ func Initialize() error {
 
 	// Try to read from DB
 	var retrievedValue string
-	err = Get(testKey, &retrievedValue)
+	err = Get(store, testKey, &retrievedValue)
 	if err != nil {
 		log.Printf("ERROR: Failed to read test value: %v", err)
 		return fmt.Errorf("failed to read test value: %w", err)
@@ -88,34 +124,33 @@ func Initialize() error {
 	}
 
 	// Clean up test key
-	err = Delete(testKey)
+	err = store.Delete(testKey)
 	if err != nil {
 		log.Printf("WARNING: Failed to delete test key: %v", err)
 	}
 
-	log.Println("SUCCESS: BadgerDB test successful - database is operational")
+	log.Println("SUCCESS: Database test successful - database is operational")
 	return nil
 }
 
-// Close closes the database connection
-func Close() {
-	if DB != nil {
-		log.Println("DEBUG: Closing BadgerDB connection...")
-		err := DB.Close()
+// Close closes the store
+func Close(store Store) {
+	if store != nil {
+		err := store.Close()
 		if err != nil {
-			log.Printf("ERROR: Failed to close BadgerDB: %v", err)
+			log.Printf("ERROR: Failed to close database: %v", err)
 		} else {
-			log.Println("DEBUG: BadgerDB connection closed successfully")
+			log.Println("DEBUG: Database connection closed successfully")
 		}
 	}
 }
 
-// Helper functions for key-value operations
+// Helper functions for JSON values
 
-// Set stores a key-value pair in BadgerDB
-func Set(key string, value interface{}) error {
-	if DB == nil {
-		return errors.New("database not initialized")
+// Set marshals value to JSON and stores it under key
+func Set(store Store, key string, value interface{}) error {
+	if store == nil {
+		return ErrNotInitialized
 	}
 
 	bytes, err := json.Marshal(value)
@@ -123,34 +158,18 @@ func Set(key string, value interface{}) error {
 		log.Printf("ERROR: Failed to marshal value for key %s: %v", key, err)
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
-
-	log.Printf("DEBUG: Setting key: %s (value size: %d bytes)", key, len(bytes))
-	return DB.Update(func(txn *badger.Txn) error {
-		return txn.Set([]byte(key), bytes)
-	})
+	return store.Set(key, bytes)
 }
 
-// Get retrieves a value by key from BadgerDB
-func Get(key string, result interface{}) error {
-	if DB == nil {
-		return errors.New("database not initialized")
+// Get retrieves the value stored under key and unmarshals it into result
+func Get(store Store, key string, result interface{}) error {
+	if store == nil {
+		return ErrNotInitialized
 	}
 
-	log.Printf("DEBUG: Getting key: %s", key)
-	var valCopy []byte
-	err := DB.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(key))
-		if err != nil {
-			log.Printf("ERROR: Failed to get item for key %s: %v", key, err)
-			return err
-		}
-
-		valCopy, err = item.ValueCopy(nil)
-		return err
-	})
-
+	valCopy, err := store.Get(key)
 	if err != nil {
-		log.Printf("ERROR: DB view operation failed for key %s: %v", key, err)
+		log.Printf("ERROR: Failed to get item for key %s: %v", key, err)
 		return err
 	}
 
@@ -163,122 +182,3 @@ func Get(key string, result interface{}) error {
 	log.Printf("DEBUG: Successfully retrieved key: %s (value size: %d bytes)", key, len(valCopy))
 	return nil
 }
-
-// Delete removes a key-value pair from BadgerDB
-func Delete(key string) error {
-	if DB == nil {
-		return errors.New("database not initialized")
-	}
-
-	log.Printf("DEBUG: Deleting key: %s", key)
-	return DB.Update(func(txn *badger.Txn) error {
-		return txn.Delete([]byte(key))
-	})
-}
-
-// GetByPrefix retrieves all items with a specific prefix
-func GetByPrefix(prefix string, results interface{}) error {
-	if DB == nil {
-		return errors.New("database not initialized")
-	}
-
-	log.Printf("DEBUG: Getting all keys with prefix: %s", prefix)
-	var items [][]byte
-	err := DB.View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchValues = true
-		it := txn.NewIterator(opts)
-		defer it.Close()
-
-		prefixBytes := []byte(prefix)
-		count := 0
-		for it.Seek(prefixBytes); it.ValidForPrefix(prefixBytes); it.Next() {
-			count++
-			item := it.Item()
-			key := item.Key()
-			log.Printf("DEBUG: Found key: %s", string(key))
-
-			err := item.Value(func(val []byte) error {
-				// Make a copy to use outside the transaction
-				valCopy := append([]byte{}, val...)
-				items = append(items, valCopy)
-				return nil
-			})
-
-			if err != nil {
-				log.Printf("ERROR: Failed to process value for key %s: %v", string(key), err)
-				return err
-			}
-		}
-		log.Printf("DEBUG: Found %d items with prefix: %s", count, prefix)
-		return nil
-	})
-
-	if err != nil {
-		log.Printf("ERROR: Failed to get items with prefix %s: %v", prefix, err)
-		return err
-	}
-
-	// Handle empty results case - initialize with empty slice
-	if len(items) == 0 {
-		log.Printf("DEBUG: No items found with prefix: %s, returning empty slice", prefix)
-
-		// Handle different result types for empty results
-		switch resultsPtr := results.(type) {
-		case *[]*models.RiskAssessment:
-			*resultsPtr = []*models.RiskAssessment{}
-		case *[]*models.StockRating:
-			*resultsPtr = []*models.StockRating{}
-		case *[]*models.Trade:
-			*resultsPtr = []*models.Trade{}
-		default:
-			log.Printf("ERROR: Unknown result type for GetByPrefix: %T", results)
-			return fmt.Errorf("unknown result type for prefix %s", prefix)
-		}
-		return nil
-	}
-
-	// Unmarshal each item directly into the appropriate type
-	switch resultsPtr := results.(type) {
-	case *[]*models.RiskAssessment:
-		for _, item := range items {
-			var assessment models.RiskAssessment
-			if err := json.Unmarshal(item, &assessment); err != nil {
-				log.Printf("ERROR: Failed to unmarshal RiskAssessment: %v", err)
-				return err
-			}
-			*resultsPtr = append(*resultsPtr, &assessment)
-		}
-	case *[]*models.StockRating:
-		for _, item := range items {
-			var rating models.StockRating
-			if err := json.Unmarshal(item, &rating); err != nil {
-				log.Printf("ERROR: Failed to unmarshal StockRating: %v", err)
-				return err
-			}
-			*resultsPtr = append(*resultsPtr, &rating)
-		}
-	case *[]*models.Trade:
-		for _, item := range items {
-			var trade models.Trade
-			if err := json.Unmarshal(item, &trade); err != nil {
-				log.Printf("ERROR: Failed to unmarshal Trade: %v", err)
-				return err
-			}
-			*resultsPtr = append(*resultsPtr, &trade)
-		}
-	default:
-		log.Printf("ERROR: Unknown result type for GetByPrefix: %T", results)
-		return fmt.Errorf("unknown result type for prefix %s", prefix)
-	}
-
-	log.Printf("DEBUG: Successfully unmarshaled %d items with prefix: %s", len(items), prefix)
-	return nil
-}
-
-// GenerateKey generates a key with a prefix and timestamp
-func GenerateKey(prefix string) string {
-	key := fmt.Sprintf("%s_%s", prefix, time.Now().Format(time.RFC3339))
-	log.Printf("DEBUG: Generated key: %s", key)
-	return key
-}
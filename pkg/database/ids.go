@@ -0,0 +1,135 @@
+package database
+
+import (
+	"crypto/rand"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDs are ULIDs: a 48-bit millisecond timestamp followed by 80 random
+// bits, written as 26 Crockford base32 characters. They sort
+// lexicographically in creation order, so a prefix scan returns records
+// oldest first.
+
+const (
+	idLength   = 26
+	idAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// idGenerator hands out strictly increasing IDs. Within one millisecond
+// the random part of the previous ID is incremented instead of redrawn.
+type idGenerator struct {
+	mu       sync.Mutex
+	lastMs   uint64
+	lastRand [10]byte
+}
+
+var ids idGenerator
+
+// NewID returns a new unique, sortable ID with the given prefix
+// (e.g. NewID("trade_") -> "trade_01J9Z3...")
+func NewID(prefix string) string {
+	id := prefix + encodeID(ids.next(time.Now()))
+	log.Printf("DEBUG: Generated key: %s", id)
+	return id
+}
+
+// NewIDAt returns an ID whose timestamp part is t. It is used when
+// re-keying historical records so they keep their original order; IDs
+// made this way are unique but not monotonic.
+func NewIDAt(prefix string, t time.Time) string {
+	var raw [16]byte
+	putMillis(&raw, uint64(t.UnixMilli()))
+	if _, err := rand.Read(raw[6:]); err != nil {
+		panic("database: crypto/rand failed: " + err.Error())
+	}
+	return prefix + encodeID(raw)
+}
+
+// IsID reports whether id is prefix followed by a well-formed ULID
+func IsID(prefix, id string) bool {
+	if !strings.HasPrefix(id, prefix) || len(id) != len(prefix)+idLength {
+		return false
+	}
+	for _, c := range id[len(prefix):] {
+		if !strings.ContainsRune(idAlphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// IDTime returns the creation time encoded in an ID made by NewID or NewIDAt
+func IDTime(prefix, id string) (time.Time, bool) {
+	if !IsID(prefix, id) {
+		return time.Time{}, false
+	}
+	// The first 10 characters hold the 48-bit timestamp (50 bits, top 2 zero)
+	var ms uint64
+	for _, c := range id[len(prefix) : len(prefix)+10] {
+		ms = ms<<5 | uint64(strings.IndexRune(idAlphabet, c))
+	}
+	return time.UnixMilli(int64(ms)), true
+}
+
+func (g *idGenerator) next(now time.Time) [16]byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(now.UnixMilli())
+	if ms > g.lastMs {
+		g.lastMs = ms
+		if _, err := rand.Read(g.lastRand[:]); err != nil {
+			panic("database: crypto/rand failed: " + err.Error())
+		}
+	} else if !increment(g.lastRand[:]) {
+		// Random part overflowed within one millisecond; borrow the next one
+		g.lastMs++
+		if _, err := rand.Read(g.lastRand[:]); err != nil {
+			panic("database: crypto/rand failed: " + err.Error())
+		}
+	}
+
+	var raw [16]byte
+	putMillis(&raw, g.lastMs)
+	copy(raw[6:], g.lastRand[:])
+	return raw
+}
+
+// increment adds one to a big-endian number, reporting false on overflow
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func putMillis(raw *[16]byte, ms uint64) {
+	for i := 5; i >= 0; i-- {
+		raw[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// encodeID writes 128 bits as 26 base32 characters (the first carries 3 bits)
+func encodeID(raw [16]byte) string {
+	var out [idLength]byte
+	bit := -2 // the 130-bit encoding starts with two zero bits
+	for i := range out {
+		var v byte
+		for j := 0; j < 5; j++ {
+			v <<= 1
+			if bit >= 0 && raw[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+			bit++
+		}
+		out[i] = idAlphabet[v]
+	}
+	return string(out[:])
+}
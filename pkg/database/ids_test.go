@@ -0,0 +1,105 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewIDOrderAndUniqueness(t *testing.T) {
+	const n = 1000
+	seen := make(map[string]bool, n)
+	prev := ""
+	for i := 0; i < n; i++ {
+		id := NewID("trade_")
+		if !IsID("trade_", id) {
+			t.Fatalf("NewID returned malformed %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewID repeated %q", id)
+		}
+		if id <= prev {
+			t.Fatalf("NewID %q does not sort after %q", id, prev)
+		}
+		seen[id] = true
+		prev = id
+	}
+}
+
+func TestIDGeneratorWithinOneMillisecond(t *testing.T) {
+	now := time.Date(2025, 4, 25, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		lastRand [10]byte
+		wantMs   int64
+	}{
+		{"increments the random part", [10]byte{9: 1}, now.UnixMilli()},
+		{"borrows the next millisecond on overflow",
+			[10]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, now.UnixMilli() + 1},
+	}
+	for _, tt := range tests {
+		g := &idGenerator{lastMs: uint64(now.UnixMilli()), lastRand: tt.lastRand}
+		prev := "x_" + encodeID(g.peek())
+		id := "x_" + encodeID(g.next(now))
+		if id <= prev {
+			t.Errorf("%s: %s does not sort after %s", tt.name, id, prev)
+		}
+		if got, _ := IDTime("x_", id); got.UnixMilli() != tt.wantMs {
+			t.Errorf("%s: ID time %d, want %d", tt.name, got.UnixMilli(), tt.wantMs)
+		}
+	}
+}
+
+// peek returns the last ID the generator handed out
+func (g *idGenerator) peek() [16]byte {
+	var raw [16]byte
+	putMillis(&raw, g.lastMs)
+	copy(raw[6:], g.lastRand[:])
+	return raw
+}
+
+func TestIDTime(t *testing.T) {
+	stamp := time.Date(2025, 4, 25, 21, 0, 0, 123e6, time.FixedZone("EDT", -4*60*60))
+	tests := []struct {
+		name   string
+		prefix string
+		id     string
+		want   time.Time
+		ok     bool
+	}{
+		{"NewIDAt", "risk_", NewIDAt("risk_", stamp), stamp, true},
+		{"epoch", "risk_", NewIDAt("risk_", time.UnixMilli(0)), time.UnixMilli(0), true},
+		{"NewID", "trade_", NewID("trade_"), time.Now(), true},
+		{"wrong prefix", "trade_", NewIDAt("risk_", stamp), time.Time{}, false},
+		{"legacy key", "risk_", "risk__2025-04-25T21:00:00-04:00", time.Time{}, false},
+		{"too short", "risk_", "risk_01J9Z3", time.Time{}, false},
+		{"not Crockford base32", "risk_", "risk_" + strings.Repeat("U", idLength), time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := IDTime(tt.prefix, tt.id)
+		if ok != tt.ok {
+			t.Errorf("%s: IDTime(%q) ok = %v, want %v", tt.name, tt.id, ok, tt.ok)
+			continue
+		}
+		if tt.name == "NewID" {
+			if d := time.Since(got); d < 0 || d > time.Minute {
+				t.Errorf("%s: IDTime = %s, want about now", tt.name, got)
+			}
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: IDTime = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewIDAtKeepsOrder(t *testing.T) {
+	earlier := NewIDAt("risk_", time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC))
+	later := NewIDAt("risk_", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if earlier >= later {
+		t.Errorf("%s does not sort before %s", earlier, later)
+	}
+	if a, b := NewIDAt("risk_", time.UnixMilli(1)), NewIDAt("risk_", time.UnixMilli(1)); a == b {
+		t.Errorf("NewIDAt returned %s twice for the same time", a)
+	}
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Secondary index entries are empty values stored under
+//
+//	idx/<collection prefix><index name>/<term>/<record id>
+//
+// so all records filed under a term share a key prefix and terms are
+// ordered, which turns lookups into prefix scans and term ranges into
+// range scans.
+
+const (
+	indexKeyPrefix  = "idx/"
+	indexMarkPrefix = "meta_index_"
+)
+
+// AddIndex registers a secondary index. terms returns the values a
+// record is filed under (usually one); empty terms are skipped.
+func (c *Collection[T]) AddIndex(name string, terms func(value *T) []string) *Collection[T] {
+	c.indexes = append(c.indexes, index[T]{name: name, terms: terms})
+	return c
+}
+
+// Lookup returns the records filed under term in the named index, in ID order
+func (c *Collection[T]) Lookup(name, term string) ([]*T, error) {
+	base, err := c.indexBase(name)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := base + escapeTerm(term) + "/"
+	log.Printf("DEBUG: Looking up index %s", prefix)
+	return c.fetch(func(fn func(key string, value []byte) error) error {
+		return c.store.Scan(prefix, fn)
+	})
+}
+
+// Range returns the records whose term in the named index lies between
+// from and to (both inclusive), ordered by term. An empty bound is open.
+func (c *Collection[T]) Range(name, from, to string) ([]*T, error) {
+	base, err := c.indexBase(name)
+	if err != nil {
+		return nil, err
+	}
+
+	start := base + escapeTerm(from)
+	end := prefixEnd(base)
+	if to != "" {
+		end = prefixEnd(base + escapeTerm(to) + "/")
+	}
+	log.Printf("DEBUG: Scanning index range [%s, %s)", start, end)
+	return c.fetch(func(fn func(key string, value []byte) error) error {
+		return c.store.ScanRange(start, end, fn)
+	})
+}
+
+// EnsureIndexes builds any index that has not been built yet, so
+// records written before an index existed can be found through it
+func (c *Collection[T]) EnsureIndexes() error {
+	if c.store == nil {
+		return ErrNotInitialized
+	}
+
+	for _, idx := range c.indexes {
+		mark := indexMarkPrefix + c.prefix + idx.name
+		if _, err := c.store.Get(mark); err == nil {
+			continue
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		log.Printf("DEBUG: Building index %s%s", c.prefix, idx.name)
+		count := 0
+		err := c.Scan(func(id string, value *T) error {
+			count++
+			return c.store.Update(func(tx Tx) error {
+				for _, key := range c.indexKeys(idx, id, value) {
+					if err := tx.Set(key, []byte{}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build index %s%s: %w", c.prefix, idx.name, err)
+		}
+		if err := c.store.Set(mark, []byte(`true`)); err != nil {
+			return err
+		}
+		log.Printf("SUCCESS: Indexed %d records in %s%s", count, c.prefix, idx.name)
+	}
+	return nil
+}
+
+// TimeTerm formats t as an index term that sorts chronologically
+func TimeTerm(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// index writes the index entries for value
+func (c *Collection[T]) index(tx Tx, id string, value *T) error {
+	for _, idx := range c.indexes {
+		for _, key := range c.indexKeys(idx, id, value) {
+			if err := tx.Set(key, []byte{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unindex removes the index entries of the record currently stored under id
+func (c *Collection[T]) unindex(tx Tx, id string) error {
+	if len(c.indexes) == 0 {
+		return nil
+	}
+
+	raw, err := tx.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	old := new(T)
+	if err := json.Unmarshal(raw, old); err != nil {
+		return fmt.Errorf("failed to unmarshal value for key %s: %w", id, err)
+	}
+	for _, idx := range c.indexes {
+		for _, key := range c.indexKeys(idx, id, old) {
+			if err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Collection[T]) indexKeys(idx index[T], id string, value *T) []string {
+	var keys []string
+	for _, term := range idx.terms(value) {
+		if term == "" {
+			continue
+		}
+		keys = append(keys, indexKeyPrefix+c.prefix+idx.name+"/"+escapeTerm(term)+"/"+id)
+	}
+	return keys
+}
+
+func (c *Collection[T]) indexBase(name string) (string, error) {
+	if c.store == nil {
+		return "", ErrNotInitialized
+	}
+	for _, idx := range c.indexes {
+		if idx.name == name {
+			return indexKeyPrefix + c.prefix + name + "/", nil
+		}
+	}
+	return "", fmt.Errorf("collection %q has no index %q", c.prefix, name)
+}
+
+// fetch loads the records referenced by the index keys that scan visits,
+// skipping duplicates and entries whose record has gone
+func (c *Collection[T]) fetch(scan func(fn func(key string, value []byte) error) error) ([]*T, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	err := scan(func(key string, _ []byte) error {
+		id := key[strings.LastIndex(key, "/")+1:]
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		value, err := c.Get(id)
+		if errors.Is(err, ErrNotFound) {
+			log.Printf("WARNING: Index entry points to missing record %s", id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, value)
+	}
+	return results, nil
+}
+
+// termEscaper escapes '%' as well as '/', so a term containing "%2F"
+// stays distinct from one containing '/'
+var termEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// escapeTerm keeps '/' inside a term from being read as a separator
+func escapeTerm(term string) string {
+	return termEscaper.Replace(term)
+}
@@ -0,0 +1,235 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newIndexedTrades returns a trade collection indexed by ticker
+func newIndexedTrades(store Store) *Collection[testRecord] {
+	return NewCollection[testRecord](store, "trade_").
+		AddIndex("ticker", func(r *testRecord) []string { return []string{r.Ticker} })
+}
+
+// storedIndexKeys returns the index entries stored for the trade collection
+func storedIndexKeys(t *testing.T, store Store) []string {
+	t.Helper()
+	var keys []string
+	err := store.Scan(indexKeyPrefix+"trade_", func(key string, _ []byte) error {
+		keys = append(keys, strings.TrimPrefix(key, indexKeyPrefix))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	return keys
+}
+
+func TestEscapeTerm(t *testing.T) {
+	tests := []struct {
+		term, want string
+	}{
+		{"AAPL", "AAPL"},
+		{"BRK/B", "BRK%2FB"},
+		{"BRK%2FB", "BRK%252FB"},
+		{"50%", "50%25"},
+		{"%/", "%25%2F"},
+	}
+	for _, tt := range tests {
+		if got := escapeTerm(tt.term); got != tt.want {
+			t.Errorf("escapeTerm(%q) = %q, want %q", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestLookupKeepsEscapedTermsApart(t *testing.T) {
+	trades := NewCollection[testRecord](NewMemoryStore(), "trade_").
+		AddIndex("ticker", func(r *testRecord) []string { return []string{r.Ticker} })
+	records := []*testRecord{
+		{ID: "trade_1", Ticker: "BRK/B"},
+		{ID: "trade_2", Ticker: "BRK%2FB"},
+	}
+	for _, r := range records {
+		if err := trades.Put(r.ID, r); err != nil {
+			t.Fatalf("Put(%s): %v", r.ID, err)
+		}
+	}
+	for _, r := range records {
+		got, err := trades.Lookup("ticker", r.Ticker)
+		if err != nil {
+			t.Fatalf("Lookup(%s): %v", r.Ticker, err)
+		}
+		if !reflect.DeepEqual(got, []*testRecord{r}) {
+			t.Errorf("Lookup(%s) = %v, want only %s", r.Ticker, tickers(got), r.ID)
+		}
+	}
+}
+
+func TestIndexFollowsWrites(t *testing.T) {
+	store := NewMemoryStore()
+	trades := newIndexedTrades(store)
+	steps := []struct {
+		name  string
+		write func() error
+		want  []string
+	}{
+		{"put", func() error { return trades.Put("trade_1", &testRecord{ID: "trade_1", Ticker: "AAPL"}) },
+			[]string{"trade_ticker/AAPL/trade_1"}},
+		{"put another", func() error { return trades.Put("trade_2", &testRecord{ID: "trade_2", Ticker: "AAPL"}) },
+			[]string{"trade_ticker/AAPL/trade_1", "trade_ticker/AAPL/trade_2"}},
+		{"change term", func() error { return trades.Put("trade_1", &testRecord{ID: "trade_1", Ticker: "MSFT"}) },
+			[]string{"trade_ticker/AAPL/trade_2", "trade_ticker/MSFT/trade_1"}},
+		{"empty term", func() error { return trades.Put("trade_2", &testRecord{ID: "trade_2"}) },
+			[]string{"trade_ticker/MSFT/trade_1"}},
+		{"delete", func() error { return trades.Delete("trade_1") },
+			nil},
+	}
+	for _, step := range steps {
+		if err := step.write(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := storedIndexKeys(t, store); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("after %s: index holds %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestIndexWritesShareTheTransaction(t *testing.T) {
+	store := NewMemoryStore()
+	trades := newIndexedTrades(store)
+	if err := trades.Put("trade_1", &testRecord{ID: "trade_1", Ticker: "AAPL"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// A delete inside a failed transaction leaves record and index alone
+	errAbort := errors.New("abort")
+	err := store.Update(func(tx Tx) error {
+		if err := trades.DeleteTx(tx, "trade_1"); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Update error = %v, want %v", err, errAbort)
+	}
+	got, err := trades.Lookup("ticker", "AAPL")
+	if err != nil || len(got) != 1 {
+		t.Errorf("Lookup after rolled back delete = %v, %v; want trade_1", tickers(got), err)
+	}
+
+	// A committed one removes both
+	if err := store.Update(func(tx Tx) error { return trades.DeleteTx(tx, "trade_1") }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if keys := storedIndexKeys(t, store); len(keys) != 0 {
+		t.Errorf("index holds %v after delete, want nothing", keys)
+	}
+}
+
+func TestLookupAndRange(t *testing.T) {
+	trades := NewCollection[testRecord](NewMemoryStore(), "trade_").
+		AddIndex("ticker", func(r *testRecord) []string { return []string{r.Ticker} }).
+		AddIndex("tag", func(r *testRecord) []string { return []string{r.Ticker, "all", "all"} })
+	for i, ticker := range []string{"MSFT", "AAPL", "TSLA", "AMZN", "AAPL"} {
+		id := "trade_" + string(rune('1'+i))
+		if err := trades.Put(id, &testRecord{ID: id, Ticker: ticker}); err != nil {
+			t.Fatalf("Put(%s): %v", id, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query func() ([]*testRecord, error)
+		want  []string
+	}{
+		{"lookup", func() ([]*testRecord, error) { return trades.Lookup("ticker", "AAPL") }, []string{"AAPL", "AAPL"}},
+		{"lookup missing term", func() ([]*testRecord, error) { return trades.Lookup("ticker", "NVDA") }, nil},
+		{"lookup skips duplicate terms", func() ([]*testRecord, error) { return trades.Lookup("tag", "all") },
+			[]string{"MSFT", "AAPL", "TSLA", "AMZN", "AAPL"}},
+		{"range is inclusive", func() ([]*testRecord, error) { return trades.Range("ticker", "AMZN", "TSLA") },
+			[]string{"AMZN", "MSFT", "TSLA"}},
+		{"range open below", func() ([]*testRecord, error) { return trades.Range("ticker", "", "AMZN") },
+			[]string{"AAPL", "AAPL", "AMZN"}},
+		{"range open above", func() ([]*testRecord, error) { return trades.Range("ticker", "N", "") },
+			[]string{"TSLA"}},
+		{"range between terms", func() ([]*testRecord, error) { return trades.Range("ticker", "B", "L") }, nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.query()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(tickers(got), tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tickers(got), tt.want)
+		}
+	}
+
+	if _, err := trades.Lookup("sector", "Technology"); err == nil {
+		t.Error("Lookup on an unknown index succeeded")
+	}
+}
+
+func TestLookupSkipsMissingRecords(t *testing.T) {
+	store := NewMemoryStore()
+	trades := newIndexedTrades(store)
+	for _, id := range []string{"trade_1", "trade_2"} {
+		if err := trades.Put(id, &testRecord{ID: id, Ticker: "AAPL"}); err != nil {
+			t.Fatalf("Put(%s): %v", id, err)
+		}
+	}
+	// Removing the record behind the collection's back leaves a stale entry
+	if err := store.Delete("trade_1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	got, err := trades.Lookup("ticker", "AAPL")
+	if err != nil || len(got) != 1 || got[0].ID != "trade_2" {
+		t.Errorf("Lookup = %+v, %v; want only trade_2", got, err)
+	}
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	store := NewMemoryStore()
+	// Records written before the collection had an index
+	for _, r := range []*testRecord{{ID: "trade_1", Ticker: "AAPL"}, {ID: "trade_2", Ticker: "MSFT"}} {
+		if err := Set(store, r.ID, r); err != nil {
+			t.Fatalf("Set(%s): %v", r.ID, err)
+		}
+	}
+	trades := newIndexedTrades(store)
+	if got, _ := trades.Lookup("ticker", "AAPL"); len(got) != 0 {
+		t.Fatalf("Lookup before EnsureIndexes = %v, want nothing", tickers(got))
+	}
+
+	if err := trades.EnsureIndexes(); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+	want := []string{"trade_ticker/AAPL/trade_1", "trade_ticker/MSFT/trade_2"}
+	if got := storedIndexKeys(t, store); !reflect.DeepEqual(got, want) {
+		t.Errorf("index holds %v, want %v", got, want)
+	}
+
+	// A built index is not rebuilt, so records written around the
+	// collection stay out of it
+	if err := Set(store, "trade_3", &testRecord{ID: "trade_3", Ticker: "TSLA"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := trades.EnsureIndexes(); err != nil {
+		t.Fatalf("second EnsureIndexes: %v", err)
+	}
+	if got := storedIndexKeys(t, store); !reflect.DeepEqual(got, want) {
+		t.Errorf("index holds %v after second EnsureIndexes, want %v", got, want)
+	}
+
+	// A new index on the same collection is built on its own
+	tagged := newIndexedTrades(store).
+		AddIndex("id", func(r *testRecord) []string { return []string{r.ID} })
+	if err := tagged.EnsureIndexes(); err != nil {
+		t.Fatalf("EnsureIndexes with a new index: %v", err)
+	}
+	if got, err := tagged.Lookup("id", "trade_3"); err != nil || len(got) != 1 {
+		t.Errorf("Lookup on the new index = %v, %v; want trade_3", tickers(got), err)
+	}
+}
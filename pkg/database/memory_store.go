@@ -0,0 +1,134 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps everything in a map. It is intended
+// for tests and for running the app without touching disk.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+// Set stores a copy of value under key
+func (s *MemoryStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte{}, value...)
+	return nil
+}
+
+// Get returns a copy of the value stored under key
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, value...), nil
+}
+
+// Delete removes key from the store
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// Scan calls fn for every key with the given prefix in key order
+func (s *MemoryStore) Scan(prefix string, fn func(key string, value []byte) error) error {
+	return s.scan(func(key string) bool { return strings.HasPrefix(key, prefix) }, fn)
+}
+
+// ScanRange calls fn for every key in [start, end) in key order
+func (s *MemoryStore) ScanRange(start, end string, fn func(key string, value []byte) error) error {
+	return s.scan(func(key string) bool { return key >= start && (end == "" || key < end) }, fn)
+}
+
+// scan calls fn in key order for every key accepted by match
+func (s *MemoryStore) scan(match func(key string) bool, fn func(key string, value []byte) error) error {
+	// Snapshot matching entries so fn can write to the store
+	s.mu.RLock()
+	var keys []string
+	for key := range s.data {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		values[key] = append([]byte{}, s.data[key]...)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := fn(key, values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update runs fn with the store locked, buffering its writes and
+// applying them only if fn succeeds
+func (s *MemoryStore) Update(fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &memoryTx{store: s, writes: make(map[string][]byte)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for key, value := range tx.writes {
+		if value == nil {
+			delete(s.data, key)
+		} else {
+			s.data[key] = value
+		}
+	}
+	return nil
+}
+
+// memoryTx buffers writes for MemoryStore.Update; a nil value marks a delete
+type memoryTx struct {
+	store  *MemoryStore
+	writes map[string][]byte
+}
+
+func (t *memoryTx) Get(key string) ([]byte, error) {
+	value, ok := t.writes[key]
+	if !ok {
+		value, ok = t.store.data[key]
+	}
+	if !ok || value == nil {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, value...), nil
+}
+
+func (t *memoryTx) Set(key string, value []byte) error {
+	// Keep empty values distinguishable from deletes
+	t.writes[key] = append([]byte{}, value...)
+	return nil
+}
+
+func (t *memoryTx) Delete(key string) error {
+	t.writes[key] = nil
+	return nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,225 @@
+package database
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SchemaVersionKey holds the schema version the data was last migrated to
+const SchemaVersionKey = "meta_schema_version"
+
+// ErrSchemaTooNew is returned when the store was written by a newer
+// version of the app than this one knows how to read
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of the app")
+
+// Migration upgrades stored data from Version-1 to Version
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(store Store) error
+}
+
+// MigrateOptions controls how pending migrations are applied
+type MigrateOptions struct {
+	// DryRun applies the migrations to an in-memory copy of the store
+	// and reports the changes without touching the real data
+	DryRun bool
+	// BackupDir, if set, receives a full export of the store before the
+	// first migration is applied
+	BackupDir string
+}
+
+// MigrationReport describes what Migrate did (or would do on a dry run)
+type MigrationReport struct {
+	FromVersion int      `json:"fromVersion"`
+	ToVersion   int      `json:"toVersion"`
+	Applied     []string `json:"applied"`
+	DryRun      bool     `json:"dryRun"`
+	BackupPath  string   `json:"backupPath,omitempty"`
+	// Key counts from comparing the store before and after (dry run only)
+	Added   int `json:"added"`
+	Changed int `json:"changed"`
+	Removed int `json:"removed"`
+}
+
+var (
+	migrationsMu sync.Mutex
+	migrations   []Migration
+)
+
+// RegisterMigration adds a migration to the registry. Packages that own
+// the data register their migrations from init(); versions must be
+// unique and are applied in ascending order.
+func RegisterMigration(m Migration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	if m.Version <= 0 || m.Up == nil {
+		panic(fmt.Sprintf("database: invalid migration %d %q", m.Version, m.Name))
+	}
+	for _, existing := range migrations {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("database: duplicate migration version %d (%q and %q)", m.Version, existing.Name, m.Name))
+		}
+	}
+	migrations = append(migrations, m)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+}
+
+// LatestSchemaVersion returns the highest registered migration version
+func LatestSchemaVersion() int {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the schema version recorded in store (0 if none)
+func SchemaVersion(store Store) (int, error) {
+	raw, err := store.Get(SchemaVersionKey)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", raw, err)
+	}
+	return version, nil
+}
+
+// Migrate applies every registered migration newer than the store's
+// schema version. It refuses to touch a store whose version is newer
+// than LatestSchemaVersion.
+func Migrate(store Store, opts MigrateOptions) (*MigrationReport, error) {
+	from, err := SchemaVersion(store)
+	if err != nil {
+		return nil, err
+	}
+	latest := LatestSchemaVersion()
+	report := &MigrationReport{FromVersion: from, ToVersion: from, DryRun: opts.DryRun}
+
+	if from > latest {
+		return report, fmt.Errorf("%w (database version %d, app supports up to %d)", ErrSchemaTooNew, from, latest)
+	}
+
+	pending := pendingMigrations(from)
+	if len(pending) == 0 {
+		log.Printf("DEBUG: Database schema is up to date (version %d)", from)
+		return report, nil
+	}
+
+	target := store
+	var before map[string][]byte
+	if opts.DryRun {
+		log.Printf("DEBUG: Dry run: applying %d migrations to an in-memory copy", len(pending))
+		copyStore := NewMemoryStore()
+		if before, err = snapshot(store); err != nil {
+			return report, fmt.Errorf("failed to copy store for dry run: %w", err)
+		}
+		for key, value := range before {
+			copyStore.Set(key, value)
+		}
+		target = copyStore
+	} else if opts.BackupDir != "" && !isEmpty(store) {
+		path := filepath.Join(opts.BackupDir,
+			fmt.Sprintf("backup-v%d-%s.jsonl", from, time.Now().Format("20060102-150405")))
+		if err := Backup(store, path); err != nil {
+			return report, fmt.Errorf("failed to back up before migrating: %w", err)
+		}
+		report.BackupPath = path
+	}
+
+	for _, m := range pending {
+		log.Printf("DEBUG: Applying migration %d: %s", m.Version, m.Name)
+		if err := m.Up(target); err != nil {
+			log.Printf("ERROR: Migration %d (%s) failed: %v", m.Version, m.Name, err)
+			return report, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if err := target.Set(SchemaVersionKey, []byte(strconv.Itoa(m.Version))); err != nil {
+			return report, fmt.Errorf("failed to record schema version %d: %w", m.Version, err)
+		}
+		report.ToVersion = m.Version
+		report.Applied = append(report.Applied, fmt.Sprintf("%d: %s", m.Version, m.Name))
+	}
+
+	if opts.DryRun {
+		after, err := snapshot(target)
+		if err != nil {
+			return report, err
+		}
+		report.Added, report.Changed, report.Removed = diffSnapshots(before, after)
+		log.Printf("SUCCESS: Dry run migrated v%d -> v%d (%d added, %d changed, %d removed); no changes written",
+			report.FromVersion, report.ToVersion, report.Added, report.Changed, report.Removed)
+	} else {
+		log.Printf("SUCCESS: Migrated database schema v%d -> v%d", report.FromVersion, report.ToVersion)
+	}
+	return report, nil
+}
+
+// pendingMigrations returns the registered migrations newer than version
+func pendingMigrations(version int) []Migration {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version > version {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// errStopScan ends a scan early without reporting an error
+var errStopScan = errors.New("stop scan")
+
+// isEmpty reports whether the store holds no keys at all
+func isEmpty(store Store) bool {
+	empty := true
+	store.Scan("", func(string, []byte) error {
+		empty = false
+		return errStopScan
+	})
+	return empty
+}
+
+// snapshot reads every key in the store
+func snapshot(store Store) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	err := store.Scan("", func(key string, value []byte) error {
+		data[key] = value
+		return nil
+	})
+	return data, err
+}
+
+// diffSnapshots counts keys added, changed and removed between two snapshots
+func diffSnapshots(before, after map[string][]byte) (added, changed, removed int) {
+	for key, value := range after {
+		old, ok := before[key]
+		if !ok {
+			added++
+		} else if !bytes.Equal(old, value) {
+			changed++
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed++
+		}
+	}
+	return added, changed, removed
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// withMigrations replaces the registered migrations for one test
+func withMigrations(t *testing.T, ms ...Migration) {
+	t.Helper()
+	migrationsMu.Lock()
+	saved := migrations
+	migrations = nil
+	migrationsMu.Unlock()
+	t.Cleanup(func() {
+		migrationsMu.Lock()
+		migrations = saved
+		migrationsMu.Unlock()
+	})
+	for _, m := range ms {
+		RegisterMigration(m)
+	}
+}
+
+// testMigrations add a key, change one and remove one, in that order
+var testMigrations = []Migration{
+	{Version: 2, Name: "change", Up: func(s Store) error { return s.Set("kept", []byte("changed")) }},
+	{Version: 1, Name: "add", Up: func(s Store) error { return s.Set("added", []byte("1")) }},
+	{Version: 3, Name: "remove", Up: func(s Store) error { return s.Delete("gone") }},
+}
+
+// seedMigrationStore returns a store at schema version 0 holding two keys
+func seedMigrationStore(t *testing.T) Store {
+	t.Helper()
+	store := NewMemoryStore()
+	seedKeys(t, store, "gone", "kept")
+	return store
+}
+
+func TestMigrate(t *testing.T) {
+	withMigrations(t, testMigrations...)
+	store := seedMigrationStore(t)
+
+	report, err := Migrate(store, MigrateOptions{})
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	wantApplied := []string{"1: add", "2: change", "3: remove"}
+	if report.FromVersion != 0 || report.ToVersion != 3 || !reflect.DeepEqual(report.Applied, wantApplied) {
+		t.Errorf("report = %+v, want v0 -> v3 applying %v", report, wantApplied)
+	}
+	want := [][2]string{{"added", "1"}, {"kept", "changed"}, {SchemaVersionKey, "3"}}
+	if got := dump(t, store); !reflect.DeepEqual(got, want) {
+		t.Errorf("store holds %v, want %v", got, want)
+	}
+
+	again, err := Migrate(store, MigrateOptions{})
+	if err != nil || len(again.Applied) != 0 || again.ToVersion != 3 {
+		t.Errorf("second Migrate = %+v, %v; want nothing applied", again, err)
+	}
+}
+
+func TestMigrateStopsAtFailedMigration(t *testing.T) {
+	errBroken := errors.New("broken")
+	withMigrations(t, testMigrations[1], Migration{Version: 2, Name: "broken", Up: func(Store) error { return errBroken }})
+	store := seedMigrationStore(t)
+
+	report, err := Migrate(store, MigrateOptions{})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Migrate error = %v, want the migration's error", err)
+	}
+	if version, _ := SchemaVersion(store); version != 1 || report.ToVersion != 1 {
+		t.Errorf("schema version %d (report %d), want 1, the last migration that succeeded", version, report.ToVersion)
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	withMigrations(t, testMigrations...)
+	store := seedMigrationStore(t)
+	before := dump(t, store)
+
+	report, err := Migrate(store, MigrateOptions{DryRun: true, BackupDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	// The schema version key counts as added
+	if !report.DryRun || report.ToVersion != 3 || report.Added != 2 || report.Changed != 1 || report.Removed != 1 {
+		t.Errorf("report = %+v, want a dry run to v3 with 2 added, 1 changed and 1 removed", report)
+	}
+	if report.BackupPath != "" {
+		t.Errorf("dry run wrote a backup to %s", report.BackupPath)
+	}
+	if got := dump(t, store); !reflect.DeepEqual(got, before) {
+		t.Errorf("dry run changed the store to %v", got)
+	}
+}
+
+func TestMigrateBacksUpFirst(t *testing.T) {
+	withMigrations(t, testMigrations...)
+	tests := []struct {
+		name       string
+		store      func(t *testing.T) Store
+		wantBackup bool
+	}{
+		{"store with data", seedMigrationStore, true},
+		{"empty store", func(*testing.T) Store { return NewMemoryStore() }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.store(t)
+			before := dump(t, store)
+
+			report, err := Migrate(store, MigrateOptions{BackupDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			if !tt.wantBackup {
+				if report.BackupPath != "" {
+					t.Errorf("backed up an empty store to %s", report.BackupPath)
+				}
+				return
+			}
+			if _, err := os.Stat(report.BackupPath); err != nil {
+				t.Fatalf("backup %q: %v", report.BackupPath, err)
+			}
+			restored := NewMemoryStore()
+			if err := Restore(restored, report.BackupPath); err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+			if got := dump(t, restored); !reflect.DeepEqual(got, before) {
+				t.Errorf("backup holds %v, want the store before migrating %v", got, before)
+			}
+		})
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	withMigrations(t, testMigrations...)
+	store := seedMigrationStore(t)
+	if err := store.Set(SchemaVersionKey, []byte("4")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	before := dump(t, store)
+
+	for _, opts := range []MigrateOptions{{}, {DryRun: true}} {
+		if _, err := Migrate(store, opts); !errors.Is(err, ErrSchemaTooNew) {
+			t.Errorf("Migrate(%+v) error = %v, want ErrSchemaTooNew", opts, err)
+		}
+	}
+	if got := dump(t, store); !reflect.DeepEqual(got, before) {
+		t.Errorf("store changed to %v", got)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	store := NewMemoryStore()
+	values := map[string]string{
+		"trade_1":             `{"id":"trade_1","notes":"line one\nline two"}`,
+		"idx/trade_ticker/A/": "",
+		"meta_schema_version": "3",
+		"unicode":             "héllo ✓",
+	}
+	for key, value := range values {
+		if err := store.Set(key, []byte(value)); err != nil {
+			t.Fatalf("Set(%s): %v", key, err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "nested", "backup.jsonl")
+	if err := Backup(store, path); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	// Restore overwrites keys in the backup and leaves others alone
+	restored := NewMemoryStore()
+	seedKeys(t, restored, "trade_1", "other")
+	if err := Restore(restored, path); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	want := [][2]string{
+		{"idx/trade_ticker/A/", ""},
+		{"meta_schema_version", "3"},
+		{"other", "other"},
+		{"trade_1", values["trade_1"]},
+		{"unicode", values["unicode"]},
+	}
+	if got := dump(t, restored); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored store holds %v, want %v", got, want)
+	}
+
+	if err := Restore(NewMemoryStore(), filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("Restore of a missing file succeeded")
+	}
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore is a Store backed by a pure-Go SQLite database. Records
+// live in a single kv table whose value column holds the JSON document,
+// so ad-hoc SQL such as json_extract(value, '$.ticker') works against it.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS kv (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+) WITHOUT ROWID`
+
+// OpenSQLiteStore opens (or creates) the SQLite database file at path
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	log.Printf("DEBUG: Opening SQLite database at: %s", path)
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	// SQLite only allows one writer; a single connection avoids SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		log.Printf("ERROR: Failed to create SQLite schema: %v", err)
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+
+	log.Println("DEBUG: SQLite database initialized successfully")
+	return &SQLiteStore{db: db}, nil
+}
+
+// Set stores a key-value pair, replacing any existing value
+func (s *SQLiteStore) Set(key string, value []byte) error {
+	log.Printf("DEBUG: Setting key: %s (value size: %d bytes)", key, len(value))
+	_, err := s.db.Exec(
+		`INSERT INTO kv (key, value) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		key, string(value))
+	return err
+}
+
+// Get retrieves a value by key
+func (s *SQLiteStore) Get(key string) ([]byte, error) {
+	log.Printf("DEBUG: Getting key: %s", key)
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// Delete removes a key-value pair
+func (s *SQLiteStore) Delete(key string) error {
+	log.Printf("DEBUG: Deleting key: %s", key)
+	_, err := s.db.Exec(`DELETE FROM kv WHERE key = ?`, key)
+	return err
+}
+
+// Scan calls fn for every key with the given prefix in key order
+func (s *SQLiteStore) Scan(prefix string, fn func(key string, value []byte) error) error {
+	log.Printf("DEBUG: Scanning keys with prefix: %s", prefix)
+	return s.scanRange(prefix, prefixEnd(prefix), fn)
+}
+
+// ScanRange calls fn for every key in [start, end) in key order
+func (s *SQLiteStore) ScanRange(start, end string, fn func(key string, value []byte) error) error {
+	log.Printf("DEBUG: Scanning keys in range: [%s, %s)", start, end)
+	return s.scanRange(start, end, fn)
+}
+
+func (s *SQLiteStore) scanRange(start, end string, fn func(key string, value []byte) error) error {
+	var rows *sql.Rows
+	var err error
+	if end != "" {
+		rows, err = s.db.Query(`SELECT key, value FROM kv WHERE key >= ? AND key < ? ORDER BY key`, start, end)
+	} else {
+		rows, err = s.db.Query(`SELECT key, value FROM kv WHERE key >= ? ORDER BY key`, start)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Read everything before calling fn so fn can write with the single connection
+	type entry struct{ key, value string }
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.key, &e.value); err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := fn(e.key, []byte(e.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update runs fn inside an SQL transaction
+func (s *SQLiteStore) Update(fn func(tx Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(sqliteTx{tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqliteTx adapts an SQL transaction to Tx
+type sqliteTx struct {
+	tx *sql.Tx
+}
+
+func (t sqliteTx) Get(key string) ([]byte, error) {
+	var value string
+	err := t.tx.QueryRow(`SELECT value FROM kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+func (t sqliteTx) Set(key string, value []byte) error {
+	_, err := t.tx.Exec(
+		`INSERT INTO kv (key, value) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		key, string(value))
+	return err
+}
+
+func (t sqliteTx) Delete(key string) error {
+	_, err := t.tx.Exec(`DELETE FROM kv WHERE key = ?`, key)
+	return err
+}
+
+// Close closes the SQLite database
+func (s *SQLiteStore) Close() error {
+	log.Println("DEBUG: Closing SQLite connection...")
+	return s.db.Close()
+}
+
+// prefixEnd returns the smallest key greater than every key with the
+// given prefix, or "" when no such bound exists
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned by a Store when a key does not exist
+var ErrNotFound = errors.New("key not found")
+
+// ErrNotInitialized is returned when a nil Store is used
+var ErrNotInitialized = errors.New("database not initialized")
+
+// Store is the storage engine abstraction used by the repositories.
+// Values are opaque bytes (JSON-encoded by the helpers in this package)
+// and keys are ordered lexicographically so prefix scans return records
+// in key order on every engine.
+type Store interface {
+	// Set stores a value under key, replacing any existing value
+	Set(key string, value []byte) error
+	// Get returns the value stored under key or ErrNotFound
+	Get(key string) ([]byte, error)
+	// Delete removes key; deleting a missing key is not an error
+	Delete(key string) error
+	// Scan calls fn for every key with the given prefix in key order.
+	// Returning an error from fn stops the scan and returns that error.
+	Scan(prefix string, fn func(key string, value []byte) error) error
+	// ScanRange calls fn for every key in [start, end) in key order.
+	// An empty end means no upper bound.
+	ScanRange(start, end string, fn func(key string, value []byte) error) error
+	// Update runs fn in a read-write transaction. Writes made through tx
+	// are applied atomically when fn returns nil and discarded otherwise.
+	// fn must only touch the store through tx.
+	Update(fn func(tx Tx) error) error
+	// Close releases the resources held by the store
+	Close() error
+}
+
+// Tx is the view of a store inside an Update transaction
+type Tx interface {
+	// Get returns the value stored under key (including writes made
+	// earlier in the transaction) or ErrNotFound
+	Get(key string) ([]byte, error)
+	// Set stores a value under key
+	Set(key string, value []byte) error
+	// Delete removes key
+	Delete(key string) error
+}
@@ -0,0 +1,292 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// engines opens an empty store of every kind, closed when the test ends
+var engines = []struct {
+	name string
+	open func(t *testing.T) Store
+}{
+	{"badger", func(t *testing.T) Store {
+		store, err := OpenBadgerStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenBadgerStore: %v", err)
+		}
+		return store
+	}},
+	{"memory", func(t *testing.T) Store {
+		return NewMemoryStore()
+	}},
+	{"sqlite", func(t *testing.T) Store {
+		store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("OpenSQLiteStore: %v", err)
+		}
+		return store
+	}},
+}
+
+// forEachEngine runs fn against an empty store of every kind
+func forEachEngine(t *testing.T, fn func(t *testing.T, store Store)) {
+	for _, engine := range engines {
+		t.Run(engine.name, func(t *testing.T) {
+			store := engine.open(t)
+			t.Cleanup(func() { store.Close() })
+			fn(t, store)
+		})
+	}
+}
+
+// seedKeys stores each key with itself as the value
+func seedKeys(t *testing.T, store Store, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if err := store.Set(key, []byte(key)); err != nil {
+			t.Fatalf("Set(%s): %v", key, err)
+		}
+	}
+}
+
+// dump returns every key and value in the store, in scan order
+func dump(t *testing.T, store Store) [][2]string {
+	t.Helper()
+	var entries [][2]string
+	err := store.ScanRange("", "", func(key string, value []byte) error {
+		entries = append(entries, [2]string{key, string(value)})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanRange: %v", err)
+	}
+	return entries
+}
+
+func TestStoreSetGetDelete(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, store Store) {
+		if _, err := store.Get("missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+		}
+		if err := store.Delete("missing"); err != nil {
+			t.Errorf("Delete(missing) = %v, want nil", err)
+		}
+
+		steps := []struct {
+			op, key, value string
+			want           string // expected value after the step; "" means not found
+		}{
+			{"set", "k", "one", "one"},
+			{"set", "k", "two", "two"},
+			{"set", "k", `{"id":"k"}`, `{"id":"k"}`},
+			{"delete", "k", "", ""},
+		}
+		for _, step := range steps {
+			var err error
+			if step.op == "set" {
+				err = store.Set(step.key, []byte(step.value))
+			} else {
+				err = store.Delete(step.key)
+			}
+			if err != nil {
+				t.Fatalf("%s %s: %v", step.op, step.key, err)
+			}
+			got, err := store.Get(step.key)
+			switch {
+			case step.want == "" && !errors.Is(err, ErrNotFound):
+				t.Errorf("after %s %s: Get = %q, %v; want ErrNotFound", step.op, step.key, got, err)
+			case step.want != "" && (err != nil || string(got) != step.want):
+				t.Errorf("after %s %s: Get = %q, %v; want %q", step.op, step.key, got, err, step.want)
+			}
+		}
+	})
+}
+
+func TestStoreScanBounds(t *testing.T) {
+	keys := []string{"a", "a/1", "a/2", "a/3", "a0", "b/1", "trade_1", "trade_2", "tradex"}
+	tests := []struct {
+		name      string
+		scan      func(store Store, fn func(key string, value []byte) error) error
+		wantKeys  []string
+		stopAfter int // fn fails on this call; 0 never fails
+	}{
+		{"prefix", func(s Store, fn func(string, []byte) error) error { return s.Scan("a/", fn) },
+			[]string{"a/1", "a/2", "a/3"}, 0},
+		{"prefix matching the whole key", func(s Store, fn func(string, []byte) error) error { return s.Scan("a", fn) },
+			[]string{"a", "a/1", "a/2", "a/3", "a0"}, 0},
+		{"prefix with no match", func(s Store, fn func(string, []byte) error) error { return s.Scan("c", fn) },
+			nil, 0},
+		{"prefix ending in underscore", func(s Store, fn func(string, []byte) error) error { return s.Scan("trade_", fn) },
+			[]string{"trade_1", "trade_2"}, 0},
+		{"range is half open", func(s Store, fn func(string, []byte) error) error { return s.ScanRange("a/1", "a/3", fn) },
+			[]string{"a/1", "a/2"}, 0},
+		{"range start between keys", func(s Store, fn func(string, []byte) error) error { return s.ScanRange("a/15", "a0", fn) },
+			[]string{"a/2", "a/3"}, 0},
+		{"range without an end", func(s Store, fn func(string, []byte) error) error { return s.ScanRange("trade_2", "", fn) },
+			[]string{"trade_2", "tradex"}, 0},
+		{"empty range", func(s Store, fn func(string, []byte) error) error { return s.ScanRange("a/2", "a/2", fn) },
+			nil, 0},
+		{"error stops the scan", func(s Store, fn func(string, []byte) error) error { return s.Scan("a", fn) },
+			[]string{"a", "a/1"}, 2},
+	}
+
+	errStop := errors.New("stop")
+	forEachEngine(t, func(t *testing.T, store Store) {
+		seedKeys(t, store, keys...)
+		for _, tt := range tests {
+			var got []string
+			err := tt.scan(store, func(key string, value []byte) error {
+				if string(value) != key {
+					t.Errorf("%s: %s has value %q", tt.name, key, value)
+				}
+				got = append(got, key)
+				if len(got) == tt.stopAfter {
+					return errStop
+				}
+				return nil
+			})
+			if tt.stopAfter > 0 && !errors.Is(err, errStop) {
+				t.Errorf("%s: error = %v, want fn's error", tt.name, err)
+			} else if tt.stopAfter == 0 && err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("%s: scanned %v, want %v", tt.name, got, tt.wantKeys)
+			}
+		}
+	})
+}
+
+func TestStoreUpdate(t *testing.T) {
+	errAbort := errors.New("abort")
+	tests := []struct {
+		name string
+		fn   func(tx Tx) error
+		err  error
+		want [][2]string
+	}{
+		{
+			name: "commits every write",
+			fn: func(tx Tx) error {
+				if err := tx.Set("new", []byte("1")); err != nil {
+					return err
+				}
+				if err := tx.Set("kept", []byte("changed")); err != nil {
+					return err
+				}
+				return tx.Delete("gone")
+			},
+			want: [][2]string{{"kept", "changed"}, {"new", "1"}},
+		},
+		{
+			name: "rolls back on error",
+			fn: func(tx Tx) error {
+				if err := tx.Set("new", []byte("1")); err != nil {
+					return err
+				}
+				if err := tx.Delete("gone"); err != nil {
+					return err
+				}
+				return errAbort
+			},
+			err:  errAbort,
+			want: [][2]string{{"gone", "gone"}, {"kept", "kept"}},
+		},
+		{
+			name: "reads its own writes",
+			fn: func(tx Tx) error {
+				if err := tx.Set("new", []byte("1")); err != nil {
+					return err
+				}
+				if got, err := tx.Get("new"); err != nil || string(got) != "1" {
+					return errors.New("write not visible in the transaction")
+				}
+				if err := tx.Delete("gone"); err != nil {
+					return err
+				}
+				if _, err := tx.Get("gone"); !errors.Is(err, ErrNotFound) {
+					return errors.New("delete not visible in the transaction")
+				}
+				if got, err := tx.Get("kept"); err != nil || string(got) != "kept" {
+					return errors.New("stored value not visible in the transaction")
+				}
+				return nil
+			},
+			want: [][2]string{{"kept", "kept"}, {"new", "1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachEngine(t, func(t *testing.T, store Store) {
+				seedKeys(t, store, "gone", "kept")
+				if err := store.Update(tt.fn); !errors.Is(err, tt.err) {
+					t.Fatalf("Update error = %v, want %v", err, tt.err)
+				}
+				if got := dump(t, store); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("store holds %v, want %v", got, tt.want)
+				}
+			})
+		})
+	}
+}
+
+func TestStoreEnginesAgree(t *testing.T) {
+	// The same writes, including JSON values, index-style keys and an
+	// empty value, leave every engine with the same contents in the
+	// same order
+	errRolledBack := errors.New("rolled back")
+	run := func(store Store) error {
+		if err := Set(store, "trade_01", map[string]any{"id": "trade_01", "ticker": "AAPL"}); err != nil {
+			return err
+		}
+		if err := store.Set("idx/trade_ticker/AAPL/trade_01", nil); err != nil {
+			return err
+		}
+		if err := store.Set("trade_02", []byte("{}")); err != nil {
+			return err
+		}
+		if err := store.Update(func(tx Tx) error {
+			if err := tx.Delete("trade_02"); err != nil {
+				return err
+			}
+			return tx.Set("trade_03", []byte(`{"id":"trade_03"}`))
+		}); err != nil {
+			return err
+		}
+		err := store.Update(func(tx Tx) error {
+			if err := tx.Set("trade_04", []byte("{}")); err != nil {
+				return err
+			}
+			return errRolledBack
+		})
+		if !errors.Is(err, errRolledBack) {
+			return fmt.Errorf("rolled back update returned %v", err)
+		}
+		return nil
+	}
+
+	var want [][2]string
+	for _, engine := range engines {
+		t.Run(engine.name, func(t *testing.T) {
+			store := engine.open(t)
+			defer store.Close()
+			if err := run(store); err != nil {
+				t.Fatalf("writes failed: %v", err)
+			}
+			got := dump(t, store)
+			if len(got) != 3 {
+				t.Errorf("store holds %v, want 3 keys", got)
+			}
+			if want == nil {
+				want = got
+			} else if !reflect.DeepEqual(got, want) {
+				t.Errorf("store holds %v, want the same as %s: %v", got, engines[0].name, want)
+			}
+		})
+	}
+}
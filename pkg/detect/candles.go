@@ -0,0 +1,154 @@
+package detect
+
+import (
+	"fmt"
+	"math"
+
+	"trading-dashboard/pkg/marketdata"
+)
+
+// gapKind is the way a gap is read
+type gapKind int
+
+const (
+	gapBreakaway  gapKind = iota // Leaves a consolidation range
+	gapRunaway                   // Continues a trend
+	gapExhaustion                // Ends an extended trend and gets filled
+)
+
+// minGap is the smallest gap counted, as a fraction of price
+const minGap = 0.005
+
+// gap reads the most recent gap of the last 10 bars as kind. Breakaway
+// and runaway gaps point the way they gapped; exhaustion gaps point back.
+func gap(bars []marketdata.Bar, kind gapKind) *Detection {
+	n := len(bars)
+	gi := -1
+	up := false
+	for i := n - 1; i >= max(1, n-10) && gi < 0; i-- {
+		switch {
+		case bars[i].Low > bars[i-1].High*(1+minGap):
+			gi, up = i, true
+		case bars[i].High < bars[i-1].Low*(1-minGap):
+			gi, up = i, false
+		}
+	}
+	if gi < 11 {
+		return nil
+	}
+
+	s := sign(up)
+	top, bottom := bars[gi].Low, bars[gi-1].High
+	if !up {
+		top, bottom = bars[gi-1].Low, bars[gi].High
+	}
+	// An up gap is filled once price trades back to its bottom, a down gap to its top
+	filled := false
+	if gi < n-1 {
+		if up {
+			filled = adverse(bars, gi+1, n-1, true) <= bottom
+		} else {
+			filled = adverse(bars, gi+1, n-1, false) >= top
+		}
+	}
+	from := max(0, gi-20)
+	volume := volumeScore(volumeRatio(bars, gi, 20))
+	move := s * (bars[gi-1].Close - bars[from].Close) / bars[from].Close
+	unfilled := 1.0
+	if filled {
+		unfilled = 0
+	}
+
+	var confidence float64
+	var summary string
+	bullish := up
+	invalidation := bottom
+	if !up {
+		invalidation = top
+	}
+	switch kind {
+	case gapBreakaway:
+		_, rangeHigh := highest(bars, from, gi-1)
+		_, rangeLow := lowest(bars, from, gi-1)
+		width := (rangeHigh - rangeLow) / bars[gi-1].Close
+		if up && top <= rangeHigh || !up && bottom >= rangeLow || width > 0.12 {
+			return nil
+		}
+		confidence = 0.4*(1-width/0.12) + 0.3*volume + 0.3*unfilled
+		summary = fmt.Sprintf("Gap out of a %.1f%% range of %d bars", width*100, gi-from)
+	case gapRunaway:
+		if move < 0.05 || filled {
+			return nil
+		}
+		confidence = 0.4*clamp01(move/0.15) + 0.3*volume + 0.3*unfilled
+		summary = fmt.Sprintf("Gap %.1f%% into a move of %d bars", move*100, gi-from)
+	case gapExhaustion:
+		from = max(0, gi-30)
+		move = s * (bars[gi-1].Close - bars[from].Close) / bars[from].Close
+		if move < 0.10 {
+			return nil
+		}
+		confidence = 0.4*clamp01(move/0.3) + 0.3*volume + 0.3*(1-unfilled)
+		summary = fmt.Sprintf("Gap after a %.1f%% move of %d bars", move*100, gi-from)
+		if filled {
+			summary += ", since filled"
+		}
+		bullish = !up
+		invalidation = bars[gi].High
+		if !up {
+			invalidation = bars[gi].Low
+		}
+	}
+
+	return &Detection{
+		Bias:       biasOf(bullish),
+		Confidence: confidence,
+		Start:      bars[gi-1].Time,
+		Levels: []Level{
+			{LevelGapTop, top},
+			{LevelGapBottom, bottom},
+			{LevelInvalidation, invalidation},
+		},
+		Summary: fmt.Sprintf("%s on %s (%.1f%%)", summary, bars[gi].Time.Format("2006-01-02"), (top-bottom)/bottom*100),
+	}
+}
+
+// engulfing finds a candle in the last three whose body engulfs the
+// opposite-coloured body before it. Confidence grows with how much larger
+// the body is, the move it reverses and its volume.
+func engulfing(bars []marketdata.Bar, bullish bool) *Detection {
+	n := len(bars)
+	s := sign(bullish)
+	for i := n - 1; i >= n-3; i-- {
+		prev, cur := bars[i-1], bars[i]
+		prevBody, curBody := s*(prev.Open-prev.Close), s*(cur.Close-cur.Open)
+		if prevBody <= 0 || curBody <= prevBody {
+			continue
+		}
+		if s*(cur.Open-prev.Close) > 0 || s*(cur.Close-prev.Open) < 0 {
+			continue
+		}
+		move := -s * (prev.Close - bars[i-6].Close) / bars[i-6].Close
+		confidence := 0.4*clamp01(curBody/prevBody-1) + 0.35*clamp01(move/0.05) +
+			0.25*volumeScore(volumeRatio(bars, i, 10))
+
+		invalidation := math.Min(prev.Low, cur.Low)
+		confirmation := cur.High
+		if !bullish {
+			invalidation = math.Max(prev.High, cur.High)
+			confirmation = cur.Low
+		}
+		return &Detection{
+			Bias:       biasOf(bullish),
+			Confidence: confidence,
+			Start:      prev.Time,
+			Levels: []Level{
+				{LevelBreakout, confirmation},
+				{LevelInvalidation, invalidation},
+			},
+			Summary: fmt.Sprintf("Body on %s is %.1fx the one before, after a %.1f%% move the other way",
+				cur.Time.Format("2006-01-02"), curBody/prevBody, move*100),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package detect
+
+import (
+	"fmt"
+	"math"
+
+	"trading-dashboard/pkg/marketdata"
+)
+
+// Trendline settings
+const (
+	trendBars  = 60    // Bars the trendlines are fitted over
+	flatSlope  = 0.001 // Slopes under 0.1% of price per bar count as flat
+	trendWidth = 2     // Pivot width for trendline touches
+)
+
+// trendlines fits lines through the swing highs and lows of the last
+// trendBars bars. It returns false unless each line has two touches and
+// one of them is recent.
+func trendlines(bars []marketdata.Bar) (highs, lows []pivot, upper, lower line, ok bool) {
+	n := len(bars)
+	highs = since(swingHighs(bars, trendWidth), n-trendBars)
+	lows = since(swingLows(bars, trendWidth), n-trendBars)
+	if len(highs) < 2 || len(lows) < 2 {
+		return nil, nil, line{}, line{}, false
+	}
+	if max(highs[len(highs)-1].i, lows[len(lows)-1].i) < n-1-20 {
+		return nil, nil, line{}, line{}, false
+	}
+	return highs, lows, fitLine(highs), fitLine(lows), true
+}
+
+// touchScore scores the number of trendline touches: 0.5 at the minimum
+// of four, 1 from seven
+func touchScore(highs, lows []pivot) float64 {
+	return clamp01(0.5 + float64(len(highs)+len(lows)-4)/6)
+}
+
+// triangle finds an ascending triangle (flat highs, rising lows) or a
+// descending one (flat lows, falling highs)
+func triangle(bars []marketdata.Bar, ascending bool) *Detection {
+	highs, lows, upper, lower, ok := trendlines(bars)
+	if !ok {
+		return nil
+	}
+	n := len(bars)
+	price := bars[n-1].Close
+	hs, ls := upper.slope/price, lower.slope/price
+
+	flat, sloped, moving := upper, lower, ls
+	flatPivots := highs
+	if !ascending {
+		flat, sloped, moving = lower, upper, -hs
+		flatPivots = lows
+	}
+	if math.Abs(flat.slope/price) > flatSlope || moving < flatSlope {
+		return nil
+	}
+
+	level := 0.0
+	for _, p := range flatPivots {
+		level += p.price
+	}
+	level /= float64(len(flatPivots))
+	edge := sloped.at(n - 1)
+
+	// Inside the triangle or through the flat side counts; through the sloped side breaks it
+	s := sign(ascending)
+	position := 1.0
+	if s*(price-edge) < 0 {
+		position = 0
+	}
+	quality := clamp01(1 - (upper.fit+lower.fit)/2/0.02)
+	confidence := 0.35*quality + 0.25*touchScore(highs, lows) + 0.2*clamp01(moving/0.004) + 0.2*position
+
+	start := min(highs[0].i, lows[0].i)
+	height := math.Abs(level - sloped.at(start))
+	levels := []Level{
+		{LevelResistance, level},
+		{LevelSupport, edge},
+		{LevelBreakout, level},
+		{LevelTarget, level + height},
+		{LevelInvalidation, lows[len(lows)-1].price},
+	}
+	if !ascending {
+		levels = []Level{
+			{LevelSupport, level},
+			{LevelResistance, edge},
+			{LevelBreakout, level},
+			{LevelTarget, level - height},
+			{LevelInvalidation, highs[len(highs)-1].price},
+		}
+	}
+	return &Detection{
+		Bias:       biasOf(ascending),
+		Confidence: confidence,
+		Start:      bars[start].Time,
+		Levels:     levels,
+		Summary: fmt.Sprintf("%d highs and %d lows since %s; flat side at %.2f, other side moving %.2f%% a bar",
+			len(highs), len(lows), bars[start].Time.Format("2006-01-02"), level, moving*100),
+	}
+}
+
+// wedge finds a rising wedge (both lines rising, lows faster) or a
+// falling one (both falling, highs faster). Rising wedges are bearish.
+func wedge(bars []marketdata.Bar, rising bool) *Detection {
+	highs, lows, upper, lower, ok := trendlines(bars)
+	if !ok {
+		return nil
+	}
+	n := len(bars)
+	price := bars[n-1].Close
+	hs, ls := upper.slope/price, lower.slope/price
+	convergence := ls - hs
+	if rising && (hs < flatSlope || ls < flatSlope) || !rising && (hs > -flatSlope || ls > -flatSlope) || convergence <= 0 {
+		return nil
+	}
+
+	quality := clamp01(1 - (upper.fit+lower.fit)/2/0.02)
+	confidence := 0.4*quality + 0.3*clamp01(convergence/0.003) + 0.3*touchScore(highs, lows)
+
+	start := min(highs[0].i, lows[0].i)
+	levels := []Level{
+		{LevelResistance, upper.at(n - 1)},
+		{LevelSupport, lower.at(n - 1)},
+		{LevelBreakout, lower.at(n - 1)},
+		{LevelTarget, lows[0].price},
+		{LevelInvalidation, highs[len(highs)-1].price},
+	}
+	if !rising {
+		levels = []Level{
+			{LevelResistance, upper.at(n - 1)},
+			{LevelSupport, lower.at(n - 1)},
+			{LevelBreakout, upper.at(n - 1)},
+			{LevelTarget, highs[0].price},
+			{LevelInvalidation, lows[len(lows)-1].price},
+		}
+	}
+	return &Detection{
+		Bias:       biasOf(!rising),
+		Confidence: confidence,
+		Start:      bars[start].Time,
+		Levels:     levels,
+		Summary: fmt.Sprintf("Highs moving %.2f%% and lows %.2f%% a bar since %s",
+			hs*100, ls*100, bars[start].Time.Format("2006-01-02")),
+	}
+}
+
+// flag finds a pole of at least 8% in 3-15 bars followed by 3-20 bars of
+// consolidation that gives back at most half of it
+func flag(bars []marketdata.Bar, bullish bool) *Detection {
+	n := len(bars)
+	if n < 40 {
+		return nil
+	}
+	s := sign(bullish)
+	tipI, tip := counter(bars, n-25, n-1, bullish)
+	if n-1-tipI < 3 || n-1-tipI > 20 || tipI < 15 {
+		return nil
+	}
+	baseI, base := counter(bars, tipI-15, tipI-3, !bullish)
+	pole := math.Abs(tip-base) / base
+	if pole < 0.08 {
+		return nil
+	}
+	giveBack := adverse(bars, tipI+1, n-1, bullish)
+	retrace := s * (tip - giveBack) / math.Abs(tip-base)
+	if retrace > 0.5 {
+		return nil
+	}
+
+	// A flag drifts sideways or against the pole
+	drift := s * (bars[n-1].Close - bars[tipI+1].Close) / bars[tipI+1].Close / float64(n-1-tipI)
+	driftScore := 1.0
+	if drift > 0 {
+		driftScore = clamp01(1 - drift/0.005)
+	}
+	length := n - 1 - tipI
+	lengthScore := 1.0
+	if length < 5 || length > 15 {
+		lengthScore = 0.5
+	}
+	confidence := 0.35*clamp01(pole/0.15) + 0.35*(1-retrace/0.5) + 0.15*lengthScore + 0.15*driftScore
+
+	return &Detection{
+		Bias:       biasOf(bullish),
+		Confidence: confidence,
+		Start:      bars[baseI].Time,
+		Levels: []Level{
+			{LevelBreakout, tip},
+			{LevelTarget, tip + s*math.Abs(tip-base)},
+			{LevelInvalidation, giveBack},
+		},
+		Summary: fmt.Sprintf("%.1f%% pole over %d bars, then %d bars giving back %.0f%% of it",
+			pole*100, tipI-baseI, length, retrace*100),
+	}
+}
+
+// base finds 5-20 bars trading in a range of at most 8% near the 60-bar
+// high (high base) or low (low base)
+func base(bars []marketdata.Bar, high bool) *Detection {
+	n := len(bars)
+	price := bars[n-1].Close
+	w := 0
+	var top, bottom float64
+	for size := 5; size <= 20 && size <= n; size++ {
+		_, t := highest(bars, n-size, n-1)
+		_, b := lowest(bars, n-size, n-1)
+		if (t-b)/price > 0.08 {
+			break
+		}
+		w, top, bottom = size, t, b
+	}
+	if w == 0 {
+		return nil
+	}
+
+	from := max(0, n-60)
+	_, hi := highest(bars, from, n-1)
+	_, lo := lowest(bars, from, n-1)
+	// The base must sit nearer the end of the range it is named for
+	near, far := (hi-top)/hi, (bottom-lo)/lo
+	if !high {
+		near, far = far, near
+	}
+	proximity := clamp01(1 - near/0.05)
+	if proximity == 0 || near >= far {
+		return nil
+	}
+
+	// The move into the base: up for a high base, down for a low one
+	trend := 0.5
+	if i := n - 1 - w; i >= 30 {
+		move := (bars[i].Close - bars[i-30].Close) / bars[i-30].Close
+		trend = clamp01(sign(high) * move / 0.2)
+	}
+	width := (top - bottom) / price
+	confidence := 0.35*(1-width/0.08) + 0.25*clamp01(float64(w)/10) + 0.2*proximity + 0.2*trend
+
+	levels := []Level{
+		{LevelResistance, top},
+		{LevelSupport, bottom},
+		{LevelBreakout, top},
+		{LevelInvalidation, bottom},
+	}
+	return &Detection{
+		Bias:       biasOf(true),
+		Confidence: confidence,
+		Start:      bars[n-w].Time,
+		Levels:     levels,
+		Summary:    fmt.Sprintf("%d bars in a %.1f%% range between %.2f and %.2f", w, width*100, bottom, top),
+	}
+}
+
+// pullback finds a dip of 2-12% from the 15-bar high in an uptrend (20-day
+// average over a rising 50-day) that holds the 50-day, or the mirror
+// image rally in a downtrend
+func pullback(bars []marketdata.Bar, bullish bool) *Detection {
+	n := len(bars)
+	if n < 60 {
+		return nil
+	}
+	s := sign(bullish)
+	price := bars[n-1].Close
+	fast, slow, slowBefore := sma(bars, n-1, 20), sma(bars, n-1, 50), sma(bars, n-11, 50)
+	if s*(fast-slow) <= 0 || s*(slow-slowBefore) <= 0 || s*(price-slow) <= 0 {
+		return nil
+	}
+	extremeI, extreme := counter(bars, n-15, n-1, bullish)
+	depth := s * (extreme - price) / extreme
+	if depth < 0.02 || depth > 0.12 || extremeI == n-1 {
+		return nil
+	}
+
+	depthScore := 1.0
+	if depth < 0.03 || depth > 0.08 {
+		depthScore = 0.6
+	}
+	confidence := 0.3*clamp01(s*(fast/slow-1)/0.05) + 0.3*(1-clamp01(math.Abs(price-fast)/fast/0.04)) +
+		0.2*depthScore + 0.2*clamp01(float64(n-1-extremeI)/3)
+
+	levels := []Level{
+		{LevelResistance, extreme},
+		{LevelSupport, fast},
+		{LevelTarget, extreme},
+		{LevelInvalidation, slow},
+	}
+	if !bullish {
+		levels = []Level{
+			{LevelSupport, extreme},
+			{LevelResistance, fast},
+			{LevelTarget, extreme},
+			{LevelInvalidation, slow},
+		}
+	}
+	return &Detection{
+		Bias:       biasOf(bullish),
+		Confidence: confidence,
+		Start:      bars[extremeI].Time,
+		Levels:     levels,
+		Summary: fmt.Sprintf("%.1f%% off the %.2f extreme of %s; 20-day %.2f, 50-day %.2f",
+			depth*100, extreme, bars[extremeI].Time.Format("2006-01-02"), fast, slow),
+	}
+}
@@ -0,0 +1,217 @@
+// Package detect finds the library's chart patterns in daily price bars,
+// so the backend can suggest a pattern for a rating or challenge the one
+// picked
+package detect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/marketdata"
+	"trading-dashboard/pkg/models"
+)
+
+// MinConfidence is the confidence a detection needs to count as found
+const MinConfidence = 0.5
+
+// minBars is the fewest bars any detector looks at
+const minBars = 20
+
+// Key price level names
+const (
+	LevelSupport      = "support"
+	LevelResistance   = "resistance"
+	LevelNeckline     = "neckline"
+	LevelBreakout     = "breakout"
+	LevelTarget       = "target"
+	LevelInvalidation = "invalidation" // Trading through it breaks the pattern
+	LevelGapTop       = "gapTop"
+	LevelGapBottom    = "gapBottom"
+)
+
+// Level is a key price of a detected pattern
+type Level struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+// Detection is a chart pattern found in the bars. Bias is the direction
+// the pattern points to; gaps, neutral in the library, take theirs from
+// the gap.
+type Detection struct {
+	Pattern    string    `json:"pattern"`    // Library pattern name
+	Bias       string    `json:"bias"`       // bullish or bearish
+	Confidence float64   `json:"confidence"` // 0 to 1
+	Start      time.Time `json:"start"`      // First bar of the pattern
+	End        time.Time `json:"end"`        // Last bar looked at
+	Levels     []Level   `json:"levels"`
+	Summary    string    `json:"summary"`
+}
+
+// Level returns the price of the named level and whether the detection has it
+func (d *Detection) Level(name string) (float64, bool) {
+	for _, level := range d.Levels {
+		if level.Name == name {
+			return level.Price, true
+		}
+	}
+	return 0, false
+}
+
+// detector looks for one pattern ending at the last bar. It returns nil
+// when the bars don't have the pattern's shape.
+type detector func(bars []marketdata.Bar) *Detection
+
+// detectors in library order
+var detectors = []struct {
+	pattern string
+	detect  detector
+}{
+	{models.PatternHighBase, func(b []marketdata.Bar) *Detection { return base(b, true) }},
+	{models.PatternLowBase, func(b []marketdata.Bar) *Detection { return base(b, false) }},
+	{models.PatternAscendingTriangle, func(b []marketdata.Bar) *Detection { return triangle(b, true) }},
+	{models.PatternDescendingTriangle, func(b []marketdata.Bar) *Detection { return triangle(b, false) }},
+	{models.PatternBullPullback, func(b []marketdata.Bar) *Detection { return pullback(b, true) }},
+	{models.PatternBearRally, func(b []marketdata.Bar) *Detection { return pullback(b, false) }},
+	{models.PatternDoubleTop, func(b []marketdata.Bar) *Detection { return double(b, false) }},
+	{models.PatternCupAndHandle, cupAndHandle},
+	{models.PatternHeadAndShoulders, func(b []marketdata.Bar) *Detection { return headAndShoulders(b, false) }},
+	{models.PatternInverseHeadAndShoulders, func(b []marketdata.Bar) *Detection { return headAndShoulders(b, true) }},
+	{models.PatternBullishFlag, func(b []marketdata.Bar) *Detection { return flag(b, true) }},
+	{models.PatternBearishFlag, func(b []marketdata.Bar) *Detection { return flag(b, false) }},
+	{models.PatternRisingWedge, func(b []marketdata.Bar) *Detection { return wedge(b, true) }},
+	{models.PatternFallingWedge, func(b []marketdata.Bar) *Detection { return wedge(b, false) }},
+	{models.PatternDoubleBottom, func(b []marketdata.Bar) *Detection { return double(b, true) }},
+	{models.PatternRoundingBottom, roundingBottom},
+	{models.PatternBreakawayGap, func(b []marketdata.Bar) *Detection { return gap(b, gapBreakaway) }},
+	{models.PatternRunawayGap, func(b []marketdata.Bar) *Detection { return gap(b, gapRunaway) }},
+	{models.PatternExhaustionGap, func(b []marketdata.Bar) *Detection { return gap(b, gapExhaustion) }},
+	{models.PatternBullishEngulfing, func(b []marketdata.Bar) *Detection { return engulfing(b, true) }},
+	{models.PatternBearishEngulfing, func(b []marketdata.Bar) *Detection { return engulfing(b, false) }},
+}
+
+// Patterns lists the names of the patterns that can be detected
+func Patterns() []string {
+	names := make([]string, len(detectors))
+	for i, d := range detectors {
+		names[i] = d.pattern
+	}
+	return names
+}
+
+// Detect runs every detector over bars (oldest first) and returns the
+// patterns found with at least MinConfidence, most confident first
+func Detect(bars []marketdata.Bar) []Detection {
+	found := []Detection{}
+	for _, d := range detectors {
+		detection := run(d.pattern, d.detect, bars)
+		if detection != nil && detection.Confidence >= MinConfidence {
+			found = append(found, *detection)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Confidence > found[j].Confidence
+	})
+	return found
+}
+
+// DetectPattern runs the detector for the named pattern (case-insensitive)
+// over bars. It returns nil when the pattern is not found at all, and
+// false when there is no detector for it.
+func DetectPattern(bars []marketdata.Bar, pattern string) (*Detection, bool) {
+	for _, d := range detectors {
+		if strings.EqualFold(d.pattern, strings.TrimSpace(pattern)) {
+			return run(d.pattern, d.detect, bars), true
+		}
+	}
+	return nil, false
+}
+
+// run calls a detector, filling in the fields every detection shares
+func run(pattern string, detect detector, bars []marketdata.Bar) *Detection {
+	if len(bars) < minBars {
+		return nil
+	}
+	detection := detect(bars)
+	if detection == nil {
+		return nil
+	}
+	detection.Pattern = pattern
+	detection.Confidence = round2(clamp01(detection.Confidence))
+	detection.End = bars[len(bars)-1].Time
+	for i := range detection.Levels {
+		detection.Levels[i].Price = round2(detection.Levels[i].Price)
+	}
+	return detection
+}
+
+// Review is the check of a rating's picked pattern against the bars
+type Review struct {
+	Pattern     string      `json:"pattern"`     // Pattern picked for the rating
+	Supported   bool        `json:"supported"`   // Found with at least MinConfidence
+	Detection   *Detection  `json:"detection"`   // The picked pattern as found in the bars; nil if not found at all
+	Suggestions []Detection `json:"suggestions"` // Other patterns found, most confident first
+	Bars        int         `json:"bars"`        // Number of bars looked at
+	Message     string      `json:"message"`
+}
+
+// ReviewPattern checks pattern against bars and suggests the other
+// patterns found in them. An empty pattern only gets suggestions.
+func ReviewPattern(bars []marketdata.Bar, pattern string) *Review {
+	review := &Review{Pattern: strings.TrimSpace(pattern), Suggestions: []Detection{}, Bars: len(bars)}
+	for _, d := range detectors {
+		if strings.EqualFold(d.pattern, review.Pattern) {
+			review.Pattern = d.pattern
+		}
+	}
+	for _, detection := range Detect(bars) {
+		if !strings.EqualFold(detection.Pattern, review.Pattern) {
+			review.Suggestions = append(review.Suggestions, detection)
+		}
+	}
+
+	var best string
+	if len(review.Suggestions) > 0 {
+		top := review.Suggestions[0]
+		best = fmt.Sprintf("%s (confidence %.2f)", top.Pattern, top.Confidence)
+	}
+
+	if review.Pattern == "" {
+		if best == "" {
+			review.Message = "No pattern picked and none found in the price history"
+		} else {
+			review.Message = "No pattern picked; the price history suggests " + best
+		}
+		return review
+	}
+
+	detection, ok := DetectPattern(bars, review.Pattern)
+	switch {
+	case !ok:
+		review.Message = fmt.Sprintf("%s can't be detected automatically", review.Pattern)
+		if best != "" {
+			review.Message += "; the price history suggests " + best
+		}
+		return review
+	case len(bars) < minBars:
+		review.Message = fmt.Sprintf("Only %d bars of price history; at least %d are needed to check %s", len(bars), minBars, review.Pattern)
+		return review
+	}
+
+	review.Detection = detection
+	review.Supported = detection != nil && detection.Confidence >= MinConfidence
+	switch {
+	case review.Supported:
+		review.Message = fmt.Sprintf("%s confirmed with confidence %.2f", detection.Pattern, detection.Confidence)
+	case detection != nil:
+		review.Message = fmt.Sprintf("%s is only a weak match (confidence %.2f)", detection.Pattern, detection.Confidence)
+	default:
+		review.Message = fmt.Sprintf("%s not found in the price history", review.Pattern)
+	}
+	if !review.Supported && best != "" {
+		review.Message += "; it looks more like " + best
+	}
+	return review
+}
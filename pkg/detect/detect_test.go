@@ -0,0 +1,157 @@
+package detect
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/marketdata"
+	"trading-dashboard/pkg/models"
+)
+
+// series turns closes into daily bars opening at the previous close,
+// with highs and lows half a percent beyond the body
+func series(closes []float64) []marketdata.Bar {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]marketdata.Bar, len(closes))
+	for i, c := range closes {
+		o := c
+		if i > 0 {
+			o = closes[i-1]
+		}
+		bars[i] = marketdata.Bar{Ticker: "TEST", Interval: marketdata.Daily, Time: start.AddDate(0, 0, i),
+			Open: o, High: math.Max(o, c) * 1.005, Low: math.Min(o, c) * 0.995, Close: c, Volume: 1000}
+	}
+	return bars
+}
+
+// path draws closes through (bars, price) points, each leg taking its
+// point's number of bars, with a small wiggle so swings are not flat
+func path(points ...[2]float64) []float64 {
+	var closes []float64
+	for k := 0; k+1 < len(points); k++ {
+		from, to := points[k], points[k+1]
+		n := int(to[0])
+		for i := 0; i < n; i++ {
+			wiggle := 0.004 * math.Sin(float64(len(closes))*1.7)
+			closes = append(closes, (from[1]+(to[1]-from[1])*float64(i)/float64(n))*(1+wiggle))
+		}
+	}
+	return append(closes, points[len(points)-1][1])
+}
+
+var doubleBottom = path([2]float64{0, 100}, [2]float64{30, 120}, [2]float64{20, 100}, [2]float64{15, 112}, [2]float64{15, 100.5}, [2]float64{10, 110})
+
+func TestDetectFindsPatterns(t *testing.T) {
+	engulfing := series(path([2]float64{0, 110}, [2]float64{30, 100}))
+	last := &engulfing[len(engulfing)-1]
+	last.Open, last.High, last.Low, last.Close, last.Volume = 99, 103.5, 98.5, 103, 3000
+
+	gap := series(path([2]float64{0, 100}, [2]float64{30, 101}))
+	gap = append(gap, marketdata.Bar{Ticker: "TEST", Interval: marketdata.Daily, Time: gap[len(gap)-1].Time.AddDate(0, 0, 1),
+		Open: 106, High: 108, Low: 105.5, Close: 107.5, Volume: 3000})
+
+	tests := []struct {
+		pattern string
+		bias    string
+		bars    []marketdata.Bar
+	}{
+		{models.PatternDoubleBottom, "bullish", series(doubleBottom)},
+		{models.PatternDoubleTop, "bearish", series(path([2]float64{0, 100}, [2]float64{30, 80}, [2]float64{20, 100}, [2]float64{15, 90}, [2]float64{15, 99.5}, [2]float64{10, 91}))},
+		{models.PatternBullishFlag, "bullish", series(path([2]float64{0, 100}, [2]float64{40, 102}, [2]float64{8, 118}, [2]float64{8, 113}))},
+		{models.PatternCupAndHandle, "bullish", series(path([2]float64{0, 90}, [2]float64{20, 120}, [2]float64{15, 100}, [2]float64{20, 98}, [2]float64{15, 119}, [2]float64{6, 113}, [2]float64{3, 115}))},
+		{models.PatternHeadAndShoulders, "bearish", series(path([2]float64{0, 80}, [2]float64{20, 100}, [2]float64{10, 92}, [2]float64{12, 108}, [2]float64{12, 92}, [2]float64{10, 100}, [2]float64{12, 89}))},
+		{models.PatternAscendingTriangle, "bullish", series(path([2]float64{0, 90}, [2]float64{10, 100}, [2]float64{8, 92}, [2]float64{8, 100}, [2]float64{8, 95}, [2]float64{8, 100}, [2]float64{8, 97}, [2]float64{6, 100}, [2]float64{5, 98.5}))},
+		{models.PatternRoundingBottom, "bullish", series(path([2]float64{0, 100}, [2]float64{20, 90}, [2]float64{20, 83}, [2]float64{20, 82}, [2]float64{20, 88}, [2]float64{20, 97}))},
+		{models.PatternBullishEngulfing, "bullish", engulfing},
+		{models.PatternBreakawayGap, "bullish", gap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var found *Detection
+			for _, d := range Detect(tt.bars) {
+				for _, level := range d.Levels {
+					if math.IsNaN(level.Price) || math.IsInf(level.Price, 0) {
+						t.Errorf("%s level %s is %v", d.Pattern, level.Name, level.Price)
+					}
+				}
+				if d.Pattern == tt.pattern {
+					found = &d
+				}
+			}
+			if found == nil {
+				t.Fatalf("%s not detected", tt.pattern)
+			}
+			if found.Bias != tt.bias {
+				t.Errorf("bias = %s, want %s", found.Bias, tt.bias)
+			}
+			if found.Confidence < MinConfidence || found.Confidence > 1 {
+				t.Errorf("confidence = %.2f, want between %.2f and 1", found.Confidence, MinConfidence)
+			}
+			if !found.End.Equal(tt.bars[len(tt.bars)-1].Time) {
+				t.Errorf("end = %s, want the last bar", found.End)
+			}
+		})
+	}
+}
+
+func TestDetectNeedsMinBars(t *testing.T) {
+	bars := series(doubleBottom)[:minBars-1]
+	if found := Detect(bars); len(found) != 0 {
+		t.Errorf("Detect on %d bars found %v", len(bars), found)
+	}
+	if d, ok := DetectPattern(bars, models.PatternDoubleBottom); !ok || d != nil {
+		t.Errorf("DetectPattern on %d bars = %v, %v; want nil, true", len(bars), d, ok)
+	}
+}
+
+func TestDetectFlatSeries(t *testing.T) {
+	closes := make([]float64, 120)
+	for i := range closes {
+		closes[i] = 100
+	}
+	for _, pattern := range []string{models.PatternDoubleBottom, models.PatternHeadAndShoulders, models.PatternBullishFlag, models.PatternCupAndHandle} {
+		if d, _ := DetectPattern(series(closes), pattern); d != nil && d.Confidence >= MinConfidence {
+			t.Errorf("%s found in a flat series with confidence %.2f", pattern, d.Confidence)
+		}
+	}
+}
+
+func TestReviewPattern(t *testing.T) {
+	bars := series(doubleBottom)
+
+	tests := []struct {
+		name      string
+		bars      []marketdata.Bar
+		pattern   string
+		want      string // Canonical pattern name in the review
+		supported bool
+		message   string
+	}{
+		{"picked pattern found", bars, "double bottom", models.PatternDoubleBottom, true, "confirmed"},
+		{"picked pattern missing", bars, models.PatternHeadAndShoulders, models.PatternHeadAndShoulders, false, "looks more like " + models.PatternDoubleBottom},
+		{"no pattern picked", bars, "", "", false, "suggests " + models.PatternDoubleBottom},
+		{"pattern without a detector", bars, "Island Reversal", "Island Reversal", false, "can't be detected"},
+		{"too few bars", bars[:10], models.PatternDoubleBottom, models.PatternDoubleBottom, false, "at least 20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := ReviewPattern(tt.bars, tt.pattern)
+			if review.Pattern != tt.want {
+				t.Errorf("pattern = %q, want %q", review.Pattern, tt.want)
+			}
+			if review.Supported != tt.supported {
+				t.Errorf("supported = %v, want %v", review.Supported, tt.supported)
+			}
+			if !strings.Contains(review.Message, tt.message) {
+				t.Errorf("message %q does not mention %q", review.Message, tt.message)
+			}
+			for _, s := range review.Suggestions {
+				if s.Pattern == review.Pattern {
+					t.Errorf("picked pattern %s repeated in the suggestions", s.Pattern)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package detect
+
+import (
+	"math"
+
+	"trading-dashboard/pkg/marketdata"
+	"trading-dashboard/pkg/models"
+)
+
+// Window sizes shared by the detectors, in bars
+const (
+	pivotWidth = 3   // Bars either side a swing high or low must top
+	lookback   = 120 // How far back formations may start
+	recentBars = 30  // How recently a formation's last swing must be
+)
+
+// pivot is a swing high or low
+type pivot struct {
+	i     int
+	price float64
+}
+
+// swingHighs returns the bars whose high tops the width bars either side
+func swingHighs(bars []marketdata.Bar, width int) []pivot {
+	var pivots []pivot
+	for i := width; i < len(bars)-width; i++ {
+		if _, high := highest(bars, i-width, i+width); high == bars[i].High && isFirst(bars, i, width, true) {
+			pivots = append(pivots, pivot{i, bars[i].High})
+		}
+	}
+	return pivots
+}
+
+// swingLows returns the bars whose low is under the width bars either side
+func swingLows(bars []marketdata.Bar, width int) []pivot {
+	var pivots []pivot
+	for i := width; i < len(bars)-width; i++ {
+		if _, low := lowest(bars, i-width, i+width); low == bars[i].Low && isFirst(bars, i, width, false) {
+			pivots = append(pivots, pivot{i, bars[i].Low})
+		}
+	}
+	return pivots
+}
+
+// isFirst reports whether no earlier bar in the window ties bar i, so a
+// flat top or bottom gives one pivot
+func isFirst(bars []marketdata.Bar, i, width int, high bool) bool {
+	for j := i - width; j < i; j++ {
+		if high && bars[j].High == bars[i].High || !high && bars[j].Low == bars[i].Low {
+			return false
+		}
+	}
+	return true
+}
+
+// swings returns the pivots a formation is built from: lows for bullish
+// formations, highs for bearish ones
+func swings(bars []marketdata.Bar, bullish bool) []pivot {
+	if bullish {
+		return swingLows(bars, pivotWidth)
+	}
+	return swingHighs(bars, pivotWidth)
+}
+
+// since drops the pivots before bar from
+func since(pivots []pivot, from int) []pivot {
+	for len(pivots) > 0 && pivots[0].i < from {
+		pivots = pivots[1:]
+	}
+	return pivots
+}
+
+// highest returns the index and price of the highest high in [from, to]
+func highest(bars []marketdata.Bar, from, to int) (int, float64) {
+	best := from
+	for i := from + 1; i <= to; i++ {
+		if bars[i].High > bars[best].High {
+			best = i
+		}
+	}
+	return best, bars[best].High
+}
+
+// lowest returns the index and price of the lowest low in [from, to]
+func lowest(bars []marketdata.Bar, from, to int) (int, float64) {
+	best := from
+	for i := from + 1; i <= to; i++ {
+		if bars[i].Low < bars[best].Low {
+			best = i
+		}
+	}
+	return best, bars[best].Low
+}
+
+// counter returns the opposite extreme between two pivots: the highest
+// high for bullish formations, the lowest low for bearish ones
+func counter(bars []marketdata.Bar, from, to int, bullish bool) (int, float64) {
+	if bullish {
+		return highest(bars, from, to)
+	}
+	return lowest(bars, from, to)
+}
+
+// adverse returns the price furthest against the bias in [from, to]:
+// the lowest low for bullish formations, the highest high for bearish ones
+func adverse(bars []marketdata.Bar, from, to int, bullish bool) float64 {
+	if bullish {
+		_, low := lowest(bars, from, to)
+		return low
+	}
+	_, high := highest(bars, from, to)
+	return high
+}
+
+// sma returns the average close of the period bars ending at end
+func sma(bars []marketdata.Bar, end, period int) float64 {
+	sum := 0.0
+	for i := end - period + 1; i <= end; i++ {
+		sum += bars[i].Close
+	}
+	return sum / float64(period)
+}
+
+// volumeRatio returns bar i's volume over the average of the period bars
+// before it, or 0 when there is no volume
+func volumeRatio(bars []marketdata.Bar, i, period int) float64 {
+	from := max(0, i-period)
+	sum := 0.0
+	for j := from; j < i; j++ {
+		sum += bars[j].Volume
+	}
+	if i == from || sum == 0 {
+		return 0
+	}
+	return bars[i].Volume / (sum / float64(i-from))
+}
+
+// volumeScore scores a volume ratio: 1 at twice the average volume, and
+// a neutral 0.5 when the bars have no volume
+func volumeScore(ratio float64) float64 {
+	if ratio == 0 {
+		return 0.5
+	}
+	return clamp01(ratio - 1)
+}
+
+// line is a least-squares line through pivots, price by bar index
+type line struct {
+	slope, intercept float64
+	fit              float64 // Mean distance of the pivots from the line, as a fraction of price
+}
+
+// at returns the line's price at bar i
+func (l line) at(i int) float64 {
+	return l.slope*float64(i) + l.intercept
+}
+
+// fitLine fits a line through at least two pivots
+func fitLine(pivots []pivot) line {
+	n := float64(len(pivots))
+	var sx, sy, sxx, sxy float64
+	for _, p := range pivots {
+		x := float64(p.i)
+		sx += x
+		sy += p.price
+		sxx += x * x
+		sxy += x * p.price
+	}
+	var l line
+	if d := n*sxx - sx*sx; d != 0 {
+		l.slope = (n*sxy - sx*sy) / d
+	}
+	l.intercept = (sy - l.slope*sx) / n
+	for _, p := range pivots {
+		l.fit += math.Abs(p.price-l.at(p.i)) / p.price
+	}
+	l.fit /= n
+	return l
+}
+
+// fitQuadratic fits y = a*x² + b*x + c by least squares and returns the
+// coefficients with the fit's R²
+func fitQuadratic(xs, ys []float64) (a, b, c, r2 float64) {
+	var s [5]float64 // Sums of x⁰..x⁴
+	var t [3]float64 // Sums of y, x*y, x²*y
+	for i, x := range xs {
+		p := 1.0
+		for k := 0; k < 5; k++ {
+			s[k] += p
+			if k < 3 {
+				t[k] += p * ys[i]
+			}
+			p *= x
+		}
+	}
+	m := [3][3]float64{{s[4], s[3], s[2]}, {s[3], s[2], s[1]}, {s[2], s[1], s[0]}}
+	v := [3]float64{t[2], t[1], t[0]}
+	det := det3(m)
+	if det == 0 {
+		return 0, 0, 0, 0
+	}
+	var coef [3]float64
+	for k := 0; k < 3; k++ {
+		mk := m
+		for row := 0; row < 3; row++ {
+			mk[row][k] = v[row]
+		}
+		coef[k] = det3(mk) / det
+	}
+	a, b, c = coef[0], coef[1], coef[2]
+
+	mean := t[0] / s[0]
+	var ssRes, ssTot float64
+	for i, x := range xs {
+		e := ys[i] - (a*x*x + b*x + c)
+		ssRes += e * e
+		ssTot += (ys[i] - mean) * (ys[i] - mean)
+	}
+	if ssTot > 0 {
+		r2 = 1 - ssRes/ssTot
+	}
+	return a, b, c, r2
+}
+
+func det3(m [3][3]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// pctDiff returns how far apart two prices are, as a fraction of the lower
+func pctDiff(a, b float64) float64 {
+	return math.Abs(a-b) / math.Min(a, b)
+}
+
+// closeness scores how near two prices are: 1 when equal, 0 at tol apart
+func closeness(a, b, tol float64) float64 {
+	return clamp01(1 - pctDiff(a, b)/tol)
+}
+
+// biasOf returns the pattern bias for a direction
+func biasOf(bullish bool) string {
+	if bullish {
+		return models.PatternBiasBullish
+	}
+	return models.PatternBiasBearish
+}
+
+// sign returns 1 for bullish and -1 for bearish
+func sign(bullish bool) float64 {
+	if bullish {
+		return 1
+	}
+	return -1
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
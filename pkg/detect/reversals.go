@@ -0,0 +1,226 @@
+package detect
+
+import (
+	"fmt"
+	"math"
+
+	"trading-dashboard/pkg/marketdata"
+)
+
+// double finds a double bottom (bullish) or double top: two swing lows
+// (highs) within 4% of each other with a rally (decline) of at least 5%
+// between them. Confidence grows with how even the swings are, how deep
+// the middle is and how far price has moved back towards the neckline.
+func double(bars []marketdata.Bar, bullish bool) *Detection {
+	n := len(bars)
+	last := bars[n-1]
+	s := sign(bullish)
+	pivots := since(swings(bars, bullish), n-lookback)
+	if len(pivots) < 2 {
+		return nil
+	}
+	second := pivots[len(pivots)-1]
+	if second.i < n-1-recentBars {
+		return nil
+	}
+
+	var best *Detection
+	for j := len(pivots) - 2; j >= 0; j-- {
+		first := pivots[j]
+		if second.i-first.i < 5 {
+			continue
+		}
+		_, neck := counter(bars, first.i, second.i, bullish)
+		extreme, inner := first.price, second.price
+		if s*(second.price-first.price) < 0 {
+			extreme, inner = second.price, first.price
+		}
+		diff := pctDiff(first.price, second.price)
+		depth := math.Abs(neck-inner) / math.Max(neck, inner)
+		if diff > 0.04 || depth < 0.05 {
+			continue
+		}
+		if second.i < n-1 && s*(adverse(bars, second.i+1, n-1, bullish)-extreme) < 0 {
+			continue
+		}
+		progress := clamp01(s * (last.Close - second.price) / math.Abs(neck-second.price))
+		confidence := 0.4*(1-diff/0.04) + 0.3*clamp01(depth/0.15) + 0.3*progress
+		if best != nil && confidence <= best.Confidence {
+			continue
+		}
+
+		edge := LevelSupport
+		if !bullish {
+			edge = LevelResistance
+		}
+		best = &Detection{
+			Bias:       biasOf(bullish),
+			Confidence: confidence,
+			Start:      bars[first.i].Time,
+			Levels: []Level{
+				{edge, extreme},
+				{LevelNeckline, neck},
+				{LevelTarget, neck + s*math.Abs(neck-extreme)},
+				{LevelInvalidation, extreme},
+			},
+			Summary: fmt.Sprintf("Swings on %s and %s are %.1f%% apart, %.1f%% from the %.2f neckline",
+				bars[first.i].Time.Format("2006-01-02"), bars[second.i].Time.Format("2006-01-02"), diff*100, depth*100, neck),
+		}
+	}
+	return best
+}
+
+// headAndShoulders finds a head and shoulders top, or an inverse one
+// (bullish): the last three swing highs (lows) with the middle one beyond
+// both shoulders by at least 2% and the shoulders within 6% of each other
+func headAndShoulders(bars []marketdata.Bar, inverse bool) *Detection {
+	n := len(bars)
+	last := bars[n-1]
+	s := sign(inverse)
+	pivots := since(swings(bars, inverse), n-lookback)
+	if len(pivots) < 3 {
+		return nil
+	}
+	left, head, right := pivots[len(pivots)-3], pivots[len(pivots)-2], pivots[len(pivots)-1]
+	if right.i < n-1-recentBars {
+		return nil
+	}
+	prominence := math.Min(-s*(head.price-left.price), -s*(head.price-right.price)) / head.price
+	symmetry := pctDiff(left.price, right.price)
+	if prominence < 0.02 || symmetry > 0.06 {
+		return nil
+	}
+	if right.i < n-1 && s*(adverse(bars, right.i+1, n-1, inverse)-head.price) < 0 {
+		return nil
+	}
+
+	_, leftTrough := counter(bars, left.i, head.i, inverse)
+	_, rightTrough := counter(bars, head.i, right.i, inverse)
+	neck := (leftTrough + rightTrough) / 2
+	if neck == right.price {
+		return nil
+	}
+	progress := clamp01(s * (last.Close - right.price) / math.Abs(neck-right.price))
+	confidence := 0.3*(1-symmetry/0.06) + 0.25*clamp01(prominence/0.08) +
+		0.15*closeness(leftTrough, rightTrough, 0.05) + 0.3*progress
+
+	return &Detection{
+		Bias:       biasOf(inverse),
+		Confidence: confidence,
+		Start:      bars[left.i].Time,
+		Levels: []Level{
+			{LevelNeckline, neck},
+			{LevelTarget, neck + s*math.Abs(head.price-neck)},
+			{LevelInvalidation, right.price},
+		},
+		Summary: fmt.Sprintf("Head at %.2f is %.1f%% beyond shoulders at %.2f and %.2f; neckline %.2f",
+			head.price, prominence*100, left.price, right.price, neck),
+	}
+}
+
+// cupAndHandle finds a rounded cup 10-45% deep and at least 20 bars long
+// whose right rim comes back within 8% of the left, followed by a handle
+// of 3-25 bars that gives back at most half the cup
+func cupAndHandle(bars []marketdata.Bar) *Detection {
+	n := len(bars)
+	if n < 40 {
+		return nil
+	}
+	last := bars[n-1]
+	leftI, left := highest(bars, max(0, n-150), n-25)
+	bottomI, bottom := lowest(bars, leftI, n-4)
+	rightI, right := highest(bars, bottomI, n-4)
+	handleI, handle := lowest(bars, rightI+1, n-1)
+
+	depth := (left - bottom) / left
+	cup := rightI - leftI
+	if depth < 0.10 || depth > 0.45 || cup < 20 || right < left*0.92 || n-1-rightI > 25 {
+		return nil
+	}
+	retrace := (right - handle) / (right - bottom)
+	if retrace > 0.5 || handle >= right {
+		return nil
+	}
+
+	// A U spends time near its bottom; a V doesn't
+	lowBars := 0
+	for i := leftI; i <= rightI; i++ {
+		if bars[i].Low <= bottom+(left-bottom)/3 {
+			lowBars++
+		}
+	}
+	roundness := clamp01(float64(lowBars) / float64(cup) / 0.3)
+	depthScore := 1.0
+	if depth < 0.12 || depth > 0.35 {
+		depthScore = 0.5
+	}
+	progress := clamp01((last.Close - handle) / (right - handle))
+	confidence := 0.2*depthScore + 0.2*closeness(left, right, 0.08) + 0.2*roundness +
+		0.2*(1-retrace/0.5) + 0.2*progress
+
+	return &Detection{
+		Bias:       biasOf(true),
+		Confidence: confidence,
+		Start:      bars[leftI].Time,
+		Levels: []Level{
+			{LevelResistance, left},
+			{LevelSupport, bottom},
+			{LevelBreakout, right},
+			{LevelTarget, right + (left - bottom)},
+			{LevelInvalidation, handle},
+		},
+		Summary: fmt.Sprintf("%.1f%% deep cup over %d bars; handle from %s gives back %.0f%% of it",
+			depth*100, cup, bars[handleI].Time.Format("2006-01-02"), retrace*100),
+	}
+}
+
+// roundingBottom fits a parabola to the closes of the last 40-120 bars
+// and finds a bowl at least 10% deep with its low in the middle half that
+// price has climbed at least halfway out of
+func roundingBottom(bars []marketdata.Bar) *Detection {
+	n := len(bars)
+	w := min(n, lookback)
+	if w < 40 {
+		return nil
+	}
+	seg := bars[n-w:]
+	xs := make([]float64, w)
+	ys := make([]float64, w)
+	for i, bar := range seg {
+		xs[i] = float64(i) / float64(w-1)
+		ys[i] = bar.Close
+	}
+	a, b, _, r2 := fitQuadratic(xs, ys)
+	if a <= 0 || r2 < 0.5 {
+		return nil
+	}
+	vertex := -b / (2 * a)
+	if vertex < 0.25 || vertex > 0.75 {
+		return nil
+	}
+
+	rim := 0.0
+	for _, bar := range seg[:w/10] {
+		rim = math.Max(rim, bar.Close)
+	}
+	_, low := lowest(seg, 0, w-1)
+	decline := (rim - low) / rim
+	recovery := (seg[w-1].Close - low) / (rim - low)
+	if decline < 0.10 || recovery < 0.5 {
+		return nil
+	}
+	confidence := 0.35*clamp01((r2-0.5)/0.4) + 0.25*(1-math.Abs(vertex-0.5)/0.25) + 0.4*clamp01(recovery)
+
+	return &Detection{
+		Bias:       biasOf(true),
+		Confidence: confidence,
+		Start:      seg[0].Time,
+		Levels: []Level{
+			{LevelSupport, low},
+			{LevelResistance, rim},
+			{LevelTarget, rim},
+			{LevelInvalidation, low},
+		},
+		Summary: fmt.Sprintf("%.1f%% deep bowl over %d bars (R² %.2f), %.0f%% recovered", decline*100, w, r2, recovery*100),
+	}
+}
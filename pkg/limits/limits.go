@@ -0,0 +1,156 @@
+// Package limits checks realized losses and the daily risk score against
+// the limits that lock trading
+package limits
+
+import (
+	"fmt"
+	"time"
+
+	"trading-dashboard/pkg/models"
+)
+
+// Limit rule names
+const (
+	RuleDailyLoss  = "daily_loss"
+	RuleWeeklyLoss = "weekly_loss"
+	RuleRiskScore  = "risk_score"
+)
+
+// Rules configure when trading locks. Zero loss limits are disabled.
+type Rules struct {
+	DailyLossLimit  float64 `json:"dailyLossLimit"`  // Dollars of net realized loss per day
+	WeeklyLossLimit float64 `json:"weeklyLossLimit"` // Dollars of net realized loss per week (Monday to Sunday)
+	// RiskScoreCheck locks trading for the day when today's assessment
+	// scores below MinRiskScore
+	RiskScoreCheck bool    `json:"riskScoreCheck"`
+	MinRiskScore   float64 `json:"minRiskScore"`
+	// RequireCheckIn refuses new trades until today's risk assessment is saved
+	RequireCheckIn bool `json:"requireCheckIn"`
+	// CooldownMinutes is how long a lock lasts; 0 locks until the day or
+	// week that tripped it ends
+	CooldownMinutes int `json:"cooldownMinutes"`
+}
+
+// DefaultRules have every limit disabled
+func DefaultRules() *Rules {
+	return &Rules{MinRiskScore: -1}
+}
+
+// Validate checks the rules
+func (r *Rules) Validate() error {
+	if r.DailyLossLimit < 0 || r.WeeklyLossLimit < 0 {
+		return fmt.Errorf("loss limits cannot be negative; enter them as positive dollar amounts")
+	}
+	if r.MinRiskScore < -3 || r.MinRiskScore > 3 {
+		return fmt.Errorf("minimum risk score must be between -3 and 3")
+	}
+	if r.CooldownMinutes < 0 {
+		return fmt.Errorf("cooldown cannot be negative")
+	}
+	return nil
+}
+
+// Period is the calendar day or week a limit applies to
+type Period struct {
+	Key   string    `json:"key"` // "2026-10-18" or "2026-W42"
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"` // Exclusive
+}
+
+// Day returns the trading day containing t (see models.TradingDay)
+func Day(t time.Time) Period {
+	start := models.DayStart(t)
+	return Period{Key: models.TradingDay(t), Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+// Week returns the Monday-to-Sunday week of trading days containing t
+func Week(t time.Time) Period {
+	day := Day(t)
+	offset := (int(day.Start.Weekday()) + 6) % 7 // Days since Monday
+	start := day.Start.AddDate(0, 0, -offset)
+	year, week := start.ISOWeek()
+	return Period{Key: fmt.Sprintf("%d-W%02d", year, week), Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// Breach is a limit that is currently exceeded
+type Breach struct {
+	Rule   string  `json:"rule"`
+	Period Period  `json:"period"`
+	Reason string  `json:"reason"`
+	Loss   float64 `json:"loss"` // Net realized loss in the period; 0 for the risk score rule
+}
+
+// RealizedBetween sums the net realized P&L of positions from start
+// (inclusive) to end (exclusive)
+func RealizedBetween(positions map[string]*models.Position, start, end time.Time) float64 {
+	total := 0.0
+	for _, pos := range positions {
+		for _, r := range pos.Realizations {
+			if !r.Date.Before(start) && r.Date.Before(end) {
+				total += r.Net
+			}
+		}
+	}
+	return total
+}
+
+// Evaluate returns the limits breached at now. today is today's risk
+// assessment, or nil if none was saved.
+func Evaluate(rules *Rules, now time.Time, positions map[string]*models.Position, today *models.RiskAssessment) []Breach {
+	var breaches []Breach
+
+	day, week := Day(now), Week(now)
+	if rules.DailyLossLimit > 0 {
+		if loss := -RealizedBetween(positions, day.Start, day.End); loss >= rules.DailyLossLimit {
+			breaches = append(breaches, Breach{RuleDailyLoss, day,
+				fmt.Sprintf("realized loss of $%.2f today reached the $%.2f daily limit", loss, rules.DailyLossLimit), loss})
+		}
+	}
+	if rules.WeeklyLossLimit > 0 {
+		if loss := -RealizedBetween(positions, week.Start, week.End); loss >= rules.WeeklyLossLimit {
+			breaches = append(breaches, Breach{RuleWeeklyLoss, week,
+				fmt.Sprintf("realized loss of $%.2f this week reached the $%.2f weekly limit", loss, rules.WeeklyLossLimit), loss})
+		}
+	}
+	if rules.RiskScoreCheck && today != nil && today.OverallScore < rules.MinRiskScore {
+		breaches = append(breaches, Breach{RuleRiskScore, day,
+			fmt.Sprintf("today's risk score %.2f is below the minimum of %.2f", today.OverallScore, rules.MinRiskScore), 0})
+	}
+	return breaches
+}
+
+// PeriodOf returns the period rule is evaluated over at t
+func PeriodOf(rule string, t time.Time) Period {
+	if rule == RuleWeeklyLoss {
+		return Week(t)
+	}
+	return Day(t)
+}
+
+// Retrips reports whether breach trips its rule again after the rule's
+// lock for the same period was overridden with lossAtOverride already
+// realized. Loss limits trip again once the loss since the override
+// reaches the limit; the risk score rule stays overridden for the day.
+func Retrips(rules *Rules, breach Breach, lossAtOverride float64) bool {
+	var limit float64
+	switch breach.Rule {
+	case RuleDailyLoss:
+		limit = rules.DailyLossLimit
+	case RuleWeeklyLoss:
+		limit = rules.WeeklyLossLimit
+	default:
+		return false
+	}
+	return limit > 0 && breach.Loss-lossAtOverride >= limit
+}
+
+// LockUntil returns when a lock tripped at trippedAt for period ends
+func LockUntil(rules *Rules, period Period, trippedAt time.Time) time.Time {
+	if rules.CooldownMinutes > 0 {
+		cooldown := trippedAt.Add(time.Duration(rules.CooldownMinutes) * time.Minute)
+		if cooldown.Before(period.End) {
+			return cooldown
+		}
+	}
+	return period.End
+}
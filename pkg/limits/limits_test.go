@@ -0,0 +1,224 @@
+package limits
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/models"
+)
+
+var eastern = time.FixedZone("EDT", -4*60*60)
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestDay(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		key  string
+	}{
+		{"midnight UTC", utc(2025, 4, 25, 0, 0), "2025-04-25"},
+		{"last second of the day", time.Date(2025, 4, 25, 23, 59, 59, 999999999, time.UTC), "2025-04-25"},
+		{"evening in New York is the next UTC day", time.Date(2025, 4, 25, 21, 0, 0, 0, eastern), "2025-04-26"},
+		{"afternoon in New York", time.Date(2025, 4, 25, 15, 0, 0, 0, eastern), "2025-04-25"},
+	}
+	for _, tt := range tests {
+		p := Day(tt.at)
+		start, _ := time.Parse("2006-01-02", tt.key)
+		if p.Key != tt.key || !p.Start.Equal(start) || !p.End.Equal(start.AddDate(0, 0, 1)) {
+			t.Errorf("%s: Day = %+v, want %s from midnight UTC for one day", tt.name, p, tt.key)
+		}
+		if tt.at.Before(p.Start) || !tt.at.Before(p.End) {
+			t.Errorf("%s: %s is outside [%s, %s)", tt.name, tt.at, p.Start, p.End)
+		}
+	}
+}
+
+func TestWeek(t *testing.T) {
+	tests := []struct {
+		name  string
+		at    time.Time
+		key   string
+		start time.Time
+	}{
+		{"monday", utc(2025, 4, 21, 0, 0), "2025-W17", utc(2025, 4, 21, 0, 0)},
+		{"sunday night", utc(2025, 4, 27, 23, 59), "2025-W17", utc(2025, 4, 21, 0, 0)},
+		{"next monday", utc(2025, 4, 28, 0, 0), "2025-W18", utc(2025, 4, 28, 0, 0)},
+		{"sunday evening in New York is Monday UTC", time.Date(2025, 4, 27, 21, 0, 0, 0, eastern), "2025-W18", utc(2025, 4, 28, 0, 0)},
+		{"week spanning new year", utc(2024, 12, 31, 12, 0), "2025-W01", utc(2024, 12, 30, 0, 0)},
+		{"week 53", utc(2021, 1, 3, 12, 0), "2020-W53", utc(2020, 12, 28, 0, 0)},
+	}
+	for _, tt := range tests {
+		p := Week(tt.at)
+		if p.Key != tt.key || !p.Start.Equal(tt.start) || !p.End.Equal(tt.start.AddDate(0, 0, 7)) {
+			t.Errorf("%s: Week = %+v, want %s starting %s", tt.name, p, tt.key, tt.start)
+		}
+	}
+}
+
+// realized returns a position with one realization of net at each time
+func realized(net float64, at ...time.Time) *models.Position {
+	pos := &models.Position{}
+	for _, t := range at {
+		pos.Realizations = append(pos.Realizations, models.Realization{Date: t, Net: net})
+	}
+	return pos
+}
+
+func TestEvaluate(t *testing.T) {
+	now := utc(2025, 4, 25, 18, 0) // A Friday
+	rules := &Rules{DailyLossLimit: 100, WeeklyLossLimit: 250, RiskScoreCheck: true, MinRiskScore: -1}
+	tests := []struct {
+		name      string
+		positions map[string]*models.Position
+		score     *float64
+		want      []string
+	}{
+		{"nothing lost", nil, nil, nil},
+		{"loss exactly at the daily limit", map[string]*models.Position{
+			"a": realized(-60, utc(2025, 4, 25, 0, 0)),
+			"b": realized(-40, utc(2025, 4, 25, 17, 0)),
+		}, nil, []string{RuleDailyLoss}},
+		{"gains offset losses", map[string]*models.Position{
+			"a": realized(-150, utc(2025, 4, 25, 10, 0)),
+			"b": realized(60, utc(2025, 4, 25, 11, 0)),
+		}, nil, nil},
+		{"yesterday's loss counts only for the week", map[string]*models.Position{
+			"a": realized(-150, utc(2025, 4, 24, 23, 59), utc(2025, 4, 21, 0, 0)),
+		}, nil, []string{RuleWeeklyLoss}},
+		{"last week's loss does not count", map[string]*models.Position{
+			"a": realized(-300, time.Date(2025, 4, 20, 23, 59, 59, 0, time.UTC)),
+		}, nil, nil},
+		{"loss realized the evening before in New York is today", map[string]*models.Position{
+			"a": realized(-100, time.Date(2025, 4, 24, 21, 0, 0, 0, eastern)),
+		}, nil, []string{RuleDailyLoss}},
+		{"low risk score", nil, ptr(-1.5), []string{RuleRiskScore}},
+		{"risk score at the minimum", nil, ptr(-1), nil},
+		{"every limit", map[string]*models.Position{
+			"a": realized(-130, utc(2025, 4, 23, 12, 0), utc(2025, 4, 25, 12, 0)),
+		}, ptr(-2), []string{RuleDailyLoss, RuleWeeklyLoss, RuleRiskScore}},
+	}
+	for _, tt := range tests {
+		var today *models.RiskAssessment
+		if tt.score != nil {
+			today = &models.RiskAssessment{OverallScore: *tt.score}
+		}
+		breaches := Evaluate(rules, now, tt.positions, today)
+		var got []string
+		for _, b := range breaches {
+			got = append(got, b.Rule)
+			if b.Reason == "" {
+				t.Errorf("%s: %s breach has no reason", tt.name, b.Rule)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: breaches %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateLoss(t *testing.T) {
+	positions := map[string]*models.Position{"a": realized(-130, utc(2025, 4, 23, 12, 0), utc(2025, 4, 25, 12, 0))}
+	today := &models.RiskAssessment{OverallScore: -2}
+	rules := &Rules{DailyLossLimit: 100, WeeklyLossLimit: 250, RiskScoreCheck: true, MinRiskScore: -1}
+	want := map[string]float64{RuleDailyLoss: 130, RuleWeeklyLoss: 260, RuleRiskScore: 0}
+	for _, b := range Evaluate(rules, utc(2025, 4, 25, 18, 0), positions, today) {
+		if b.Loss != want[b.Rule] {
+			t.Errorf("%s breach loss = %v, want %v", b.Rule, b.Loss, want[b.Rule])
+		}
+	}
+}
+
+func TestRetrips(t *testing.T) {
+	rules := &Rules{DailyLossLimit: 100, WeeklyLossLimit: 250, RiskScoreCheck: true, MinRiskScore: -1}
+	tests := []struct {
+		name           string
+		rules          *Rules
+		breach         Breach
+		lossAtOverride float64
+		want           bool
+	}{
+		{"daily loss grew by less than the limit", rules, Breach{Rule: RuleDailyLoss, Loss: 199}, 100, false},
+		{"daily loss grew by the limit", rules, Breach{Rule: RuleDailyLoss, Loss: 200}, 100, true},
+		{"overridden below the limit", rules, Breach{Rule: RuleDailyLoss, Loss: 120}, 20, true},
+		{"weekly loss grew by less than the limit", rules, Breach{Rule: RuleWeeklyLoss, Loss: 400}, 300, false},
+		{"weekly loss grew by the limit", rules, Breach{Rule: RuleWeeklyLoss, Loss: 560}, 300, true},
+		{"risk score stays overridden", rules, Breach{Rule: RuleRiskScore}, 0, false},
+		{"disabled limit", &Rules{WeeklyLossLimit: 250}, Breach{Rule: RuleDailyLoss, Loss: 500}, 0, false},
+	}
+	for _, tt := range tests {
+		if got := Retrips(tt.rules, tt.breach, tt.lossAtOverride); got != tt.want {
+			t.Errorf("%s: Retrips = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPeriodOf(t *testing.T) {
+	at := utc(2025, 4, 25, 18, 0)
+	tests := []struct {
+		rule string
+		want Period
+	}{
+		{RuleDailyLoss, Day(at)},
+		{RuleRiskScore, Day(at)},
+		{RuleWeeklyLoss, Week(at)},
+	}
+	for _, tt := range tests {
+		if got := PeriodOf(tt.rule, at); got != tt.want {
+			t.Errorf("PeriodOf(%s) = %+v, want %+v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateDisabledLimits(t *testing.T) {
+	positions := map[string]*models.Position{"a": realized(-1000, utc(2025, 4, 25, 12, 0))}
+	today := &models.RiskAssessment{OverallScore: -3}
+	if breaches := Evaluate(DefaultRules(), utc(2025, 4, 25, 18, 0), positions, today); len(breaches) != 0 {
+		t.Errorf("default rules breached %+v", breaches)
+	}
+}
+
+func TestLockUntil(t *testing.T) {
+	trippedAt := utc(2025, 4, 25, 18, 0)
+	day := Day(trippedAt)
+	tests := []struct {
+		name     string
+		cooldown int
+		want     time.Time
+	}{
+		{"no cooldown locks for the period", 0, day.End},
+		{"cooldown within the period", 90, utc(2025, 4, 25, 19, 30)},
+		{"cooldown past the period ends with it", 24 * 60, day.End},
+	}
+	for _, tt := range tests {
+		if got := LockUntil(&Rules{CooldownMinutes: tt.cooldown}, day, trippedAt); !got.Equal(tt.want) {
+			t.Errorf("%s: LockUntil = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		ok    bool
+	}{
+		{"defaults", *DefaultRules(), true},
+		{"negative daily limit", Rules{DailyLossLimit: -100}, false},
+		{"negative weekly limit", Rules{WeeklyLossLimit: -100}, false},
+		{"score out of range", Rules{MinRiskScore: -4}, false},
+		{"negative cooldown", Rules{CooldownMinutes: -5}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.rules.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
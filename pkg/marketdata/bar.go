@@ -0,0 +1,136 @@
+// Package marketdata holds OHLCV price bars, reads them from CSV files
+// and answers range queries over them
+package marketdata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// Interval is the length of one bar
+type Interval string
+
+// Bar intervals
+const (
+	Minute1  Interval = "1m"
+	Minute5  Interval = "5m"
+	Minute15 Interval = "15m"
+	Minute30 Interval = "30m"
+	Hour1    Interval = "1h"
+	Hour4    Interval = "4h"
+	Daily    Interval = "1d"
+	Weekly   Interval = "1w"
+)
+
+// Intervals lists every interval, shortest first
+var Intervals = []Interval{Minute1, Minute5, Minute15, Minute30, Hour1, Hour4, Daily, Weekly}
+
+// ParseInterval returns the interval named s ("1d", "5m", ...), also
+// accepting "daily", "weekly" and "D"/"W"
+func ParseInterval(s string) (Interval, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	switch key {
+	case "d", "day", "daily":
+		return Daily, nil
+	case "w", "week", "weekly":
+		return Weekly, nil
+	case "60m":
+		return Hour1, nil
+	}
+	for _, interval := range Intervals {
+		if string(interval) == key {
+			return interval, nil
+		}
+	}
+	return "", fmt.Errorf("unknown bar interval %q", s)
+}
+
+// Intraday reports whether bars of the interval are shorter than a day
+func (i Interval) Intraday() bool {
+	return i != Daily && i != Weekly
+}
+
+// DayStamp returns midnight UTC of t's date as read in t's own zone, the
+// time daily and weekly bars start at
+func DayStamp(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Bar is one OHLCV bar. Time is the start of the bar; daily and weekly
+// bars start at midnight UTC of their first day (see DayStamp), the way
+// trading days are keyed.
+type Bar struct {
+	Ticker   string    `json:"ticker"`
+	Interval Interval  `json:"interval"`
+	Time     time.Time `json:"time"`
+	Open     float64   `json:"open"`
+	High     float64   `json:"high"`
+	Low      float64   `json:"low"`
+	Close    float64   `json:"close"`
+	Volume   float64   `json:"volume"`
+}
+
+// Validate checks that prices are positive and consistent
+func (b *Bar) Validate() error {
+	var v validation.Validator
+	v.Required("ticker", b.Ticker)
+	if _, err := ParseInterval(string(b.Interval)); err != nil {
+		v.Add("interval", validation.CodeInvalid, err.Error())
+	}
+	if b.Time.IsZero() {
+		v.Add("time", validation.CodeRequired, "time is required")
+	}
+	if b.Open <= 0 || b.High <= 0 || b.Low <= 0 || b.Close <= 0 {
+		v.Add("close", validation.CodeOutOfRange, "prices must be positive")
+	} else if b.Low > b.High || b.Open < b.Low || b.Open > b.High || b.Close < b.Low || b.Close > b.High {
+		v.Add("high", validation.CodeOrder, "open and close must be between low and high")
+	}
+	v.NotNegative("volume", b.Volume)
+	return v.Err()
+}
+
+// Series summarizes the stored bars of one ticker and interval
+type Series struct {
+	Ticker    string    `json:"ticker"`
+	Interval  Interval  `json:"interval"`
+	Bars      int       `json:"bars"`
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Sort orders bars by time
+func Sort(bars []Bar) {
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].Time.Before(bars[j].Time) })
+}
+
+// Between returns the bars (sorted by time) starting from start to end
+// inclusive; a zero start or end does not bound the range
+func Between(bars []Bar, start, end time.Time) []Bar {
+	from := 0
+	if !start.IsZero() {
+		from = sort.Search(len(bars), func(i int) bool { return !bars[i].Time.Before(start) })
+	}
+	to := len(bars)
+	if !end.IsZero() {
+		to = sort.Search(len(bars), func(i int) bool { return bars[i].Time.After(end) })
+	}
+	if from >= to {
+		return []Bar{}
+	}
+	return bars[from:to]
+}
+
+// Latest returns the last bar (of bars sorted by time) starting at or
+// before asOf, or false if there is none
+func Latest(bars []Bar, asOf time.Time) (Bar, bool) {
+	i := sort.Search(len(bars), func(i int) bool { return bars[i].Time.After(asOf) })
+	if i == 0 {
+		return Bar{}, false
+	}
+	return bars[i-1], true
+}
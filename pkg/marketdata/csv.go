@@ -0,0 +1,294 @@
+package marketdata
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportOptions say how to read a CSV file of bars
+type ImportOptions struct {
+	Ticker   string   `json:"ticker"`   // Used when the file has no ticker/symbol column
+	Interval Interval `json:"interval"` // Interval of every bar in the file
+	// Location is the time zone of timestamps without one; nil means
+	// the local time zone
+	Location *time.Location `json:"-"`
+}
+
+// ImportResult reports what an import read
+type ImportResult struct {
+	Interval Interval  `json:"interval"`
+	Tickers  []string  `json:"tickers"`
+	Imported int       `json:"imported"`
+	Skipped  int       `json:"skipped"` // Rows with missing values, e.g. "null" prices
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+}
+
+// Column name aliases, lower case without spaces, underscores or <>
+var columnAliases = map[string]string{
+	"date":      "date",
+	"day":       "date",
+	"time":      "time",
+	"datetime":  "datetime",
+	"timestamp": "datetime",
+	"date/time": "datetime",
+	"gmttime":   "datetime",
+	"localtime": "datetime",
+	"open":      "open",
+	"o":         "open",
+	"high":      "high",
+	"h":         "high",
+	"low":       "low",
+	"l":         "low",
+	"close":     "close",
+	"c":         "close",
+	"last":      "close",
+	"closelast": "close",
+	"volume":    "volume",
+	"vol":       "volume",
+	"v":         "volume",
+	"ticker":    "ticker",
+	"symbol":    "ticker",
+}
+
+// dateLayouts are tried in order on date and datetime values
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"01/02/2006",
+	"02-Jan-2006",
+	"Jan 2, 2006",
+	"20060102",
+}
+
+// timeLayouts are tried on a separate time column
+var timeLayouts = []string{"15:04:05", "15:04", "150405", "1504"}
+
+// ParseCSV reads bars from CSV in the common layouts: Yahoo Finance
+// (Date,Open,High,Low,Close,Adj Close,Volume), Stooq
+// (<TICKER>,<PER>,<DATE>,<TIME>,<OPEN>,...), TradingView
+// (time,open,high,low,close,Volume with Unix or ISO times) and other
+// files with a header naming the columns. Files without a header must
+// be Date,Open,High,Low,Close[,Volume]. Commas, semicolons and tabs are
+// accepted as separators. Daily and weekly bars are set to midnight UTC
+// of their date as written. Rows with missing prices are skipped and counted.
+func ParseCSV(r io.Reader, opts ImportOptions) ([]Bar, *ImportResult, error) {
+	interval, err := ParseInterval(string(opts.Interval))
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	buffered := bufio.NewReader(r)
+	first, _ := buffered.Peek(4096)
+	reader := csv.NewReader(buffered)
+	reader.Comma = sniffSeparator(string(first))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	result := &ImportResult{Interval: interval, Tickers: []string{}}
+	var bars []Bar
+	var columns map[string]int
+	tickers := make(map[string]bool)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(record) == 0 || len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if columns == nil {
+			if columns = headerColumns(record); columns != nil {
+				continue
+			}
+			columns = map[string]int{"date": 0, "open": 1, "high": 2, "low": 3, "close": 4, "volume": 5}
+		}
+
+		bar, ok, err := parseRow(record, columns, interval, loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		if bar.Ticker == "" {
+			bar.Ticker = opts.Ticker
+		}
+		bar.Ticker = strings.ToUpper(strings.TrimSpace(bar.Ticker))
+		if err := bar.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		bars = append(bars, bar)
+		if !tickers[bar.Ticker] {
+			tickers[bar.Ticker] = true
+			result.Tickers = append(result.Tickers, bar.Ticker)
+		}
+	}
+
+	Sort(bars)
+	result.Imported = len(bars)
+	if len(bars) > 0 {
+		result.First, result.Last = bars[0].Time, bars[len(bars)-1].Time
+	}
+	return bars, result, nil
+}
+
+// sniffSeparator picks the separator used most in the first line
+func sniffSeparator(sample string) rune {
+	firstLine, _, _ := strings.Cut(sample, "\n")
+	best, count := ',', strings.Count(firstLine, ",")
+	for _, sep := range []rune{';', '\t'} {
+		if n := strings.Count(firstLine, string(sep)); n > count {
+			best, count = sep, n
+		}
+	}
+	return best
+}
+
+// headerColumns maps column roles to positions, or returns nil if record
+// is not a header (it has no close column)
+func headerColumns(record []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range record {
+		key := strings.ToLower(strings.TrimSpace(name))
+		key = strings.NewReplacer("<", "", ">", "", " ", "", "_", "", "\ufeff", "").Replace(key)
+		if role, ok := columnAliases[key]; ok {
+			if _, taken := columns[role]; !taken {
+				columns[role] = i
+			}
+		}
+	}
+	if _, ok := columns["close"]; !ok {
+		return nil
+	}
+	// A lone time column (TradingView) holds the full timestamp
+	_, hasDate := columns["date"]
+	_, hasDatetime := columns["datetime"]
+	if i, ok := columns["time"]; ok && !hasDate && !hasDatetime {
+		columns["datetime"] = i
+		delete(columns, "time")
+	}
+	return columns
+}
+
+// errMissing marks a row with an empty or "null" value
+var errMissing = errors.New("missing value")
+
+// parseRow reads one bar. It returns false for rows with missing values.
+func parseRow(record []string, columns map[string]int, interval Interval, loc *time.Location) (Bar, bool, error) {
+	field := func(role string) (string, bool) {
+		i, ok := columns[role]
+		if !ok || i >= len(record) {
+			return "", false
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+	number := func(role string, required bool) (float64, error) {
+		s, ok := field(role)
+		if !ok && !required {
+			return 0, nil
+		}
+		if s == "" || strings.EqualFold(s, "null") || strings.EqualFold(s, "nan") {
+			return 0, errMissing
+		}
+		v, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", role, s)
+		}
+		return v, nil
+	}
+
+	bar := Bar{Interval: interval}
+	bar.Ticker, _ = field("ticker")
+
+	stamp, ok := field("datetime")
+	if !ok {
+		stamp, ok = field("date")
+		if clock, hasTime := field("time"); hasTime && clock != "" {
+			stamp += " " + clock
+		}
+	}
+	if !ok || stamp == "" {
+		return bar, false, fmt.Errorf("no date")
+	}
+	t, err := parseTime(stamp, loc)
+	if err != nil {
+		return bar, false, err
+	}
+	if !interval.Intraday() {
+		t = DayStamp(t)
+	}
+	bar.Time = t
+
+	for _, target := range []struct {
+		role     string
+		value    *float64
+		required bool
+	}{
+		{"open", &bar.Open, true}, {"high", &bar.High, true}, {"low", &bar.Low, true},
+		{"close", &bar.Close, true}, {"volume", &bar.Volume, false},
+	} {
+		v, err := number(target.role, target.required)
+		if errors.Is(err, errMissing) {
+			if !target.required {
+				continue
+			}
+			return bar, false, nil
+		}
+		if err != nil {
+			return bar, false, err
+		}
+		*target.value = v
+	}
+	return bar, true, nil
+}
+
+// parseTime reads a date, date and time, or Unix time in seconds or
+// milliseconds. Values without a zone are in loc.
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && len(s) >= 9 {
+		if len(s) >= 12 {
+			return time.UnixMilli(n).In(loc), nil
+		}
+		return time.Unix(n, 0).In(loc), nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	// A date followed by a separate time column in another layout
+	if date, clock, ok := strings.Cut(s, " "); ok {
+		day, err := parseTime(date, loc)
+		if err == nil {
+			for _, layout := range timeLayouts {
+				if c, err := time.Parse(layout, clock); err == nil {
+					return time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), c.Second(), 0, loc), nil
+				}
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
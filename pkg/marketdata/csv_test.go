@@ -0,0 +1,172 @@
+package marketdata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var eastern = time.FixedZone("EDT", -4*60*60)
+
+func day(d int) time.Time {
+	return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		opts    ImportOptions
+		want    []Bar
+		skipped int
+	}{
+		{"yahoo", "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+			"2025-04-25,11,13,10,12,12,2000\n" +
+			"2025-04-24,10,12,9,11,11,1000\n",
+			ImportOptions{Ticker: " aapl", Interval: "daily", Location: eastern},
+			[]Bar{
+				{Ticker: "AAPL", Interval: Daily, Time: day(24), Open: 10, High: 12, Low: 9, Close: 11, Volume: 1000},
+				{Ticker: "AAPL", Interval: Daily, Time: day(25), Open: 11, High: 13, Low: 10, Close: 12, Volume: 2000},
+			}, 0},
+		{"stooq with a time column", "<TICKER>,<PER>,<DATE>,<TIME>,<OPEN>,<HIGH>,<LOW>,<CLOSE>,<VOL>,<OPENINT>\n" +
+			"msft.us,5,20250424,153000,10,12,9,11,1000,0\n",
+			ImportOptions{Interval: Minute5, Location: time.UTC},
+			[]Bar{{Ticker: "MSFT.US", Interval: Minute5, Time: time.Date(2025, 4, 24, 15, 30, 0, 0, time.UTC),
+				Open: 10, High: 12, Low: 9, Close: 11, Volume: 1000}}, 0},
+		{"tradingview unix times", "time,open,high,low,close,Volume\n1745503200,10,12,9,11,1000\n",
+			ImportOptions{Ticker: "TSLA", Interval: Hour1, Location: time.UTC},
+			[]Bar{{Ticker: "TSLA", Interval: Hour1, Time: time.Date(2025, 4, 24, 14, 0, 0, 0, time.UTC),
+				Open: 10, High: 12, Low: 9, Close: 11, Volume: 1000}}, 0},
+		{"no header, semicolons, no volume", "2025-04-24;10;12;9;11\n\n",
+			ImportOptions{Ticker: "NVDA", Interval: Daily},
+			[]Bar{{Ticker: "NVDA", Interval: Daily, Time: day(24), Open: 10, High: 12, Low: 9, Close: 11}}, 0},
+		{"weekly bar with an evening timestamp keeps its written date", "Date,Open,High,Low,Close\n2025-04-21 20:30,10,12,9,11\n",
+			ImportOptions{Ticker: "AAPL", Interval: Weekly, Location: eastern},
+			[]Bar{{Ticker: "AAPL", Interval: Weekly, Time: day(21), Open: 10, High: 12, Low: 9, Close: 11}}, 0},
+		{"rows with missing prices are skipped", "Date,Open,High,Low,Close,Volume\n" +
+			"2025-04-23,null,null,null,null,null\n" +
+			"2025-04-24,10,12,9,11,\n" +
+			"2025-04-25,,12,9,11,100\n",
+			ImportOptions{Ticker: "AAPL", Interval: Daily},
+			[]Bar{{Ticker: "AAPL", Interval: Daily, Time: day(24), Open: 10, High: 12, Low: 9, Close: 11}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars, result, err := ParseCSV(strings.NewReader(tt.csv), tt.opts)
+			if err != nil {
+				t.Fatalf("ParseCSV: %v", err)
+			}
+			if !reflect.DeepEqual(bars, tt.want) {
+				t.Errorf("bars =\n%+v\nwant\n%+v", bars, tt.want)
+			}
+			if result.Imported != len(tt.want) || result.Skipped != tt.skipped {
+				t.Errorf("imported %d, skipped %d; want %d, %d", result.Imported, result.Skipped, len(tt.want), tt.skipped)
+			}
+			if len(bars) > 0 && (!result.First.Equal(bars[0].Time) || !result.Last.Equal(bars[len(bars)-1].Time)) {
+				t.Errorf("result spans %s to %s, want the first and last bar", result.First, result.Last)
+			}
+		})
+	}
+}
+
+func TestParseCSVRejectsBadRows(t *testing.T) {
+	const header = "Date,Open,High,Low,Close,Volume\n2025-04-24,10,12,9,11,1000\n"
+	tests := []struct {
+		name string
+		csv  string
+		opts ImportOptions
+		want string
+	}{
+		{"unparseable price", header + "2025-04-25,ten,12,9,11,1000\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, `line 3: invalid open "ten"`},
+		{"unrecognized date", header + "someday,10,12,9,11,1000\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, "line 3: unrecognized date"},
+		{"blank date", header + ",10,12,9,11,1000\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, "line 3: no date"},
+		{"low above high", header + "2025-04-25,10,9,12,11,1000\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, "line 3: open and close must be between low and high"},
+		{"close outside the range", header + "2025-04-25,10,12,9,13,1000\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, "line 3: open and close"},
+		{"zero price", header + "2025-04-25,0,12,9,11,1000\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, "line 3: prices must be positive"},
+		{"negative volume", header + "2025-04-25,10,12,9,11,-5\n", ImportOptions{Ticker: "AAPL", Interval: Daily}, "line 3: volume cannot be negative"},
+		{"no ticker", header, ImportOptions{Interval: Daily}, "line 2: ticker is required"},
+		{"unknown interval", header, ImportOptions{Ticker: "AAPL", Interval: "3d"}, `unknown bar interval "3d"`},
+	}
+	for _, tt := range tests {
+		bars, _, err := ParseCSV(strings.NewReader(tt.csv), tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ParseCSV error = %v, want %q", tt.name, err, tt.want)
+		}
+		if bars != nil {
+			t.Errorf("%s: ParseCSV returned %d bars with its error", tt.name, len(bars))
+		}
+	}
+}
+
+func TestDayStamp(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"midnight UTC", day(24), day(24)},
+		{"evening in New York keeps its date", time.Date(2025, 4, 24, 21, 0, 0, 0, eastern), day(24)},
+		{"late UTC", time.Date(2025, 4, 24, 23, 59, 59, 0, time.UTC), day(24)},
+	}
+	for _, tt := range tests {
+		if got := DayStamp(tt.at); !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("%s: DayStamp = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Interval
+		ok   bool
+	}{
+		{"1d", Daily, true},
+		{" Daily ", Daily, true},
+		{"W", Weekly, true},
+		{"60m", Hour1, true},
+		{"15m", Minute15, true},
+		{"2h", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, err := ParseInterval(tt.in)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseInterval(%q) = %q, %v; want %q, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBetweenAndLatest(t *testing.T) {
+	bars := []Bar{{Time: day(22)}, {Time: day(23)}, {Time: day(24)}, {Time: day(25)}}
+	times := func(bars []Bar) []int {
+		out := []int{}
+		for _, b := range bars {
+			out = append(out, b.Time.Day())
+		}
+		return out
+	}
+	ranges := []struct {
+		name       string
+		start, end time.Time
+		want       []int
+	}{
+		{"inclusive", day(23), day(24), []int{23, 24}},
+		{"open start", time.Time{}, day(23), []int{22, 23}},
+		{"open end", day(24), time.Time{}, []int{24, 25}},
+		{"empty", day(26), day(27), []int{}},
+	}
+	for _, tt := range ranges {
+		if got := times(Between(bars, tt.start, tt.end)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Between %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if b, ok := Latest(bars, day(24).Add(time.Hour)); !ok || b.Time.Day() != 24 {
+		t.Errorf("Latest = %v, %v; want the bar of the 24th", b.Time, ok)
+	}
+	if _, ok := Latest(bars, day(21)); ok {
+		t.Error("Latest found a bar before the first")
+	}
+}
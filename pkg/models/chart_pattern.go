@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// Pattern biases
+const (
+	PatternBiasBullish = "bullish"
+	PatternBiasBearish = "bearish"
+	PatternBiasNeutral = "neutral" // Gaps and other patterns that follow the trend they appear in
+)
+
+// Names of the default chart patterns
+const (
+	PatternHighBase                = "High Base"
+	PatternLowBase                 = "Low Base"
+	PatternAscendingTriangle       = "Ascending Triangle"
+	PatternDescendingTriangle      = "Descending Triangle"
+	PatternBullPullback            = "Bull Pullback"
+	PatternBearRally               = "Bear Rally"
+	PatternDoubleTop               = "Double-Top"
+	PatternCupAndHandle            = "Cup-and-Handle"
+	PatternHeadAndShoulders        = "Head and Shoulders"
+	PatternInverseHeadAndShoulders = "Inverse Head and Shoulders"
+	PatternBullishFlag             = "Bullish Flag"
+	PatternBearishFlag             = "Bearish Flag"
+	PatternRisingWedge             = "Rising Wedge"
+	PatternFallingWedge            = "Falling Wedge"
+	PatternDoubleBottom            = "Double Bottom"
+	PatternRoundingBottom          = "Rounding Bottom"
+	PatternBreakawayGap            = "Breakaway Gap"
+	PatternRunawayGap              = "Runaway Gap"
+	PatternExhaustionGap           = "Exhaustion Gap"
+	PatternBullishEngulfing        = "Bullish Engulfing"
+	PatternBearishEngulfing        = "Bearish Engulfing"
+)
+
+// MaxPatternPoints is the most a chart pattern can add to a rating's enthusiasm
+const MaxPatternPoints = 10
+
+// ChartPattern is a chart pattern from the pattern library. Ratings copy
+// its name and points when they are scored, so editing a pattern does
+// not change earlier ratings.
+type ChartPattern struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Bias        string    `json:"bias"`   // bullish, bearish or neutral
+	Points      int       `json:"points"` // Added to enthusiasm; 0 to MaxPatternPoints
+	Description string    `json:"description"`
+	Active      bool      `json:"active"` // Only active patterns can be chosen for new ratings
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Validate checks the name, bias and points
+func (cp *ChartPattern) Validate() error {
+	var v validation.Validator
+	v.Required("name", cp.Name)
+	switch cp.Bias {
+	case PatternBiasBullish, PatternBiasBearish, PatternBiasNeutral:
+	default:
+		v.Add("bias", validation.CodeInvalid,
+			fmt.Sprintf("bias must be %s, %s or %s", PatternBiasBullish, PatternBiasBearish, PatternBiasNeutral))
+	}
+	v.IntRange("points", cp.Points, 0, MaxPatternPoints)
+	return v.Err()
+}
+
+// DefaultChartPatterns returns the patterns the library starts with,
+// scored as they were before patterns could be edited
+func DefaultChartPatterns() []*ChartPattern {
+	patterns := []*ChartPattern{
+		{Name: PatternHighBase, Bias: PatternBiasBullish, Points: 2,
+			Description: "Consolidation pattern near resistance with tight price action, suggesting strength."},
+		{Name: PatternLowBase, Bias: PatternBiasBullish, Points: 2,
+			Description: "Consolidation pattern near support with tight price action, showing potential for reversal."},
+		{Name: PatternAscendingTriangle, Bias: PatternBiasBullish, Points: 3,
+			Description: "Bullish pattern with horizontal resistance and rising support, typically breaks upward."},
+		{Name: PatternDescendingTriangle, Bias: PatternBiasBearish, Points: 3,
+			Description: "Bearish pattern with horizontal support and falling resistance, typically breaks downward."},
+		{Name: PatternBullPullback, Bias: PatternBiasBullish, Points: 2,
+			Description: "Temporary price retreat within an uptrend, often creating a buying opportunity."},
+		{Name: PatternBearRally, Bias: PatternBiasBearish, Points: 2,
+			Description: "Temporary price rise within a downtrend, potentially creating a shorting opportunity."},
+		{Name: PatternDoubleTop, Bias: PatternBiasBearish, Points: 3,
+			Description: "Bearish reversal pattern showing two roughly equal highs, indicating resistance."},
+		{Name: PatternCupAndHandle, Bias: PatternBiasBullish, Points: 4,
+			Description: "Bullish continuation pattern resembling a cup with a handle, signaling continuation."},
+		{Name: PatternHeadAndShoulders, Bias: PatternBiasBearish, Points: 4,
+			Description: "Bearish reversal pattern with three peaks (middle highest), signaling a trend change."},
+		{Name: PatternInverseHeadAndShoulders, Bias: PatternBiasBullish, Points: 4,
+			Description: "Bullish reversal pattern with three troughs (middle lowest), signaling an uptrend."},
+		{Name: PatternBullishFlag, Bias: PatternBiasBullish, Points: 3,
+			Description: "Continuation pattern that forms after a strong upward move, followed by consolidation."},
+		{Name: PatternBearishFlag, Bias: PatternBiasBearish, Points: 3,
+			Description: "Continuation pattern that forms after a strong downward move, followed by consolidation."},
+		{Name: PatternRisingWedge, Bias: PatternBiasBearish, Points: 2,
+			Description: "Pattern with converging trend lines sloping upward, often breaks downward."},
+		{Name: PatternFallingWedge, Bias: PatternBiasBullish, Points: 2,
+			Description: "Pattern with converging trend lines sloping downward, often breaks upward."},
+		{Name: PatternDoubleBottom, Bias: PatternBiasBullish, Points: 3,
+			Description: "Bullish reversal pattern showing two roughly equal lows, indicating support."},
+		{Name: PatternRoundingBottom, Bias: PatternBiasBullish, Points: 3,
+			Description: "Long-term reversal pattern indicating gradual shift from bearish to bullish sentiment."},
+		{Name: PatternBreakawayGap, Bias: PatternBiasNeutral, Points: 3,
+			Description: "Gap that forms at the beginning of a trend, signaling a strong move."},
+		{Name: PatternRunawayGap, Bias: PatternBiasNeutral, Points: 2,
+			Description: "Gap that forms during the middle of a trend, confirming the trend strength."},
+		{Name: PatternExhaustionGap, Bias: PatternBiasNeutral, Points: 1,
+			Description: "Gap that forms near the end of a trend, signaling potential reversal."},
+		{Name: PatternBullishEngulfing, Bias: PatternBiasBullish, Points: 2,
+			Description: "Two-candle reversal pattern where a bullish candle completely engulfs the previous bearish one."},
+		{Name: PatternBearishEngulfing, Bias: PatternBiasBearish, Points: 2,
+			Description: "Two-candle reversal pattern where a bearish candle completely engulfs the previous bullish one."},
+	}
+	for _, p := range patterns {
+		p.Active = true
+	}
+	return patterns
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Trade directions of a stock rating
+const (
+	DirectionBullish = "bullish"
+	DirectionBearish = "bearish"
+)
+
+// Enthusiasm components
+const (
+	EnthusiasmStock      = "stock"
+	EnthusiasmPattern    = "pattern"
+	EnthusiasmSector     = "sector"
+	EnthusiasmMarket     = "market"
+	EnthusiasmConfluence = "confluence"
+	EnthusiasmConflict   = "conflict"
+)
+
+// EnthusiasmVersion identifies the current enthusiasm formula. Bump it
+// when CalculateEnthusiasm changes, with a migration that decides what
+// happens to stored ratings.
+const EnthusiasmVersion = "1"
+
+// Confluence and conflict adjustments of directed ratings
+const (
+	ConfluenceBonus = 2 // Stock sentiment, pattern and sector all favour the direction
+	ConflictPenalty = 2 // Per pair of stock sentiment, pattern and sector pointing opposite ways
+)
+
+// EnthusiasmComponent is one term of a rating's enthusiasm
+type EnthusiasmComponent struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Reason string `json:"reason"`
+}
+
+// CalculateEnthusiasm calculates the enthusiasm rating and its breakdown.
+//
+// A directed rating scores each input by how much it favours Direction:
+// bullish stock, sector and market sentiment count for a bullish trade
+// and against a bearish one, and so do the points of a bullish pattern.
+// Neutral patterns (gaps) count in the direction of the stock sentiment.
+// Stock sentiment, pattern and sector all favouring the direction earns
+// ConfluenceBonus; each pair of them pointing opposite ways relative to
+// the direction costs ConflictPenalty.
+//
+// An undirected rating (no Direction) is scored for the trade its stock
+// sentiment implies, or its pattern bias when the stock sentiment is 0.
+// When neither implies a direction, it adds the stock sentiment, the
+// pattern points, the sector sentiment and half the market sentiment.
+// Ratings without a Sector get no sector term.
+func (sr *StockRating) CalculateEnthusiasm() {
+	var components []EnthusiasmComponent
+	add := func(name string, points int, reason string, args ...any) {
+		components = append(components, EnthusiasmComponent{Name: name, Points: points, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	sector, hasSector := sr.SectorSentiment()
+	market := int(math.Round(float64(sr.MarketSentiment) / 2))
+
+	direction, trade := sr.Direction, "a "+sr.Direction+" trade"
+	if direction == "" {
+		direction = sr.impliedDirection()
+		trade = "an implied " + direction + " trade"
+	}
+
+	if direction == "" {
+		add(EnthusiasmStock, sr.StockSentiment, "stock sentiment %+d", sr.StockSentiment)
+		if sr.Pattern != "" {
+			add(EnthusiasmPattern, sr.PatternPoints, "%s pattern", sr.Pattern)
+		}
+		if hasSector {
+			add(EnthusiasmSector, sector, "%s sentiment %+d", sr.Sector, sector)
+		}
+		add(EnthusiasmMarket, market, "half the market sentiment %+d", sr.MarketSentiment)
+	} else {
+		sign := 1
+		if direction == DirectionBearish {
+			sign = -1
+		}
+		stock := sign * sr.StockSentiment
+		add(EnthusiasmStock, stock, "stock sentiment %+d for %s", sr.StockSentiment, trade)
+
+		pattern := 0
+		if sr.Pattern != "" {
+			switch sr.PatternBias {
+			case PatternBiasBullish:
+				pattern = sign * sr.PatternPoints
+			case PatternBiasBearish:
+				pattern = -sign * sr.PatternPoints
+			default:
+				pattern = sr.PatternPoints * sgn(stock)
+			}
+			reason := "%s pattern is %s"
+			if sr.PatternBias == PatternBiasNeutral || sr.PatternBias == "" {
+				reason = "%s pattern is %s and follows the stock sentiment"
+			}
+			add(EnthusiasmPattern, pattern, reason, sr.Pattern, orNeutral(sr.PatternBias))
+		}
+
+		if hasSector {
+			add(EnthusiasmSector, sign*sector, "%s sentiment %+d for %s", sr.Sector, sector, trade)
+			sector *= sign
+		}
+		add(EnthusiasmMarket, sign*market, "half the market sentiment %+d for %s", sr.MarketSentiment, trade)
+
+		if stock > 0 && pattern > 0 && hasSector && sector > 0 {
+			add(EnthusiasmConfluence, ConfluenceBonus, "stock sentiment, pattern and sector all favour %s", trade)
+		}
+
+		// Which way each input leans relative to the direction
+		type lean struct {
+			dir  int
+			what string
+		}
+		leans := []lean{{sgn(stock), fmt.Sprintf("stock sentiment %+d", sr.StockSentiment)}}
+		if sr.Pattern != "" {
+			dir := sign * biasSign(sr.PatternBias)
+			if dir == 0 {
+				dir = sgn(stock)
+			}
+			leans = append(leans, lean{dir, fmt.Sprintf("the %s %s pattern", orNeutral(sr.PatternBias), sr.Pattern)})
+		}
+		if hasSector {
+			leans = append(leans, lean{sgn(sector), fmt.Sprintf("%s sentiment %+d", sr.Sector, sign*sector)})
+		}
+		for i, a := range leans {
+			for _, b := range leans[i+1:] {
+				if a.dir*b.dir < 0 {
+					add(EnthusiasmConflict, -ConflictPenalty, "%s contradicts %s on %s", a.what, b.what, trade)
+				}
+			}
+		}
+	}
+
+	sr.EnthusiasmRating = 0
+	for _, c := range components {
+		sr.EnthusiasmRating += c.Points
+	}
+	sr.EnthusiasmBreakdown = components
+	sr.EnthusiasmVersion = EnthusiasmVersion
+}
+
+// impliedDirection returns the direction of an undirected rating: that of
+// its stock sentiment, else its pattern bias, else empty
+func (sr *StockRating) impliedDirection() string {
+	lean := sgn(sr.StockSentiment)
+	if lean == 0 && sr.Pattern != "" {
+		lean = biasSign(sr.PatternBias)
+	}
+	switch lean {
+	case 1:
+		return DirectionBullish
+	case -1:
+		return DirectionBearish
+	}
+	return ""
+}
+
+// sgn returns -1, 0 or 1 for the sign of n
+func sgn(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// biasSign returns 1 for a bullish bias, -1 for a bearish one and 0 otherwise
+func biasSign(bias string) int {
+	switch bias {
+	case PatternBiasBullish:
+		return 1
+	case PatternBiasBearish:
+		return -1
+	}
+	return 0
+}
+
+func orNeutral(bias string) string {
+	if bias == "" {
+		return PatternBiasNeutral
+	}
+	return bias
+}
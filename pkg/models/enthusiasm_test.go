@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestCalculateEnthusiasmConflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		rating    StockRating
+		conflicts int
+	}{
+		{"all agree", StockRating{Direction: DirectionBullish, StockSentiment: 2, Pattern: PatternCupAndHandle,
+			PatternBias: PatternBiasBullish, PatternPoints: 4, Sector: SectorTechnology, Technology: 1}, 0},
+		{"sector against stock and pattern", StockRating{Direction: DirectionBullish, StockSentiment: 2, Pattern: PatternCupAndHandle,
+			PatternBias: PatternBiasBullish, PatternPoints: 4, Sector: SectorTechnology, Technology: -2}, 2},
+		{"pattern against stock", StockRating{Direction: DirectionBullish, StockSentiment: 2, Pattern: PatternHeadAndShoulders,
+			PatternBias: PatternBiasBearish, PatternPoints: 4}, 1},
+		{"bearish trade, sector against", StockRating{Direction: DirectionBearish, StockSentiment: -2, Pattern: PatternDoubleTop,
+			PatternBias: PatternBiasBearish, PatternPoints: 3, Sector: SectorTechnology, Technology: 2}, 2},
+		{"neutral pattern follows stock", StockRating{Direction: DirectionBullish, StockSentiment: 1, Pattern: PatternRunawayGap,
+			PatternBias: PatternBiasNeutral, PatternPoints: 2, Sector: SectorTechnology, Technology: -1}, 2},
+		// Undirected ratings are scored for the trade the stock sentiment implies
+		{"undirected", StockRating{StockSentiment: 2, Pattern: PatternHeadAndShoulders,
+			PatternBias: PatternBiasBearish, PatternPoints: 4, Sector: SectorTechnology, Technology: -2}, 2},
+		// ... or, with a neutral stock sentiment, the pattern bias
+		{"undirected, neutral stock, bearish pattern", StockRating{Pattern: PatternDoubleTop,
+			PatternBias: PatternBiasBearish, PatternPoints: 3, Sector: SectorTechnology, Technology: 2}, 1},
+		{"undirected without a lean", StockRating{Pattern: PatternRunawayGap,
+			PatternBias: PatternBiasNeutral, PatternPoints: 2, Sector: SectorTechnology, Technology: -2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			direction := tt.rating.Direction
+			tt.rating.CalculateEnthusiasm()
+			if tt.rating.Direction != direction {
+				t.Errorf("direction = %q, want it left %q", tt.rating.Direction, direction)
+			}
+			conflicts, total := 0, 0
+			for _, c := range tt.rating.EnthusiasmBreakdown {
+				total += c.Points
+				if c.Name == EnthusiasmConflict {
+					conflicts++
+					if c.Points != -ConflictPenalty || c.Reason == "" {
+						t.Errorf("conflict component %+v", c)
+					}
+				}
+			}
+			if conflicts != tt.conflicts {
+				t.Errorf("conflicts = %d, want %d: %+v", conflicts, tt.conflicts, tt.rating.EnthusiasmBreakdown)
+			}
+			if total != tt.rating.EnthusiasmRating {
+				t.Errorf("breakdown sums to %d, rating is %d", total, tt.rating.EnthusiasmRating)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// Option types
+const (
+	OptionCall = "call"
+	OptionPut  = "put"
+)
+
+// Leg sides
+const (
+	SideLong  = "long"
+	SideShort = "short"
+)
+
+// Leg is one option contract line of a trade
+type Leg struct {
+	OptionType string    `json:"optionType"` // "call" or "put"
+	Strike     float64   `json:"strike"`
+	Expiration time.Time `json:"expiration"`
+	Quantity   int       `json:"quantity"`  // Number of contracts, always positive
+	Side       string    `json:"side"`      // "long" (bought) or "short" (sold)
+	FillPrice  float64   `json:"fillPrice"` // Premium per share
+}
+
+// Validate checks the leg on its own, without regard to the strategy
+func (l Leg) Validate() error {
+	var v validation.Validator
+	if l.OptionType != OptionCall && l.OptionType != OptionPut {
+		v.Add("optionType", validation.CodeInvalid, fmt.Sprintf("option type must be %q or %q, got %q", OptionCall, OptionPut, l.OptionType))
+	}
+	if l.Side != SideLong && l.Side != SideShort {
+		v.Add("side", validation.CodeInvalid, fmt.Sprintf("side must be %q or %q, got %q", SideLong, SideShort, l.Side))
+	}
+	if l.Strike <= 0 {
+		v.Add("strike", validation.CodeOutOfRange, "strike must be positive")
+	}
+	if l.Quantity <= 0 {
+		v.Add("quantity", validation.CodeOutOfRange, "quantity must be positive")
+	}
+	if l.Expiration.IsZero() {
+		v.Add("expiration", validation.CodeRequired, "expiration is required")
+	}
+	if l.FillPrice < 0 {
+		v.Add("fillPrice", validation.CodeOutOfRange, "fill price cannot be negative")
+	}
+	return v.Err()
+}
+
+// expiryDay truncates the expiration to its trading day so legs entered
+// with different times or zones on the same day count as one expiry
+func (l Leg) expiryDay() string {
+	return TradingDay(l.Expiration)
+}
+
+// mergeLegs combines legs with the same type, strike, expiry and side
+// (e.g. two separate short calls in a butterfly body) and sorts the
+// result by expiry, then strike
+func mergeLegs(legs []Leg) []Leg {
+	var merged []Leg
+	for _, leg := range legs {
+		found := false
+		for i := range merged {
+			m := &merged[i]
+			if m.OptionType == leg.OptionType && m.Strike == leg.Strike &&
+				m.expiryDay() == leg.expiryDay() && m.Side == leg.Side {
+				m.Quantity += leg.Quantity
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, leg)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].expiryDay() != merged[j].expiryDay() {
+			return merged[i].Expiration.Before(merged[j].Expiration)
+		}
+		return merged[i].Strike < merged[j].Strike
+	})
+	return merged
+}
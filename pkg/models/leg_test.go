@@ -0,0 +1,48 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeLegs(t *testing.T) {
+	eastern := time.FixedZone("EDT", -4*60*60)
+	june := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)
+	leg := func(optionType string, strike float64, expiration time.Time, qty int, side string) Leg {
+		return Leg{OptionType: optionType, Strike: strike, Expiration: expiration, Quantity: qty, Side: side}
+	}
+	tests := []struct {
+		name string
+		legs []Leg
+		want []Leg
+	}{
+		{"butterfly body merges", []Leg{
+			leg(OptionCall, 100, june, 1, SideLong), leg(OptionCall, 105, june, 1, SideShort),
+			leg(OptionCall, 105, june, 1, SideShort), leg(OptionCall, 110, june, 1, SideLong),
+		}, []Leg{
+			leg(OptionCall, 100, june, 1, SideLong), leg(OptionCall, 105, june, 2, SideShort), leg(OptionCall, 110, june, 1, SideLong),
+		}},
+		{"same UTC day at another time or zone merges", []Leg{
+			leg(OptionPut, 50, june, 1, SideShort), leg(OptionPut, 50, june.Add(15*time.Hour).In(eastern), 2, SideShort),
+		}, []Leg{leg(OptionPut, 50, june, 3, SideShort)}},
+		// 21:00 in New York on the 19th is the 20th in UTC
+		{"evening in New York is the next trading day", []Leg{
+			leg(OptionPut, 50, june, 1, SideShort), leg(OptionPut, 50, june.Add(time.Hour).In(eastern), 1, SideShort),
+		}, []Leg{leg(OptionPut, 50, june, 2, SideShort)}},
+		{"different sides stay apart", []Leg{
+			leg(OptionCall, 100, june, 1, SideLong), leg(OptionCall, 100, june, 1, SideShort),
+		}, []Leg{leg(OptionCall, 100, june, 1, SideLong), leg(OptionCall, 100, june, 1, SideShort)}},
+		{"sorted by expiry, then strike", []Leg{
+			leg(OptionCall, 100, july, 1, SideLong), leg(OptionCall, 110, june, 1, SideShort), leg(OptionCall, 105, june, 1, SideShort),
+		}, []Leg{
+			leg(OptionCall, 105, june, 1, SideShort), leg(OptionCall, 110, june, 1, SideShort), leg(OptionCall, 100, july, 1, SideLong),
+		}},
+	}
+	for _, tt := range tests {
+		if got := mergeLegs(tt.legs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: mergeLegs =\n%+v\nwant\n%+v", tt.name, got, tt.want)
+		}
+	}
+}
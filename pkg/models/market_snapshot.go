@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// MarketSnapshot is the market and sector sentiment of one day. Every
+// stock rating of the day refers to it, so they share one backdrop.
+type MarketSnapshot struct {
+	ID                    string    `json:"id"`                    // snapshot_<YYYY-MM-DD>
+	Date                  time.Time `json:"date"`                  // Day the sentiments are for
+	MarketSentiment       int       `json:"marketSentiment"`       // Range: -3 to +3
+	BasicMaterials        int       `json:"basicMaterials"`        // Range: -3 to +3
+	CommunicationServices int       `json:"communicationServices"` // Range: -3 to +3
+	ConsumerCyclical      int       `json:"consumerCyclical"`      // Range: -3 to +3
+	ConsumerDefensive     int       `json:"consumerDefensive"`     // Range: -3 to +3
+	Energy                int       `json:"energy"`                // Range: -3 to +3
+	Financial             int       `json:"financial"`             // Range: -3 to +3
+	Healthcare            int       `json:"healthcare"`            // Range: -3 to +3
+	Industrials           int       `json:"industrials"`           // Range: -3 to +3
+	RealEstate            int       `json:"realEstate"`            // Range: -3 to +3
+	Technology            int       `json:"technology"`            // Range: -3 to +3
+	Utilities             int       `json:"utilities"`             // Range: -3 to +3
+	UpdatedAt             time.Time `json:"updatedAt"`
+}
+
+// Day returns the trading day (YYYY-MM-DD) of the snapshot
+func (ms *MarketSnapshot) Day() string {
+	return TradingDay(ms.Date)
+}
+
+// Validate checks that every sentiment is within -3..+3
+func (ms *MarketSnapshot) Validate() error {
+	var v validation.Validator
+	for _, s := range ms.sentimentFields() {
+		v.IntRange(s.field, s.value, -3, 3)
+	}
+	return v.Err()
+}
+
+// Sentiments returns the market sentiment followed by the sector
+// sentiments in Sectors order
+func (ms *MarketSnapshot) Sentiments() [12]int {
+	var values [12]int
+	for i, s := range ms.sentimentFields() {
+		values[i] = s.value
+	}
+	return values
+}
+
+// SectorSentiment returns the sentiment of sector (any name
+// NormalizeSector accepts), or false if it is unknown
+func (ms *MarketSnapshot) SectorSentiment(sector string) (int, bool) {
+	name, ok := NormalizeSector(sector)
+	if !ok {
+		return 0, false
+	}
+	for i, s := range Sectors {
+		if s == name {
+			return ms.Sentiments()[i+1], true
+		}
+	}
+	return 0, false
+}
+
+type sentimentField struct {
+	field string
+	value int
+}
+
+// sentimentFields pairs each sentiment with its JSON field name
+func (ms *MarketSnapshot) sentimentFields() []sentimentField {
+	return []sentimentField{
+		{"marketSentiment", ms.MarketSentiment},
+		{"basicMaterials", ms.BasicMaterials},
+		{"communicationServices", ms.CommunicationServices},
+		{"consumerCyclical", ms.ConsumerCyclical},
+		{"consumerDefensive", ms.ConsumerDefensive},
+		{"energy", ms.Energy},
+		{"financial", ms.Financial},
+		{"healthcare", ms.Healthcare},
+		{"industrials", ms.Industrials},
+		{"realEstate", ms.RealEstate},
+		{"technology", ms.Technology},
+		{"utilities", ms.Utilities},
+	}
+}
@@ -0,0 +1,338 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Position statuses
+const (
+	PositionOpen   = "open"
+	PositionClosed = "closed"
+)
+
+// Position is the state of a trade derived from its lifecycle events
+type Position struct {
+	TradeID     string    `json:"tradeId"`
+	Status      string    `json:"status"`
+	OpenLegs    []Leg     `json:"openLegs"`    // Net open contracts; FillPrice is the average open price
+	Multiplier  int       `json:"multiplier"`  // Shares per contract
+	RealizedPnl float64   `json:"realizedPnl"` // Dollars from closed contracts, net of costs
+	Costs       float64   `json:"costs"`       // Commissions and fees paid so far
+	OpenedAt    time.Time `json:"openedAt"`
+	ClosedAt    time.Time `json:"closedAt"` // Zero while the position is open
+	HoldingDays float64   `json:"holdingDays"`
+	EventCount  int       `json:"eventCount"`
+	// Realizations break RealizedPnl down by event, so P&L can be
+	// attributed to the day it was realized
+	Realizations []Realization `json:"realizations"`
+}
+
+// Realization is the P&L realized by one event. Commissions and fees
+// count against the day they were paid, including on opening events.
+type Realization struct {
+	Date      time.Time `json:"date"`
+	EventType string    `json:"eventType"`
+	Gross     float64   `json:"gross"` // Dollars from closed contracts
+	Costs     float64   `json:"costs"` // Commissions and fees
+	Net       float64   `json:"net"`
+}
+
+// book tracks the net position in one contract
+type book struct {
+	leg      Leg     // Contract identity (type, strike, expiration)
+	net      int     // Positive long, negative short
+	avgPrice float64 // Average price of the open contracts
+}
+
+// BuildPosition replays a trade's events and returns the resulting
+// position. Without an explicit open event the trade's Legs, filled at
+// EntryDate, open the position. asOf is used for the holding period of
+// positions that are still open. An error means the events are
+// inconsistent (for example, closing contracts that are not held).
+func BuildPosition(trade *Trade, events []*TradeEvent, asOf time.Time) (*Position, error) {
+	sorted := append([]*TradeEvent{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].Date.Before(sorted[j].Date)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	if (len(sorted) == 0 || sorted[0].Type != EventOpen) && len(trade.Legs) > 0 {
+		implicit := &TradeEvent{
+			TradeID:    trade.ID,
+			Type:       EventOpen,
+			Date:       trade.EntryDate,
+			Fills:      trade.Legs,
+			Commission: trade.Commission,
+			Fees:       trade.Fees,
+		}
+		sorted = append([]*TradeEvent{implicit}, sorted...)
+	}
+
+	multiplier := float64(trade.ContractMultiplier())
+	pos := &Position{
+		TradeID:    trade.ID,
+		Status:     PositionOpen,
+		Multiplier: trade.ContractMultiplier(),
+		OpenedAt:   trade.EntryDate,
+		EventCount: len(events),
+	}
+	var books []*book
+
+	for i, event := range sorted {
+		if pos.Status == PositionClosed {
+			return nil, fmt.Errorf("%s event on %s: position was already closed", event.Type, event.Date.Format("2006-01-02"))
+		}
+		if event.Type == EventOpen {
+			if i != 0 {
+				return nil, fmt.Errorf("open event must be the first event")
+			}
+			pos.OpenedAt = event.Date
+		}
+
+		fills, err := eventFills(event, books)
+		if err != nil {
+			return nil, err
+		}
+
+		if event.Commission < 0 || event.Fees < 0 {
+			return nil, fmt.Errorf("%s event: commission and fees cannot be negative", event.Type)
+		}
+
+		before := openQuantity(books)
+		gross := 0.0
+		for _, fill := range fills {
+			if err := fill.Validate(); err != nil {
+				return nil, fmt.Errorf("%s event fill: %w", event.Type, err)
+			}
+			b := findBook(&books, fill)
+			reducing := b.net != 0 && (b.net > 0) != (fill.Side == SideLong)
+			if event.Type == EventPartialClose && (!reducing || fill.Quantity > abs(b.net)) {
+				return nil, fmt.Errorf("partial close may only reduce existing legs")
+			}
+			gross += b.apply(fill) * multiplier
+		}
+
+		costs := event.Commission + event.Fees
+		if gross != 0 || costs != 0 {
+			pos.Realizations = append(pos.Realizations, Realization{
+				Date:      event.Date,
+				EventType: event.Type,
+				Gross:     roundCents(gross),
+				Costs:     roundCents(costs),
+				Net:       roundCents(gross - costs),
+			})
+		}
+		pos.RealizedPnl += gross - costs
+		pos.Costs += costs
+
+		after := openQuantity(books)
+		if event.Type == EventClose && after != 0 {
+			return nil, fmt.Errorf("close event must close every open leg (%d contracts remain); use %s", after, EventPartialClose)
+		}
+
+		if before > 0 && after == 0 {
+			pos.Status = PositionClosed
+			pos.ClosedAt = event.Date
+		}
+	}
+
+	for _, b := range books {
+		if b.net == 0 {
+			continue
+		}
+		leg := b.leg
+		leg.Quantity = abs(b.net)
+		leg.Side = SideLong
+		if b.net < 0 {
+			leg.Side = SideShort
+		}
+		leg.FillPrice = b.avgPrice
+		pos.OpenLegs = append(pos.OpenLegs, leg)
+	}
+
+	end := asOf
+	if pos.Status == PositionClosed {
+		end = pos.ClosedAt
+	}
+	if !pos.OpenedAt.IsZero() && end.After(pos.OpenedAt) {
+		pos.HoldingDays = math.Round(end.Sub(pos.OpenedAt).Hours()/24*10) / 10
+	}
+	pos.RealizedPnl = roundCents(pos.RealizedPnl)
+	pos.Costs = roundCents(pos.Costs)
+	return pos, nil
+}
+
+// UnrealizedPnl values the open legs at mark (price per share for each
+// open leg's contract) and returns the unrealized P&L in dollars. ok is
+// false if any open leg has no mark.
+func (p *Position) UnrealizedPnl(mark func(leg Leg) (float64, bool)) (pnl float64, ok bool) {
+	for _, leg := range p.OpenLegs {
+		price, found := mark(leg)
+		if !found {
+			return 0, false
+		}
+		direction := 1.0
+		if leg.Side == SideShort {
+			direction = -1.0
+		}
+		pnl += (price - leg.FillPrice) * float64(leg.Quantity) * direction * float64(p.Multiplier)
+	}
+	return roundCents(pnl), true
+}
+
+// SameContract reports whether two legs refer to the same option contract
+func SameContract(a, b Leg) bool {
+	return a.OptionType == b.OptionType && a.Strike == b.Strike && a.expiryDay() == b.expiryDay()
+}
+
+// eventFills returns the fills an event applies. Expirations without
+// fills close every leg expiring by the event date at zero. Fills on
+// expire, assigned and exercised events name the held legs (their Side
+// is ignored); assignment and exercise settle them at intrinsic value.
+func eventFills(event *TradeEvent, books []*book) ([]Leg, error) {
+	switch event.Type {
+	case EventExpire, EventAssigned, EventExercised:
+		if event.Type != EventExpire {
+			if len(event.Fills) == 0 {
+				return nil, fmt.Errorf("%s event needs the legs that were %s", event.Type, event.Type)
+			}
+			if event.UnderlyingPrice <= 0 {
+				return nil, fmt.Errorf("%s event needs the underlying price", event.Type)
+			}
+		}
+
+		var fills []Leg
+		if len(event.Fills) == 0 {
+			for _, b := range books {
+				if b.net != 0 && !b.leg.Expiration.After(event.Date) {
+					fills = append(fills, b.closingFill(0))
+				}
+			}
+			if len(fills) == 0 {
+				return nil, fmt.Errorf("no open legs expire by %s", event.Date.Format("2006-01-02"))
+			}
+			return fills, nil
+		}
+
+		for _, held := range event.Fills {
+			b := heldBook(books, held)
+			if b == nil || abs(b.net) < held.Quantity {
+				return nil, fmt.Errorf("%s event: %d %s %.2f contracts are not held", event.Type, held.Quantity, held.OptionType, held.Strike)
+			}
+			price := 0.0
+			if event.Type != EventExpire {
+				price = intrinsicValue(held, event.UnderlyingPrice)
+			}
+			fill := b.closingFill(price)
+			fill.Quantity = held.Quantity
+			fills = append(fills, fill)
+		}
+		return fills, nil
+
+	default:
+		if len(event.Fills) == 0 {
+			return nil, fmt.Errorf("%s event needs at least one fill", event.Type)
+		}
+		return event.Fills, nil
+	}
+}
+
+// heldBook returns the book for leg's contract, or nil if none exists
+func heldBook(books []*book, leg Leg) *book {
+	for _, b := range books {
+		if SameContract(b.leg, leg) {
+			return b
+		}
+	}
+	return nil
+}
+
+// findBook returns the book for fill's contract, creating it if needed
+func findBook(books *[]*book, fill Leg) *book {
+	if b := heldBook(*books, fill); b != nil {
+		return b
+	}
+	b := &book{leg: Leg{OptionType: fill.OptionType, Strike: fill.Strike, Expiration: fill.Expiration}}
+	*books = append(*books, b)
+	return b
+}
+
+// apply adds a fill to the book and returns the realized P&L per share
+// (multiply by the contract multiplier for dollars)
+func (b *book) apply(fill Leg) float64 {
+	signed := fill.Quantity
+	if fill.Side == SideShort {
+		signed = -signed
+	}
+
+	realized := 0.0
+	if b.net != 0 && (b.net > 0) != (signed > 0) {
+		closed := min(abs(signed), abs(b.net))
+		direction := 1.0
+		if b.net < 0 {
+			direction = -1.0
+		}
+		realized = (fill.FillPrice - b.avgPrice) * float64(closed) * direction
+		if b.net > 0 {
+			b.net -= closed
+			signed += closed
+		} else {
+			b.net += closed
+			signed -= closed
+		}
+		if b.net == 0 {
+			b.avgPrice = 0
+		}
+	}
+
+	if signed != 0 {
+		// Whatever is left opens (or adds to) a position at the fill price
+		total := abs(b.net) + abs(signed)
+		b.avgPrice = (b.avgPrice*float64(abs(b.net)) + fill.FillPrice*float64(abs(signed))) / float64(total)
+		b.net += signed
+	}
+	return realized
+}
+
+// closingFill returns the fill that flattens the book at price
+func (b *book) closingFill(price float64) Leg {
+	fill := b.leg
+	fill.Quantity = abs(b.net)
+	fill.Side = SideShort
+	if b.net < 0 {
+		fill.Side = SideLong
+	}
+	fill.FillPrice = price
+	return fill
+}
+
+func openQuantity(books []*book) int {
+	total := 0
+	for _, b := range books {
+		total += abs(b.net)
+	}
+	return total
+}
+
+func intrinsicValue(leg Leg, underlying float64) float64 {
+	if leg.OptionType == OptionCall {
+		return math.Max(underlying-leg.Strike, 0)
+	}
+	return math.Max(leg.Strike-underlying, 0)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -1,6 +1,22 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// Risk assessment factor names, as used in scoring model weights
+const (
+	RiskFactorEmotional = "emotional"
+	RiskFactorFomo      = "fomo"
+	RiskFactorBias      = "bias"
+	RiskFactorPhysical  = "physical"
+	RiskFactorPnl       = "pnl"
+)
+
+// RiskFactors lists every factor of a risk assessment
+var RiskFactors = []string{RiskFactorEmotional, RiskFactorFomo, RiskFactorBias, RiskFactorPhysical, RiskFactorPnl}
 
 // RiskAssessment represents a daily risk assessment entry
 type RiskAssessment struct {
@@ -11,11 +27,54 @@ type RiskAssessment struct {
 	Bias         int       `json:"bias"`         // Range: -3 to +3
 	Physical     int       `json:"physical"`     // Range: -3 to +3
 	Pnl          int       `json:"pnl"`          // Range: -3 to +3
-	OverallScore int       `json:"overallScore"` // Calculated score
+	OverallScore float64   `json:"overallScore"` // Calculated score
+	// ScoringVersion is the version of the scoring model that produced
+	// OverallScore, so later formula changes don't reinterpret history
+	ScoringVersion string `json:"scoringVersion"`
+}
+
+// TradingDay returns the trading day (YYYY-MM-DD) the assessment is
+// for. There is at most one assessment per trading day.
+func (ra *RiskAssessment) TradingDay() string {
+	return TradingDay(ra.Date)
+}
+
+// RiskAssessmentRevision is an earlier version of an assessment, saved
+// when the assessment was edited
+type RiskAssessmentRevision struct {
+	ID           string         `json:"id"`
+	AssessmentID string         `json:"assessmentId"`
+	EditedAt     time.Time      `json:"editedAt"`
+	Previous     RiskAssessment `json:"previous"`
+}
+
+// Validate checks that every factor is within -3..+3
+func (ra *RiskAssessment) Validate() error {
+	var v validation.Validator
+	scores := ra.FactorScores()
+	for _, factor := range RiskFactors {
+		v.IntRange(factor, scores[factor], -3, 3)
+	}
+	return v.Err()
+}
+
+// FactorScores returns the score of each factor, keyed by factor name
+func (ra *RiskAssessment) FactorScores() map[string]int {
+	return map[string]int{
+		RiskFactorEmotional: ra.Emotional,
+		RiskFactorFomo:      ra.Fomo,
+		RiskFactorBias:      ra.Bias,
+		RiskFactorPhysical:  ra.Physical,
+		RiskFactorPnl:       ra.Pnl,
+	}
 }
 
-// CalculateOverallScore calculates the overall risk score
-func (ra *RiskAssessment) CalculateOverallScore() {
-	// Simple average for now, can be enhanced with weighted calculations
-	ra.OverallScore = (ra.Emotional + ra.Fomo + ra.Bias + ra.Physical + ra.Pnl) / 5
+// CalculateOverallScore scores the assessment with model (the default
+// model when nil) and records the model's version
+func (ra *RiskAssessment) CalculateOverallScore(model *RiskScoringModel) {
+	if model == nil {
+		model = DefaultRiskScoringModel()
+	}
+	ra.OverallScore = model.Score(ra)
+	ra.ScoringVersion = model.Version
 }
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// LegacyRiskScoringVersion marks assessments scored by the original
+// truncating integer average, before scoring models existed
+const LegacyRiskScoringVersion = "legacy-average"
+
+// RiskScoringModel turns the factors of a risk assessment into an
+// overall score. Models are identified by Version and never change once
+// saved; a new formula gets a new version.
+type RiskScoringModel struct {
+	Version string `json:"version"`
+	// Weights per factor (see RiskFactors); the score is the weighted
+	// average of the factor scores. Factors without a weight count 0.
+	Weights   map[string]float64 `json:"weights"`
+	Penalties []RiskPenalty      `json:"penalties"`
+}
+
+// RiskPenalty caps the overall score when a factor is at or below a
+// threshold, e.g. {Factor: "", AtOrBelow: -3, Cap: -2} caps the score at
+// -2 whenever any factor is rated -3
+type RiskPenalty struct {
+	Factor    string  `json:"factor"` // Empty means any factor
+	AtOrBelow int     `json:"atOrBelow"`
+	Cap       float64 `json:"cap"`
+}
+
+// DefaultRiskScoringModel weights every factor equally without penalties
+func DefaultRiskScoringModel() *RiskScoringModel {
+	weights := make(map[string]float64, len(RiskFactors))
+	for _, factor := range RiskFactors {
+		weights[factor] = 1
+	}
+	return &RiskScoringModel{Version: "equal-weights-1", Weights: weights, Penalties: []RiskPenalty{}}
+}
+
+// Validate checks the model's version, weights and penalties
+func (m *RiskScoringModel) Validate() error {
+	if m.Version == "" {
+		return fmt.Errorf("scoring model version is required")
+	}
+	if m.Version == LegacyRiskScoringVersion {
+		return fmt.Errorf("scoring model version %q is reserved", LegacyRiskScoringVersion)
+	}
+
+	total := 0.0
+	for factor, weight := range m.Weights {
+		if !isRiskFactor(factor) {
+			return fmt.Errorf("unknown risk factor %q", factor)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight for %s cannot be negative", factor)
+		}
+		total += weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("at least one factor needs a positive weight")
+	}
+
+	for i, p := range m.Penalties {
+		if p.Factor != "" && !isRiskFactor(p.Factor) {
+			return fmt.Errorf("penalty %d: unknown risk factor %q", i+1, p.Factor)
+		}
+		if p.AtOrBelow < -3 || p.AtOrBelow > 3 || p.Cap < -3 || p.Cap > 3 {
+			return fmt.Errorf("penalty %d: threshold and cap must be between -3 and 3", i+1)
+		}
+	}
+	return nil
+}
+
+// Score returns the weighted average of the factor scores, capped by any
+// penalty that applies, rounded to two decimals
+func (m *RiskScoringModel) Score(ra *RiskAssessment) float64 {
+	scores := ra.FactorScores()
+
+	var sum, total float64
+	for _, factor := range RiskFactors {
+		weight := m.Weights[factor]
+		sum += weight * float64(scores[factor])
+		total += weight
+	}
+	score := 0.0
+	if total > 0 {
+		score = sum / total
+	}
+
+	for _, p := range m.Penalties {
+		for _, factor := range RiskFactors {
+			if (p.Factor == "" || p.Factor == factor) && scores[factor] <= p.AtOrBelow {
+				score = math.Min(score, p.Cap)
+			}
+		}
+	}
+	return math.Round(score*100) / 100
+}
+
+func isRiskFactor(name string) bool {
+	for _, factor := range RiskFactors {
+		if factor == name {
+			return true
+		}
+	}
+	return false
+}
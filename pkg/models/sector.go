@@ -0,0 +1,63 @@
+package models
+
+import "strings"
+
+// Sectors are the eleven GICS sectors, named as on the stock rating form
+const (
+	SectorBasicMaterials        = "Basic Materials"
+	SectorCommunicationServices = "Communication Services"
+	SectorConsumerCyclical      = "Consumer Cyclical"
+	SectorConsumerDefensive     = "Consumer Defensive"
+	SectorEnergy                = "Energy"
+	SectorFinancial             = "Financial"
+	SectorHealthcare            = "Healthcare"
+	SectorIndustrials           = "Industrials"
+	SectorRealEstate            = "Real Estate"
+	SectorTechnology            = "Technology"
+	SectorUtilities             = "Utilities"
+)
+
+// Sectors lists every sector
+var Sectors = []string{
+	SectorBasicMaterials, SectorCommunicationServices, SectorConsumerCyclical,
+	SectorConsumerDefensive, SectorEnergy, SectorFinancial, SectorHealthcare,
+	SectorIndustrials, SectorRealEstate, SectorTechnology, SectorUtilities,
+}
+
+// sectorAliases maps official GICS sector names (and the rating form's
+// field names) to the sector names used here. Keys are lower case.
+var sectorAliases = map[string]string{
+	"materials":              SectorBasicMaterials,
+	"basicmaterials":         SectorBasicMaterials,
+	"communicationservices":  SectorCommunicationServices,
+	"consumer discretionary": SectorConsumerCyclical,
+	"consumercyclical":       SectorConsumerCyclical,
+	"consumer staples":       SectorConsumerDefensive,
+	"consumerdefensive":      SectorConsumerDefensive,
+	"financials":             SectorFinancial,
+	"health care":            SectorHealthcare,
+	"information technology": SectorTechnology,
+	"realestate":             SectorRealEstate,
+}
+
+// NormalizeSector returns the sector name for name (a sector, GICS
+// sector or rating field name, in any case), or false if it is unknown
+func NormalizeSector(name string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, sector := range Sectors {
+		if strings.ToLower(sector) == key {
+			return sector, true
+		}
+	}
+	sector, ok := sectorAliases[key]
+	return sector, ok
+}
+
+// TickerSector assigns a ticker to a sector
+type TickerSector struct {
+	Ticker string `json:"ticker"`
+	Sector string `json:"sector"`
+	// BuiltIn is set for entries from the bundled reference table that
+	// have not been edited
+	BuiltIn bool `json:"builtIn"`
+}
@@ -1,12 +1,22 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
 
-// StockRating represents a rating for a stock
+	"trading-dashboard/pkg/validation"
+)
+
+// StockRating represents a rating for a stock. The market and sector
+// sentiments belong to the day's MarketSnapshot (SnapshotID); they are
+// filled in from it when a rating is read and are not stored with it.
+// The enthusiasm rating and its breakdown are stored as calculated when
+// the rating was saved; reads never recalculate them.
 type StockRating struct {
 	ID                    string    `json:"id"`
 	Date                  time.Time `json:"date"`
 	Ticker                string    `json:"ticker"`
+	SnapshotID            string    `json:"snapshotId"`            // Day's market snapshot
 	MarketSentiment       int       `json:"marketSentiment"`       // Range: -3 to +3
 	BasicMaterials        int       `json:"basicMaterials"`        // Range: -3 to +3
 	CommunicationServices int       `json:"communicationServices"` // Range: -3 to +3
@@ -20,45 +30,105 @@ type StockRating struct {
 	Technology            int       `json:"technology"`            // Range: -3 to +3
 	Utilities             int       `json:"utilities"`             // Range: -3 to +3
 	StockSentiment        int       `json:"stockSentiment"`        // Range: -3 to +3
-	Pattern               string    `json:"pattern"`               // Chart pattern
+	Pattern               string    `json:"pattern"`               // Chart pattern name as scored
+	PatternID             string    `json:"patternId"`             // Chart pattern in the pattern library
+	PatternPoints         int       `json:"patternPoints"`         // Pattern's points when the rating was scored
+	PatternBias           string    `json:"patternBias"`           // Pattern's bias when the rating was scored
+	Direction             string    `json:"direction"`             // Intended trade: bullish, bearish or empty for an undirected rating
+	Sector                string    `json:"sector"`                // Ticker's sector, from the sector table
 	EnthusiasmRating      int       `json:"enthusiasmRating"`      // Calculated rating
+	// EnthusiasmBreakdown explains each component of EnthusiasmRating
+	EnthusiasmBreakdown []EnthusiasmComponent `json:"enthusiasmBreakdown"`
+	// EnthusiasmVersion is the version of the enthusiasm formula that
+	// produced EnthusiasmRating, so formula changes don't reinterpret history
+	EnthusiasmVersion string    `json:"enthusiasmVersion"`
+	UpdatedAt         time.Time `json:"updatedAt"` // When this version was saved
+}
+
+// StockRatingRevision is an earlier version of a rating, saved when the
+// ticker was rated again the same day or the rating was edited
+type StockRatingRevision struct {
+	ID       string      `json:"id"`
+	RatingID string      `json:"ratingId"`
+	EditedAt time.Time   `json:"editedAt"`
+	Previous StockRating `json:"previous"` // As it was read, with its day's sentiments and enthusiasm
 }
 
-// CalculateEnthusiasm calculates the enthusiasm rating
-func (sr *StockRating) CalculateEnthusiasm() {
-	// Map of pattern values
-	patternPoints := map[string]int{
-		// Original patterns
-		"High Base":           2,
-		"Low Base":            2,
-		"Ascending Triangle":  3,
-		"Descending Triangle": 3,
-		"Bull Pullback":       2,
-		"Bear Rally":          2,
-		"Double-Top":          3,
-		"Cup-and-Handle":      4,
-		// Additional patterns
-		"Head and Shoulders":         4,
-		"Inverse Head and Shoulders": 4,
-		"Bullish Flag":               3,
-		"Bearish Flag":               3,
-		"Rising Wedge":               2,
-		"Falling Wedge":              2,
-		"Double Bottom":              3,
-		"Rounding Bottom":            3,
-		"Breakaway Gap":              3,
-		"Runaway Gap":                2,
-		"Exhaustion Gap":             1,
-		"Bullish Engulfing":          2,
-		"Bearish Engulfing":          2,
+// SavedAt returns when the version was saved; ratings saved before
+// UpdatedAt was recorded use their date
+func (sr *StockRating) SavedAt() time.Time {
+	if sr.UpdatedAt.IsZero() {
+		return sr.Date
+	}
+	return sr.UpdatedAt
+}
+
+// Validate checks the ticker and that every slider is within -3..+3
+func (sr *StockRating) Validate() error {
+	var v validation.Validator
+	v.Required("ticker", sr.Ticker)
+	if _, ok := NormalizeSector(sr.Sector); sr.Sector != "" && !ok {
+		v.Add("sector", validation.CodeInvalid, fmt.Sprintf("unknown sector %q", sr.Sector))
+	}
+	for _, s := range sr.MarketSnapshot().sentimentFields() {
+		v.IntRange(s.field, s.value, -3, 3)
 	}
+	v.IntRange("stockSentiment", sr.StockSentiment, -3, 3)
+	switch sr.Direction {
+	case "", DirectionBullish, DirectionBearish:
+	default:
+		v.Add("direction", validation.CodeInvalid,
+			fmt.Sprintf("direction must be %q, %q or empty, got %q", DirectionBullish, DirectionBearish, sr.Direction))
+	}
+	return v.Err()
+}
+
+// TradingDay returns the trading day (YYYY-MM-DD) of the rating
+func (sr *StockRating) TradingDay() string {
+	return TradingDay(sr.Date)
+}
 
-	// Get pattern points, default to 0 if pattern not found
-	points, exists := patternPoints[sr.Pattern]
-	if !exists {
-		points = 0
+// MarketSnapshot returns the market and sector sentiments the rating
+// carries as a snapshot for its day
+func (sr *StockRating) MarketSnapshot() *MarketSnapshot {
+	return &MarketSnapshot{
+		ID:                    sr.SnapshotID,
+		Date:                  sr.Date,
+		MarketSentiment:       sr.MarketSentiment,
+		BasicMaterials:        sr.BasicMaterials,
+		CommunicationServices: sr.CommunicationServices,
+		ConsumerCyclical:      sr.ConsumerCyclical,
+		ConsumerDefensive:     sr.ConsumerDefensive,
+		Energy:                sr.Energy,
+		Financial:             sr.Financial,
+		Healthcare:            sr.Healthcare,
+		Industrials:           sr.Industrials,
+		RealEstate:            sr.RealEstate,
+		Technology:            sr.Technology,
+		Utilities:             sr.Utilities,
 	}
+}
+
+// ApplyMarketSnapshot references snapshot and copies its sentiments into
+// the rating
+func (sr *StockRating) ApplyMarketSnapshot(snapshot *MarketSnapshot) {
+	sr.SnapshotID = snapshot.ID
+	sr.MarketSentiment = snapshot.MarketSentiment
+	sr.BasicMaterials = snapshot.BasicMaterials
+	sr.CommunicationServices = snapshot.CommunicationServices
+	sr.ConsumerCyclical = snapshot.ConsumerCyclical
+	sr.ConsumerDefensive = snapshot.ConsumerDefensive
+	sr.Energy = snapshot.Energy
+	sr.Financial = snapshot.Financial
+	sr.Healthcare = snapshot.Healthcare
+	sr.Industrials = snapshot.Industrials
+	sr.RealEstate = snapshot.RealEstate
+	sr.Technology = snapshot.Technology
+	sr.Utilities = snapshot.Utilities
+}
 
-	// Calculate enthusiasm based on stock sentiment and pattern
-	sr.EnthusiasmRating = sr.StockSentiment + points
+// SectorSentiment returns the sentiment of the rating's sector, or false
+// if Sector is empty or unknown
+func (sr *StockRating) SectorSentiment() (int, bool) {
+	return sr.MarketSnapshot().SectorSentiment(sr.Sector)
 }
@@ -0,0 +1,352 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// legRule checks that merged legs (see mergeLegs) form a strategy
+type legRule func(legs []Leg) error
+
+// strategyRules maps the strategy types offered by the trade calendar to
+// the leg structure they require. Strategy types not listed here are
+// saved without a structure check.
+var strategyRules = map[string]legRule{
+	// Basic
+	"Long Call":  single(OptionCall, SideLong),
+	"Long Put":   single(OptionPut, SideLong),
+	"Short Call": single(OptionCall, SideShort),
+	"Short Put":  single(OptionPut, SideShort),
+	// Vertical spreads
+	"Bull Call Spread": vertical(OptionCall, SideLong),
+	"Bear Call Spread": vertical(OptionCall, SideShort),
+	"Bull Put Spread":  vertical(OptionPut, SideLong),
+	"Bear Put Spread":  vertical(OptionPut, SideShort),
+	// Calendar spreads
+	"Long Calendar Call Spread": calendar(OptionCall),
+	"Long Calendar Put Spread":  calendar(OptionPut),
+	// Diagonal spreads
+	"Diagonal Call Spread Up":   diagonal(OptionCall, true),
+	"Diagonal Call Spread Down": diagonal(OptionCall, false),
+	"Diagonal Put Spread Up":    diagonal(OptionPut, true),
+	"Diagonal Put Spread Down":  diagonal(OptionPut, false),
+	// Butterflies
+	"Long Call Butterfly":        butterfly(OptionCall, 0),
+	"Long Put Butterfly":         butterfly(OptionPut, 0),
+	"Broken Wing Butterfly Up":   butterfly("", 1),
+	"Broken Wing Butterfly Down": butterfly("", -1),
+	// Iron condors/butterflies
+	"Iron Condor":    iron(false),
+	"Iron Butterfly": iron(true),
+	// Ratio spreads
+	"Call Ratio Backspread": ratio(OptionCall, true, true),
+	"Put Ratio Backspread":  ratio(OptionPut, false, true),
+	"Call Ratio Spread":     ratio(OptionCall, false, false),
+	"Put Ratio Spread":      ratio(OptionPut, true, false),
+}
+
+// ValidateLegs checks every leg and, for known strategy types, that the
+// legs have the structure the strategy requires. Trades without legs
+// (entered before legs existed) pass.
+func (t *Trade) ValidateLegs() error {
+	if len(t.Legs) == 0 {
+		return nil
+	}
+	for i, leg := range t.Legs {
+		if err := leg.Validate(); err != nil {
+			return fmt.Errorf("leg %d: %w", i+1, err)
+		}
+	}
+
+	rule, known := strategyRules[t.StrategyType]
+	if !known {
+		return nil
+	}
+	if err := rule(mergeLegs(t.Legs)); err != nil {
+		return fmt.Errorf("legs do not match %s: %w", t.StrategyType, err)
+	}
+	return nil
+}
+
+// NearestExpiration returns the earliest leg expiration (zero without legs)
+func (t *Trade) NearestExpiration() time.Time {
+	var nearest time.Time
+	for _, leg := range t.Legs {
+		if nearest.IsZero() || leg.Expiration.Before(nearest) {
+			nearest = leg.Expiration
+		}
+	}
+	return nearest
+}
+
+// Units returns the number of complete structures the legs make (the
+// smallest merged leg quantity, so a 1:2:1 butterfly is one unit)
+func (t *Trade) Units() int {
+	units := 0
+	for _, leg := range mergeLegs(t.Legs) {
+		if units == 0 || leg.Quantity < units {
+			units = leg.Quantity
+		}
+	}
+	return units
+}
+
+func single(optionType, side string) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 1); err != nil {
+			return err
+		}
+		return expectLeg(legs[0], optionType, side, "")
+	}
+}
+
+// vertical: two strikes, same expiry and size; lowerSide is the side of
+// the lower strike and the higher strike takes the other side
+func vertical(optionType, lowerSide string) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 2); err != nil {
+			return err
+		}
+		if err := sameExpiry(legs); err != nil {
+			return err
+		}
+		if err := sameQuantity(legs); err != nil {
+			return err
+		}
+		low, high := legs[0], legs[1]
+		if low.Strike == high.Strike {
+			return fmt.Errorf("strikes must differ")
+		}
+		if err := expectLeg(low, optionType, lowerSide, "lower strike"); err != nil {
+			return err
+		}
+		return expectLeg(high, optionType, opposite(lowerSide), "higher strike")
+	}
+}
+
+// calendar: same strike, short the near expiry and long the far one
+func calendar(optionType string) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 2); err != nil {
+			return err
+		}
+		near, far := legs[0], legs[1]
+		if near.expiryDay() == far.expiryDay() {
+			return fmt.Errorf("expirations must differ")
+		}
+		if near.Strike != far.Strike {
+			return fmt.Errorf("both legs must use the same strike")
+		}
+		if err := sameQuantity(legs); err != nil {
+			return err
+		}
+		if err := expectLeg(near, optionType, SideShort, "near-term"); err != nil {
+			return err
+		}
+		return expectLeg(far, optionType, SideLong, "longer-term")
+	}
+}
+
+// diagonal: long the far expiry, short the near one at a different
+// strike; longLower says whether the long leg has the lower strike
+func diagonal(optionType string, longLower bool) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 2); err != nil {
+			return err
+		}
+		near, far := legs[0], legs[1]
+		if near.expiryDay() == far.expiryDay() {
+			return fmt.Errorf("expirations must differ")
+		}
+		if err := sameQuantity(legs); err != nil {
+			return err
+		}
+		if err := expectLeg(near, optionType, SideShort, "near-term"); err != nil {
+			return err
+		}
+		if err := expectLeg(far, optionType, SideLong, "longer-term"); err != nil {
+			return err
+		}
+		if longLower && far.Strike >= near.Strike {
+			return fmt.Errorf("long leg must have the lower strike")
+		}
+		if !longLower && far.Strike <= near.Strike {
+			return fmt.Errorf("long leg must have the higher strike")
+		}
+		return nil
+	}
+}
+
+// butterfly: long 1 / short 2 / long 1 across three strikes with one
+// expiry. wing 0 requires even spacing, 1 a wider upper wing and -1 a
+// wider lower wing. An empty optionType accepts calls or puts.
+func butterfly(optionType string, wing int) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 3); err != nil {
+			return err
+		}
+		if err := sameExpiry(legs); err != nil {
+			return err
+		}
+		want := optionType
+		if want == "" {
+			want = legs[0].OptionType
+		}
+		low, body, high := legs[0], legs[1], legs[2]
+		if err := expectLeg(low, want, SideLong, "lower wing"); err != nil {
+			return err
+		}
+		if err := expectLeg(body, want, SideShort, "body"); err != nil {
+			return err
+		}
+		if err := expectLeg(high, want, SideLong, "upper wing"); err != nil {
+			return err
+		}
+		if low.Quantity != high.Quantity || body.Quantity != 2*low.Quantity {
+			return fmt.Errorf("quantities must be in a 1:2:1 ratio")
+		}
+
+		lower, upper := body.Strike-low.Strike, high.Strike-body.Strike
+		switch {
+		case lower <= 0 || upper <= 0:
+			return fmt.Errorf("body strike must be between the wing strikes")
+		case wing == 0 && !sameFloat(lower, upper):
+			return fmt.Errorf("strikes must be evenly spaced")
+		case wing > 0 && upper <= lower:
+			return fmt.Errorf("upper wing must be wider than the lower wing")
+		case wing < 0 && lower <= upper:
+			return fmt.Errorf("lower wing must be wider than the upper wing")
+		}
+		return nil
+	}
+}
+
+// iron: long put < short put <= short call < long call, one expiry and
+// size; the short strikes meet for an iron butterfly
+func iron(shortsMeet bool) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 4); err != nil {
+			return err
+		}
+		if err := sameExpiry(legs); err != nil {
+			return err
+		}
+		if err := sameQuantity(legs); err != nil {
+			return err
+		}
+
+		var longPut, shortPut, shortCall, longCall *Leg
+		for i := range legs {
+			leg := &legs[i]
+			switch {
+			case leg.OptionType == OptionPut && leg.Side == SideLong:
+				longPut = leg
+			case leg.OptionType == OptionPut && leg.Side == SideShort:
+				shortPut = leg
+			case leg.OptionType == OptionCall && leg.Side == SideShort:
+				shortCall = leg
+			case leg.OptionType == OptionCall && leg.Side == SideLong:
+				longCall = leg
+			}
+		}
+		if longPut == nil || shortPut == nil || shortCall == nil || longCall == nil {
+			return fmt.Errorf("needs one long put, one short put, one short call and one long call")
+		}
+		if longPut.Strike >= shortPut.Strike {
+			return fmt.Errorf("long put strike must be below the short put strike")
+		}
+		if longCall.Strike <= shortCall.Strike {
+			return fmt.Errorf("long call strike must be above the short call strike")
+		}
+		if shortsMeet && shortPut.Strike != shortCall.Strike {
+			return fmt.Errorf("short put and short call must share a strike")
+		}
+		if !shortsMeet && shortPut.Strike >= shortCall.Strike {
+			return fmt.Errorf("short put strike must be below the short call strike")
+		}
+		return nil
+	}
+}
+
+// ratio: two strikes, one expiry, unequal size. longHigher says whether
+// the long leg has the higher strike and longMore whether it is the
+// larger leg (a backspread) or the smaller one (a ratio spread).
+func ratio(optionType string, longHigher, longMore bool) legRule {
+	return func(legs []Leg) error {
+		if err := expectLegs(legs, 2); err != nil {
+			return err
+		}
+		if err := sameExpiry(legs); err != nil {
+			return err
+		}
+		low, high := legs[0], legs[1]
+		if low.Strike == high.Strike {
+			return fmt.Errorf("strikes must differ")
+		}
+
+		long, short := low, high
+		if longHigher {
+			long, short = high, low
+		}
+		if err := expectLeg(long, optionType, SideLong, "long"); err != nil {
+			return err
+		}
+		if err := expectLeg(short, optionType, SideShort, "short"); err != nil {
+			return err
+		}
+		if longMore && long.Quantity <= short.Quantity {
+			return fmt.Errorf("must buy more contracts than are sold")
+		}
+		if !longMore && short.Quantity <= long.Quantity {
+			return fmt.Errorf("must sell more contracts than are bought")
+		}
+		return nil
+	}
+}
+
+func expectLegs(legs []Leg, n int) error {
+	if len(legs) != n {
+		return fmt.Errorf("expected %d distinct legs, got %d", n, len(legs))
+	}
+	return nil
+}
+
+func expectLeg(leg Leg, optionType, side, label string) error {
+	if label != "" {
+		label += " "
+	}
+	if leg.OptionType != optionType || leg.Side != side {
+		return fmt.Errorf("%sleg must be a %s %s, got a %s %s", label, side, optionType, leg.Side, leg.OptionType)
+	}
+	return nil
+}
+
+func sameExpiry(legs []Leg) error {
+	for _, leg := range legs[1:] {
+		if leg.expiryDay() != legs[0].expiryDay() {
+			return fmt.Errorf("all legs must share one expiration")
+		}
+	}
+	return nil
+}
+
+func sameQuantity(legs []Leg) error {
+	for _, leg := range legs[1:] {
+		if leg.Quantity != legs[0].Quantity {
+			return fmt.Errorf("all legs must have the same quantity")
+		}
+	}
+	return nil
+}
+
+func opposite(side string) string {
+	if side == SideLong {
+		return SideShort
+	}
+	return SideLong
+}
+
+func sameFloat(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
 
 // Trade represents a trading position
 type Trade struct {
@@ -14,4 +19,93 @@ type Trade struct {
 	StrategyType   string    `json:"strategyType"`
 	SpreadType     string    `json:"spreadType"`
 	Direction      string    `json:"direction"`
+	Legs           []Leg     `json:"legs"`       // Option legs; empty for trades entered before legs existed
+	Multiplier     int       `json:"multiplier"` // Shares per contract; 0 means DefaultMultiplier
+	Commission     float64   `json:"commission"` // Opening commission in dollars
+	Fees           float64   `json:"fees"`       // Opening exchange/regulatory fees in dollars
+	MaxLoss        float64   `json:"maxLoss"`    // Dollars the whole trade can lose; 0 if not entered
+	// SizeCheck records the position-size check made when the trade was
+	// first saved; nil if no check was made
+	SizeCheck *SizeCheck `json:"sizeCheck,omitempty"`
+	// EntryContext records the rating and risk assessment that were
+	// current when the trade was entered; nil until it is linked
+	EntryContext *EntryContext `json:"entryContext,omitempty"`
+}
+
+// EntryContext links a trade to the ticker's latest stock rating on or
+// before the entry day and to the entry day's risk assessment, keeping
+// their scores as they were when the trade was linked
+type EntryContext struct {
+	LinkedAt         time.Time `json:"linkedAt"`
+	StockRatingID    string    `json:"stockRatingId"`    // Empty if the ticker had no rating
+	RatingDate       string    `json:"ratingDate"`       // Day of the rating, YYYY-MM-DD
+	EnthusiasmRating int       `json:"enthusiasmRating"` // Rating's enthusiasm when linked
+	RiskAssessmentID string    `json:"riskAssessmentId"` // Empty if the entry day had no assessment
+	RiskScore        float64   `json:"riskScore"`        // Assessment's overall score when linked
+}
+
+// HasRating reports whether the trade is linked to a stock rating
+func (ec *EntryContext) HasRating() bool {
+	return ec != nil && ec.StockRatingID != ""
+}
+
+// HasRiskAssessment reports whether the trade is linked to a risk assessment
+func (ec *EntryContext) HasRiskAssessment() bool {
+	return ec != nil && ec.RiskAssessmentID != ""
+}
+
+// SizeCheck is the outcome of checking a trade against the recommended
+// position size
+type SizeCheck struct {
+	CheckedAt        time.Time `json:"checkedAt"`
+	Units            int       `json:"units"`
+	RecommendedUnits int       `json:"recommendedUnits"`
+	Exceeded         bool      `json:"exceeded"`
+	Reasons          []string  `json:"reasons"`
+}
+
+// DefaultMultiplier is the number of shares per equity option contract
+const DefaultMultiplier = 100
+
+// ContractMultiplier returns the shares per contract used for P&L
+func (t *Trade) ContractMultiplier() int {
+	if t.Multiplier > 0 {
+		return t.Multiplier
+	}
+	return DefaultMultiplier
+}
+
+// Validate checks the trade's fields, each leg and, for known strategy
+// types, that the legs form the strategy
+func (t *Trade) Validate() error {
+	var v validation.Validator
+	v.Required("ticker", t.Ticker)
+	if t.EntryDate.IsZero() {
+		v.Add("entryDate", validation.CodeRequired, "entryDate is required")
+	}
+	// Compare trading days so same-day (0DTE) expirations are allowed
+	if !t.EntryDate.IsZero() && !t.ExpirationDate.IsZero() &&
+		TradingDay(t.ExpirationDate) < TradingDay(t.EntryDate) {
+		v.Add("expirationDate", validation.CodeOrder, "expirationDate must be on or after entryDate")
+	}
+	if t.Multiplier < 0 {
+		v.Add("multiplier", validation.CodeOutOfRange, "multiplier cannot be negative")
+	}
+	v.NotNegative("commission", t.Commission)
+	v.NotNegative("fees", t.Fees)
+	v.NotNegative("maxLoss", t.MaxLoss)
+
+	legsValid := true
+	for i, leg := range t.Legs {
+		if err := leg.Validate(); err != nil {
+			v.Nested(fmt.Sprintf("legs[%d]", i), fmt.Sprintf("leg %d", i+1), err)
+			legsValid = false
+		}
+	}
+	if legsValid {
+		if err := t.ValidateLegs(); err != nil {
+			v.Add("legs", validation.CodeStrategy, err.Error())
+		}
+	}
+	return v.Err()
 }
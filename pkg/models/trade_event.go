@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// Trade lifecycle event types
+const (
+	EventOpen         = "open"
+	EventAdjust       = "adjust"
+	EventRoll         = "roll"
+	EventPartialClose = "partial_close"
+	EventClose        = "close"
+	EventExpire       = "expire"
+	EventAssigned     = "assigned"
+	EventExercised    = "exercised"
+)
+
+// TradeEventTypes lists every lifecycle event type
+var TradeEventTypes = []string{
+	EventOpen, EventAdjust, EventRoll, EventPartialClose,
+	EventClose, EventExpire, EventAssigned, EventExercised,
+}
+
+// TradeEvent is one change to a trade's position. Events are append-only;
+// the current position is derived by replaying them (see BuildPosition).
+type TradeEvent struct {
+	ID      string    `json:"id"`
+	TradeID string    `json:"tradeId"`
+	Type    string    `json:"type"`
+	Date    time.Time `json:"date"`
+	// Fills are the contracts traded by this event. Side is the direction
+	// of the transaction: "long" bought, "short" sold. Closing a short
+	// call is therefore a "long" fill of that call.
+	Fills []Leg `json:"fills"`
+	// UnderlyingPrice settles assigned/exercised legs at intrinsic value
+	UnderlyingPrice float64 `json:"underlyingPrice"`
+	Commission      float64 `json:"commission"` // Dollars
+	Fees            float64 `json:"fees"`       // Dollars
+	Notes           string  `json:"notes"`
+}
+
+// IsValidTradeEventType reports whether eventType is a known lifecycle event
+func IsValidTradeEventType(eventType string) bool {
+	for _, t := range TradeEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the event's fields and fills. Whether the event fits
+// the position is checked by replaying it (see BuildPosition).
+func (e *TradeEvent) Validate() error {
+	var v validation.Validator
+	v.Required("tradeId", e.TradeID)
+	if !IsValidTradeEventType(e.Type) {
+		v.Add("type", validation.CodeInvalid, fmt.Sprintf("unknown trade event type %q", e.Type))
+	}
+	v.NotNegative("commission", e.Commission)
+	v.NotNegative("fees", e.Fees)
+	v.NotNegative("underlyingPrice", e.UnderlyingPrice)
+	for i, fill := range e.Fills {
+		// Fills of expire/assigned/exercised events name held legs; their side is ignored
+		if e.Type == EventExpire || e.Type == EventAssigned || e.Type == EventExercised {
+			fill.Side = SideLong
+		}
+		v.Nested(fmt.Sprintf("fills[%d]", i), fmt.Sprintf("fill %d", i+1), fill.Validate())
+	}
+	return v.Err()
+}
+
+// OpensContracts reports whether the event adds contracts to pos: one of
+// its fills buys or sells a contract that is not held, adds to a held
+// leg or turns it to the other side. Expire, assigned and exercised
+// events only ever take contracts off.
+func (e *TradeEvent) OpensContracts(pos *Position) bool {
+	if e.Type == EventExpire || e.Type == EventAssigned || e.Type == EventExercised {
+		return false
+	}
+	books := make([]*book, 0, len(pos.OpenLegs))
+	for _, leg := range pos.OpenLegs {
+		b := &book{leg: leg, net: leg.Quantity}
+		if leg.Side == SideShort {
+			b.net = -b.net
+		}
+		books = append(books, b)
+	}
+	for _, fill := range e.Fills {
+		b := heldBook(books, fill)
+		if b == nil || b.net == 0 || (b.net > 0) == (fill.Side == SideLong) || fill.Quantity > abs(b.net) {
+			return true
+		}
+		if b.net > 0 {
+			b.net -= fill.Quantity
+		} else {
+			b.net += fill.Quantity
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+func TestTradeValidateExpiration(t *testing.T) {
+	eastern := time.FixedZone("EDT", -4*60*60)
+	entry := time.Date(2025, 4, 25, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		entry      time.Time
+		expiration time.Time
+		ok         bool
+	}{
+		{"same day (0DTE)", entry, time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), true},
+		{"later day", entry, time.Date(2025, 5, 16, 0, 0, 0, 0, time.UTC), true},
+		{"day before", entry, time.Date(2025, 4, 24, 0, 0, 0, 0, time.UTC), false},
+		// 21:00 in New York on the 24th is the 25th in UTC
+		{"entered the evening before in New York", time.Date(2025, 4, 24, 21, 0, 0, 0, eastern),
+			time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), true},
+		{"expiring the evening before in New York", entry, time.Date(2025, 4, 24, 21, 0, 0, 0, eastern), true},
+		{"no expiration", entry, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		trade := &Trade{Ticker: "AAPL", EntryDate: tt.entry, ExpirationDate: tt.expiration}
+		fields, _ := validation.Fields(trade.Validate())
+		failed := false
+		for _, fe := range fields {
+			failed = failed || fe.Field == "expirationDate"
+		}
+		if failed == tt.ok {
+			t.Errorf("%s: expirationDate errors %+v, want ok %v", tt.name, fields, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// DayLayout formats a trading day as YYYY-MM-DD
+const DayLayout = "2006-01-02"
+
+// TradingDay returns the trading day (YYYY-MM-DD) t falls on. Trading
+// days are UTC calendar days: the frontend sends day-only dates as UTC
+// midnight, so this keeps an entered date on the day it was entered for.
+// Every package that buckets records by day uses it, so a trade, its
+// rating and assessment, its P&L and the loss limits agree on the day.
+func TradingDay(t time.Time) string {
+	return t.UTC().Format(DayLayout)
+}
+
+// DayStart returns the start (UTC midnight) of the trading day t falls on
+func DayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
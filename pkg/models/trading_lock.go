@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// TradingLock records a loss limit or risk score check that locked
+// trading. A lock is active until Until unless it was overridden.
+type TradingLock struct {
+	ID         string    `json:"id"`
+	Rule       string    `json:"rule"`   // See the limits package rule names
+	Period     string    `json:"period"` // Day or week that tripped it
+	Reason     string    `json:"reason"`
+	TrippedAt  time.Time `json:"trippedAt"`
+	Until      time.Time `json:"until"`
+	Loss       float64   `json:"loss"`       // Realized loss in the period when it tripped
+	OverrideID string    `json:"overrideId"` // Empty unless overridden
+	// LossAtOverride is the realized loss in the period when the lock was
+	// overridden; the rule trips again once the loss grows by its limit
+	LossAtOverride float64 `json:"lossAtOverride"`
+}
+
+// IsActive reports whether the lock still blocks trading at now
+func (l *TradingLock) IsActive(now time.Time) bool {
+	return l.OverrideID == "" && now.Before(l.Until)
+}
+
+// LockOverride records a manual override of active trading locks
+type LockOverride struct {
+	ID      string    `json:"id"`
+	Date    time.Time `json:"date"`
+	Reason  string    `json:"reason"`
+	LockIDs []string  `json:"lockIds"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/validation"
+)
+
+// Watchlist is a named shortlist of tickers to trade from
+type Watchlist struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Items     []WatchlistItem `json:"items"`
+	Notes     string          `json:"notes"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// WatchlistItem is one ticker on a watchlist with the levels it is
+// traded at. Zero levels are not set.
+type WatchlistItem struct {
+	Ticker            string    `json:"ticker"`
+	TargetEntry       float64   `json:"targetEntry"`       // Price to enter at
+	InvalidationLevel float64   `json:"invalidationLevel"` // Price at which the setup is void
+	Notes             string    `json:"notes"`
+	AddedAt           time.Time `json:"addedAt"`
+}
+
+// Validate checks the item's ticker and levels
+func (wi *WatchlistItem) Validate() error {
+	var v validation.Validator
+	v.Required("ticker", wi.Ticker)
+	v.NotNegative("targetEntry", wi.TargetEntry)
+	v.NotNegative("invalidationLevel", wi.InvalidationLevel)
+	return v.Err()
+}
+
+// Validate checks the name and every item, and that no ticker is listed twice
+func (w *Watchlist) Validate() error {
+	var v validation.Validator
+	v.Required("name", w.Name)
+	seen := make(map[string]bool)
+	for i, item := range w.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		v.Nested(field, fmt.Sprintf("item %d", i+1), item.Validate())
+		ticker := strings.ToUpper(strings.TrimSpace(item.Ticker))
+		if ticker != "" && seen[ticker] {
+			v.Add(field+".ticker", validation.CodeInvalid, fmt.Sprintf("%s is already on the watchlist", ticker))
+		}
+		seen[ticker] = true
+	}
+	return v.Err()
+}
+
+// Item returns the watchlist's item for ticker, or nil
+func (w *Watchlist) Item(ticker string) *WatchlistItem {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	for i := range w.Items {
+		if w.Items[i].Ticker == ticker {
+			return &w.Items[i]
+		}
+	}
+	return nil
+}
+
+// RankQuery filters the tickers ranked by their latest rating. Empty
+// fields do not filter.
+type RankQuery struct {
+	Sector      string `json:"sector"`      // Any name NormalizeSector accepts
+	Pattern     string `json:"pattern"`     // Pattern name, ignoring case
+	WatchlistID string `json:"watchlistId"` // Only tickers on this watchlist
+	Limit       int    `json:"limit"`       // 0 for all
+}
+
+// RankedTicker is a ticker ranked by the enthusiasm of its latest rating
+type RankedTicker struct {
+	Rank   int          `json:"rank"` // From 1
+	Ticker string       `json:"ticker"`
+	Rating *StockRating `json:"rating"`
+	// Item is the ticker's watchlist entry when ranking a watchlist
+	Item *WatchlistItem `json:"item"`
+}
@@ -0,0 +1,173 @@
+// Package pnl rolls trade positions up into realized and unrealized P&L
+// per trade, per day and per strategy
+package pnl
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"trading-dashboard/pkg/models"
+)
+
+// Mark is the current price per share of one open contract
+type Mark struct {
+	TradeID    string    `json:"tradeId"`
+	OptionType string    `json:"optionType"`
+	Strike     float64   `json:"strike"`
+	Expiration time.Time `json:"expiration"`
+	Price      float64   `json:"price"`
+}
+
+// TradePnl is the P&L of one trade
+type TradePnl struct {
+	TradeID      string  `json:"tradeId"`
+	Ticker       string  `json:"ticker"`
+	StrategyType string  `json:"strategyType"`
+	Status       string  `json:"status"`
+	Multiplier   int     `json:"multiplier"`
+	Realized     float64 `json:"realized"` // Net of commissions and fees
+	Costs        float64 `json:"costs"`
+	Unrealized   float64 `json:"unrealized"`
+	// Marked is false when an open leg had no mark; Unrealized is then 0
+	Marked bool    `json:"marked"`
+	Total  float64 `json:"total"`
+}
+
+// DailyPnl is the P&L realized on one calendar day
+type DailyPnl struct {
+	Date     string  `json:"date"` // YYYY-MM-DD
+	Gross    float64 `json:"gross"`
+	Costs    float64 `json:"costs"`
+	Realized float64 `json:"realized"`
+	Trades   int     `json:"trades"` // Trades with a realization that day
+}
+
+// StrategyPnl is the P&L of every trade of one strategy type
+type StrategyPnl struct {
+	StrategyType string  `json:"strategyType"`
+	Trades       int     `json:"trades"`
+	Closed       int     `json:"closed"`
+	Realized     float64 `json:"realized"`
+	Costs        float64 `json:"costs"`
+	Unrealized   float64 `json:"unrealized"`
+	Total        float64 `json:"total"`
+}
+
+// Report is the P&L of a set of trades
+type Report struct {
+	Trades          []TradePnl    `json:"trades"`
+	Daily           []DailyPnl    `json:"daily"`      // Oldest first
+	ByStrategy      []StrategyPnl `json:"byStrategy"` // By strategy type
+	TotalRealized   float64       `json:"totalRealized"`
+	TotalCosts      float64       `json:"totalCosts"`
+	TotalUnrealized float64       `json:"totalUnrealized"`
+	// Unmarked lists open trades left out of TotalUnrealized for lack of marks
+	Unmarked []string `json:"unmarked"`
+}
+
+// ForTrade computes a trade's P&L from its position. marks are matched
+// to open legs by contract; marks for other trades are ignored.
+func ForTrade(trade *models.Trade, pos *models.Position, marks []Mark) TradePnl {
+	result := TradePnl{
+		TradeID:      trade.ID,
+		Ticker:       trade.Ticker,
+		StrategyType: trade.StrategyType,
+		Status:       pos.Status,
+		Multiplier:   pos.Multiplier,
+		Realized:     pos.RealizedPnl,
+		Costs:        pos.Costs,
+	}
+	result.Unrealized, result.Marked = pos.UnrealizedPnl(func(leg models.Leg) (float64, bool) {
+		for _, m := range marks {
+			if m.TradeID != "" && m.TradeID != trade.ID {
+				continue
+			}
+			if models.SameContract(leg, models.Leg{OptionType: m.OptionType, Strike: m.Strike, Expiration: m.Expiration}) {
+				return m.Price, true
+			}
+		}
+		return 0, false
+	})
+	result.Total = roundCents(result.Realized + result.Unrealized)
+	return result
+}
+
+// Build computes the P&L report for trades. Trades without a position
+// (see TradeEventRepository.GetAllPositions) are skipped. Daily P&L is
+// bucketed by the trading day (models.TradingDay) of each realizing event.
+func Build(trades []*models.Trade, positions map[string]*models.Position, marks []Mark) *Report {
+	report := &Report{Trades: []TradePnl{}, Daily: []DailyPnl{}, ByStrategy: []StrategyPnl{}, Unmarked: []string{}}
+	daily := make(map[string]*DailyPnl)
+	strategies := make(map[string]*StrategyPnl)
+
+	for _, trade := range trades {
+		pos, ok := positions[trade.ID]
+		if !ok {
+			continue
+		}
+		tp := ForTrade(trade, pos, marks)
+		report.Trades = append(report.Trades, tp)
+
+		report.TotalRealized += tp.Realized
+		report.TotalCosts += tp.Costs
+		report.TotalUnrealized += tp.Unrealized
+		if !tp.Marked {
+			report.Unmarked = append(report.Unmarked, trade.ID)
+		}
+
+		s := strategies[trade.StrategyType]
+		if s == nil {
+			s = &StrategyPnl{StrategyType: trade.StrategyType}
+			strategies[trade.StrategyType] = s
+		}
+		s.Trades++
+		if tp.Status == models.PositionClosed {
+			s.Closed++
+		}
+		s.Realized += tp.Realized
+		s.Costs += tp.Costs
+		s.Unrealized += tp.Unrealized
+
+		seen := make(map[string]bool)
+		for _, r := range pos.Realizations {
+			day := models.TradingDay(r.Date)
+			d := daily[day]
+			if d == nil {
+				d = &DailyPnl{Date: day}
+				daily[day] = d
+			}
+			d.Gross += r.Gross
+			d.Costs += r.Costs
+			d.Realized += r.Net
+			if !seen[day] {
+				d.Trades++
+				seen[day] = true
+			}
+		}
+	}
+
+	for _, d := range daily {
+		d.Gross, d.Costs, d.Realized = roundCents(d.Gross), roundCents(d.Costs), roundCents(d.Realized)
+		report.Daily = append(report.Daily, *d)
+	}
+	sort.Slice(report.Daily, func(i, j int) bool { return report.Daily[i].Date < report.Daily[j].Date })
+
+	for _, s := range strategies {
+		s.Realized, s.Costs, s.Unrealized = roundCents(s.Realized), roundCents(s.Costs), roundCents(s.Unrealized)
+		s.Total = roundCents(s.Realized + s.Unrealized)
+		report.ByStrategy = append(report.ByStrategy, *s)
+	}
+	sort.Slice(report.ByStrategy, func(i, j int) bool {
+		return report.ByStrategy[i].StrategyType < report.ByStrategy[j].StrategyType
+	})
+
+	report.TotalRealized = roundCents(report.TotalRealized)
+	report.TotalCosts = roundCents(report.TotalCosts)
+	report.TotalUnrealized = roundCents(report.TotalUnrealized)
+	return report
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,141 @@
+package pnl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/models"
+)
+
+var (
+	eastern = time.FixedZone("EDT", -4*60*60)
+	expiry  = time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	// An evening fill in New York, which belongs to the next UTC trading day
+	openedAt = time.Date(2025, 4, 25, 21, 0, 0, 0, eastern)
+	closedAt = time.Date(2025, 4, 28, 15, 0, 0, 0, time.UTC)
+)
+
+func fill(optionType, side string, strike float64, qty int, price float64) models.Leg {
+	return models.Leg{OptionType: optionType, Strike: strike, Expiration: expiry, Quantity: qty, Side: side, FillPrice: price}
+}
+
+// position replays events for trade, failing the test if they are inconsistent
+func position(t *testing.T, trade *models.Trade, events ...*models.TradeEvent) *models.Position {
+	t.Helper()
+	pos, err := models.BuildPosition(trade, events, closedAt)
+	if err != nil {
+		t.Fatalf("BuildPosition(%s): %v", trade.ID, err)
+	}
+	return pos
+}
+
+func TestBuild(t *testing.T) {
+	// A call bought and sold for a profit, paying commission and fees both ways
+	closedCall := &models.Trade{ID: "trade_1", Ticker: "AAPL", StrategyType: "Long Call", EntryDate: openedAt}
+	// A put sold on a 10-share contract and marked below its fill
+	markedPut := &models.Trade{ID: "trade_2", Ticker: "MSFT", StrategyType: "Short Put", EntryDate: openedAt, Multiplier: 10}
+	// An open call with no mark and no costs
+	unmarkedCall := &models.Trade{ID: "trade_3", Ticker: "TSLA", StrategyType: "Long Call", EntryDate: closedAt}
+	// A trade whose events could not be replayed has no position
+	noPosition := &models.Trade{ID: "trade_4", Ticker: "NVDA", StrategyType: "Long Call", EntryDate: openedAt}
+
+	positions := map[string]*models.Position{
+		closedCall.ID: position(t, closedCall,
+			&models.TradeEvent{ID: "e1", Type: models.EventOpen, Date: openedAt, Commission: 1.30, Fees: 0.04,
+				Fills: []models.Leg{fill(models.OptionCall, models.SideLong, 100, 2, 1.234)}},
+			&models.TradeEvent{ID: "e2", Type: models.EventClose, Date: closedAt, Commission: 1.30, Fees: 0.04,
+				Fills: []models.Leg{fill(models.OptionCall, models.SideShort, 100, 2, 1.5)}}),
+		markedPut.ID: position(t, markedPut,
+			&models.TradeEvent{ID: "e3", Type: models.EventOpen, Date: openedAt, Commission: 0.5,
+				Fills: []models.Leg{fill(models.OptionPut, models.SideShort, 50, 3, 0.333)}}),
+		unmarkedCall.ID: position(t, unmarkedCall,
+			&models.TradeEvent{ID: "e4", Type: models.EventOpen, Date: closedAt,
+				Fills: []models.Leg{fill(models.OptionCall, models.SideLong, 250, 1, 2)}}),
+	}
+	marks := []Mark{{TradeID: markedPut.ID, OptionType: models.OptionPut, Strike: 50, Expiration: expiry, Price: 0.1}}
+
+	report := Build([]*models.Trade{closedCall, markedPut, unmarkedCall, noPosition}, positions, marks)
+
+	wantTrades := []TradePnl{
+		// (1.50 - 1.234) x 2 x 100 = 53.20 gross, less 2 x 1.34 costs
+		{TradeID: "trade_1", Ticker: "AAPL", StrategyType: "Long Call", Status: models.PositionClosed, Multiplier: 100,
+			Realized: 50.52, Costs: 2.68, Marked: true, Total: 50.52},
+		// Short, so (0.333 - 0.10) x 3 x 10 = 6.99
+		{TradeID: "trade_2", Ticker: "MSFT", StrategyType: "Short Put", Status: models.PositionOpen, Multiplier: 10,
+			Realized: -0.5, Costs: 0.5, Unrealized: 6.99, Marked: true, Total: 6.49},
+		{TradeID: "trade_3", Ticker: "TSLA", StrategyType: "Long Call", Status: models.PositionOpen, Multiplier: 100},
+	}
+	if !reflect.DeepEqual(report.Trades, wantTrades) {
+		t.Errorf("trades =\n%+v\nwant\n%+v", report.Trades, wantTrades)
+	}
+
+	totals := []struct {
+		name      string
+		got, want float64
+	}{
+		{"TotalRealized", report.TotalRealized, 50.02},
+		{"TotalCosts", report.TotalCosts, 3.18},
+		{"TotalUnrealized", report.TotalUnrealized, 6.99},
+	}
+	for _, tt := range totals {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if want := []string{"trade_3"}; !reflect.DeepEqual(report.Unmarked, want) {
+		t.Errorf("Unmarked = %v, want %v", report.Unmarked, want)
+	}
+
+	// Opening costs count against the UTC day of the evening fill
+	wantDaily := []DailyPnl{
+		{Date: "2025-04-26", Costs: 1.84, Realized: -1.84, Trades: 2},
+		{Date: "2025-04-28", Gross: 53.2, Costs: 1.34, Realized: 51.86, Trades: 1},
+	}
+	if !reflect.DeepEqual(report.Daily, wantDaily) {
+		t.Errorf("daily = %+v, want %+v", report.Daily, wantDaily)
+	}
+
+	wantStrategies := []StrategyPnl{
+		{StrategyType: "Long Call", Trades: 2, Closed: 1, Realized: 50.52, Costs: 2.68, Total: 50.52},
+		{StrategyType: "Short Put", Trades: 1, Realized: -0.5, Costs: 0.5, Unrealized: 6.99, Total: 6.49},
+	}
+	if !reflect.DeepEqual(report.ByStrategy, wantStrategies) {
+		t.Errorf("by strategy = %+v, want %+v", report.ByStrategy, wantStrategies)
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	report := Build(nil, nil, nil)
+	if report.Trades == nil || report.Daily == nil || report.ByStrategy == nil || report.Unmarked == nil {
+		t.Errorf("empty report has nil slices: %+v", report)
+	}
+}
+
+func TestForTradeMarks(t *testing.T) {
+	trade := &models.Trade{ID: "trade_1", Ticker: "AAPL", EntryDate: openedAt}
+	pos := position(t, trade, &models.TradeEvent{ID: "e1", Type: models.EventOpen, Date: openedAt,
+		Fills: []models.Leg{fill(models.OptionCall, models.SideLong, 100, 1, 1)}})
+
+	tests := []struct {
+		name       string
+		mark       Mark
+		unrealized float64
+		marked     bool
+	}{
+		{"mark for the trade", Mark{TradeID: "trade_1", OptionType: models.OptionCall, Strike: 100, Expiration: expiry, Price: 1.255}, 25.5, true},
+		{"mark for any trade", Mark{OptionType: models.OptionCall, Strike: 100, Expiration: expiry, Price: 0.5}, -50, true},
+		{"expiry given in another zone", Mark{OptionType: models.OptionCall, Strike: 100,
+			Expiration: expiry.Add(12 * time.Hour).In(eastern), Price: 2}, 100, true},
+		{"mark for another trade", Mark{TradeID: "trade_2", OptionType: models.OptionCall, Strike: 100, Expiration: expiry, Price: 2}, 0, false},
+		{"other strike", Mark{OptionType: models.OptionCall, Strike: 105, Expiration: expiry, Price: 2}, 0, false},
+		{"other type", Mark{OptionType: models.OptionPut, Strike: 100, Expiration: expiry, Price: 2}, 0, false},
+	}
+	for _, tt := range tests {
+		got := ForTrade(trade, pos, []Mark{tt.mark})
+		if got.Unrealized != tt.unrealized || got.Marked != tt.marked || got.Total != tt.unrealized {
+			t.Errorf("%s: unrealized %v (marked %v, total %v), want %v (marked %v)",
+				tt.name, got.Unrealized, got.Marked, got.Total, tt.unrealized, tt.marked)
+		}
+	}
+}
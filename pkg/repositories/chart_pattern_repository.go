@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/validation"
+)
+
+const PATTERN_PREFIX = "pattern_"
+
+// patternNameIndex files chart patterns by lower-cased name
+const patternNameIndex = "name"
+
+// ChartPatternRepository stores the chart pattern library
+type ChartPatternRepository struct {
+	patterns *database.Collection[models.ChartPattern]
+}
+
+// NewChartPatternRepository creates a chart pattern repository on top of store
+func NewChartPatternRepository(store database.Store) *ChartPatternRepository {
+	return &ChartPatternRepository{patterns: newChartPatterns(store)}
+}
+
+// newChartPatterns returns the pattern collection with its name index
+func newChartPatterns(store database.Store) *database.Collection[models.ChartPattern] {
+	return database.NewCollection[models.ChartPattern](store, PATTERN_PREFIX).
+		AddIndex(patternNameIndex, func(cp *models.ChartPattern) []string {
+			return []string{patternNameTerm(cp.Name)}
+		})
+}
+
+func patternNameTerm(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// SaveChartPattern creates a pattern, or updates it if its ID is set.
+// Names must be unique, ignoring case.
+func (r *ChartPatternRepository) SaveChartPattern(pattern *models.ChartPattern) error {
+	pattern.Name = strings.TrimSpace(pattern.Name)
+	if err := pattern.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := r.GetChartPatternByName(pattern.Name)
+	switch {
+	case err == nil && existing.ID != pattern.ID:
+		return apperror.Newf(apperror.Conflict, "a chart pattern named %q already exists", existing.Name)
+	case err != nil && !errors.Is(err, database.ErrNotFound):
+		return err
+	}
+
+	if pattern.ID == "" {
+		pattern.ID = database.NewID(PATTERN_PREFIX)
+	} else if _, err := r.GetChartPattern(pattern.ID); err != nil {
+		return err
+	}
+	pattern.UpdatedAt = time.Now()
+	return r.patterns.Put(pattern.ID, pattern)
+}
+
+// GetChartPattern retrieves a pattern by ID
+func (r *ChartPatternRepository) GetChartPattern(id string) (*models.ChartPattern, error) {
+	pattern, err := r.patterns.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+// GetChartPatternByName retrieves a pattern by name, ignoring case, or
+// an error wrapping database.ErrNotFound
+func (r *ChartPatternRepository) GetChartPatternByName(name string) (*models.ChartPattern, error) {
+	found, err := r.patterns.Lookup(patternNameIndex, patternNameTerm(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart pattern %q: %w", name, err)
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no chart pattern %q: %w", name, database.ErrNotFound)
+	}
+	return found[0], nil
+}
+
+// GetAllChartPatterns retrieves every pattern, including inactive ones
+func (r *ChartPatternRepository) GetAllChartPatterns() ([]*models.ChartPattern, error) {
+	patterns, err := r.patterns.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart patterns: %w", err)
+	}
+	return patterns, nil
+}
+
+// DeleteChartPattern deletes a pattern. Ratings scored with it keep its
+// name and points.
+func (r *ChartPatternRepository) DeleteChartPattern(id string) error {
+	return r.patterns.Delete(id)
+}
+
+// ScoreRating sets the rating's pattern name, points and bias from the
+// library. The rating names its pattern by PatternID or, failing that,
+// by Pattern. previous is the rating as stored before this save (nil
+// for a new rating); if it was scored with the same pattern it keeps
+// the points it was scored with.
+func (r *ChartPatternRepository) ScoreRating(rating, previous *models.StockRating) error {
+	if rating.PatternID == "" && strings.TrimSpace(rating.Pattern) == "" {
+		rating.Pattern, rating.PatternPoints, rating.PatternBias = "", 0, ""
+		return nil
+	}
+	if previous != nil && previous.PatternID != "" &&
+		(rating.PatternID == previous.PatternID || rating.PatternID == "" && rating.Pattern == previous.Pattern) {
+		rating.PatternID, rating.Pattern = previous.PatternID, previous.Pattern
+		rating.PatternPoints, rating.PatternBias = previous.PatternPoints, previous.PatternBias
+		return nil
+	}
+
+	var pattern *models.ChartPattern
+	var err error
+	name := rating.Pattern
+	if rating.PatternID != "" {
+		name = rating.PatternID
+		pattern, err = r.patterns.Get(rating.PatternID)
+	} else {
+		pattern, err = r.GetChartPatternByName(rating.Pattern)
+	}
+	var v validation.Validator
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		v.Add("pattern", validation.CodeInvalid, fmt.Sprintf("unknown chart pattern %q", name))
+		return v.Err()
+	case err != nil:
+		return err
+	case !pattern.Active:
+		v.Add("pattern", validation.CodeInvalid, fmt.Sprintf("chart pattern %q is inactive", pattern.Name))
+		return v.Err()
+	}
+
+	rating.PatternID, rating.Pattern = pattern.ID, pattern.Name
+	rating.PatternPoints, rating.PatternBias = pattern.Points, pattern.Bias
+	return nil
+}
+
+// seedChartPatterns fills an empty pattern library with the default
+// patterns and scores existing ratings with the points their pattern
+// had before the library existed
+func seedChartPatterns(store database.Store) error {
+	patterns := newChartPatterns(store)
+	existing, err := patterns.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan chart patterns: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	byName := make(map[string]*models.ChartPattern)
+	for _, pattern := range models.DefaultChartPatterns() {
+		pattern.ID = database.NewID(PATTERN_PREFIX)
+		pattern.UpdatedAt = time.Now()
+		if err := patterns.Put(pattern.ID, pattern); err != nil {
+			return err
+		}
+		byName[pattern.Name] = pattern
+	}
+
+	ratings := newStockRatings(store)
+	all, err := ratings.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan stock ratings: %w", err)
+	}
+	for _, rating := range all {
+		pattern, ok := byName[rating.Pattern]
+		if !ok || rating.PatternID != "" {
+			continue
+		}
+		rating.PatternID, rating.PatternPoints = pattern.ID, pattern.Points
+		if err := ratings.Put(rating.ID, rating); err != nil {
+			return fmt.Errorf("failed to score %s: %w", rating.ID, err)
+		}
+	}
+	return nil
+}
+
+// tagRatingPatternBias records on each rating the bias of the pattern it
+// was scored with, from the library
+func tagRatingPatternBias(store database.Store) error {
+	patterns := newChartPatterns(store)
+	ratings := newStockRatings(store)
+	all, err := ratings.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan stock ratings: %w", err)
+	}
+	for _, rating := range all {
+		if rating.PatternID == "" || rating.PatternBias != "" {
+			continue
+		}
+		pattern, err := patterns.Get(rating.PatternID)
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		rating.PatternBias = pattern.Bias
+		if err := ratings.Put(rating.ID, rating); err != nil {
+			return fmt.Errorf("failed to tag %s: %w", rating.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/limits"
+	"trading-dashboard/pkg/models"
+)
+
+// Circuit breaker storage prefixes. Locks are keyed by rule and period
+// (lock_daily_loss_2026-10-18) so each limit trips at most once per
+// period, unless its lock is overridden and the loss keeps growing; the
+// later locks get a trip number (lock_daily_loss_2026-10-18_2).
+const (
+	TRADING_LOCK_PREFIX  = "lock_"
+	LOCK_OVERRIDE_PREFIX = "override_"
+)
+
+// lossLimitRulesKey holds the loss limit rules
+const lossLimitRulesKey = "meta_loss_limit_rules"
+
+// Errors returned when a new trade may not be opened
+var (
+	ErrTradingLocked  = apperror.New(apperror.Locked, "trading is locked")
+	ErrCheckInMissing = apperror.New(apperror.Locked, "today's risk assessment has not been completed")
+)
+
+// TradingStatus is whether new trades may be opened and why not
+type TradingStatus struct {
+	Locked        bool                  `json:"locked"`
+	Locks         []*models.TradingLock `json:"locks"` // Active locks
+	Until         time.Time             `json:"until"` // When the last active lock ends
+	RealizedToday float64               `json:"realizedToday"`
+	RealizedWeek  float64               `json:"realizedWeek"`
+	// CheckInRequired is set when the rules require today's risk
+	// assessment before trading and it is missing
+	CheckInRequired bool `json:"checkInRequired"`
+}
+
+// CircuitBreakerRepository locks trading when loss limits are breached
+// or today's risk score is too low, and records overrides
+type CircuitBreakerRepository struct {
+	store     database.Store
+	risks     *RiskRepository
+	events    *TradeEventRepository
+	locks     *database.Collection[models.TradingLock]
+	overrides *database.Collection[models.LockOverride]
+}
+
+// NewCircuitBreakerRepository creates a circuit breaker on top of store
+func NewCircuitBreakerRepository(store database.Store, risks *RiskRepository, events *TradeEventRepository) *CircuitBreakerRepository {
+	return &CircuitBreakerRepository{
+		store:     store,
+		risks:     risks,
+		events:    events,
+		locks:     database.NewCollection[models.TradingLock](store, TRADING_LOCK_PREFIX),
+		overrides: database.NewCollection[models.LockOverride](store, LOCK_OVERRIDE_PREFIX),
+	}
+}
+
+// GetLossLimitRules returns the saved rules, or the defaults
+func (r *CircuitBreakerRepository) GetLossLimitRules() (*limits.Rules, error) {
+	rules := limits.DefaultRules()
+	err := database.Get(r.store, lossLimitRulesKey, rules)
+	if errors.Is(err, database.ErrNotFound) {
+		return limits.DefaultRules(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loss limit rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SaveLossLimitRules validates and saves the rules. Locks that already
+// tripped keep the end time they were given.
+func (r *CircuitBreakerRepository) SaveLossLimitRules(rules *limits.Rules) error {
+	if err := rules.Validate(); err != nil {
+		return apperror.Wrap(apperror.Invalid, err)
+	}
+	return database.Set(r.store, lossLimitRulesKey, rules)
+}
+
+// GetTradingStatus returns the active locks. It saves nothing: breaches
+// that have not tripped a lock yet are reported as the locks they will
+// trip on the next trade or event that opens contracts.
+func (r *CircuitBreakerRepository) GetTradingStatus() (*TradingStatus, error) {
+	return r.status(time.Now(), false)
+}
+
+// CheckCanOpen trips a lock for each new breach, then returns
+// ErrTradingLocked (with the lock reasons) or ErrCheckInMissing if new
+// trades may not be opened
+func (r *CircuitBreakerRepository) CheckCanOpen() error {
+	status, err := r.status(time.Now(), true)
+	if err != nil {
+		return err
+	}
+	if status.CheckInRequired {
+		return fmt.Errorf("%w; complete it before opening trades", ErrCheckInMissing)
+	}
+	if !status.Locked {
+		return nil
+	}
+	reasons := make([]string, len(status.Locks))
+	for i, lock := range status.Locks {
+		reasons[i] = lock.Reason
+	}
+	return fmt.Errorf("%w until %s: %s", ErrTradingLocked, status.Until.Local().Format("2006-01-02 15:04"), strings.Join(reasons, "; "))
+}
+
+// OverrideLocks lifts every active lock. The written reason is required
+// and the override is recorded.
+func (r *CircuitBreakerRepository) OverrideLocks(reason string) (*models.LockOverride, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperror.Newf(apperror.Invalid, "a written reason is required to override a trading lock")
+	}
+
+	// Trip pending breaches so the override covers every lock the
+	// status reported
+	now := time.Now()
+	if _, err := r.status(now, true); err != nil {
+		return nil, err
+	}
+	active, err := r.activeLocks(now)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) == 0 {
+		return nil, apperror.Newf(apperror.Conflict, "trading is not locked")
+	}
+	_, positions, err := r.events.GetAllPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	override := &models.LockOverride{ID: database.NewID(LOCK_OVERRIDE_PREFIX), Date: now, Reason: reason}
+	for _, lock := range active {
+		override.LockIDs = append(override.LockIDs, lock.ID)
+	}
+	if err := r.overrides.Put(override.ID, override); err != nil {
+		return nil, err
+	}
+	for _, lock := range active {
+		period := limits.PeriodOf(lock.Rule, now)
+		lock.OverrideID = override.ID
+		lock.LossAtOverride = round2(-limits.RealizedBetween(positions, period.Start, period.End))
+		if err := r.locks.Put(lock.ID, lock); err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("WARNING: Trading lock overridden (%d locks): %s", len(active), reason)
+	return override, nil
+}
+
+// GetLockOverrides returns every recorded override, oldest first
+func (r *CircuitBreakerRepository) GetLockOverrides() ([]*models.LockOverride, error) {
+	overrides, err := r.overrides.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// status evaluates the limits at now. Each breach that trips a new lock
+// is reported with the active locks, and saved if save is set.
+func (r *CircuitBreakerRepository) status(now time.Time, save bool) (*TradingStatus, error) {
+	rules, err := r.GetLossLimitRules()
+	if err != nil {
+		return nil, err
+	}
+	_, positions, err := r.events.GetAllPositions()
+	if err != nil {
+		return nil, err
+	}
+	checkIn, err := r.risks.GetCheckInStatus(now)
+	if err != nil {
+		return nil, err
+	}
+
+	day, week := limits.Day(now), limits.Week(now)
+	status := &TradingStatus{
+		RealizedToday:   round2(limits.RealizedBetween(positions, day.Start, day.End)),
+		RealizedWeek:    round2(limits.RealizedBetween(positions, week.Start, week.End)),
+		CheckInRequired: rules.RequireCheckIn && checkIn.Missing,
+	}
+	status.Locks, err = r.activeLocks(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, breach := range limits.Evaluate(rules, now, positions, checkIn.Assessment) {
+		lock, err := r.newLock(rules, breach, now)
+		if err != nil {
+			return nil, err
+		}
+		if lock == nil {
+			continue
+		}
+		if save {
+			log.Printf("WARNING: Trading locked: %s", lock.Reason)
+			if err := r.locks.Put(lock.ID, lock); err != nil {
+				return nil, err
+			}
+		}
+		status.Locks = append(status.Locks, lock)
+	}
+
+	for _, lock := range status.Locks {
+		status.Locked = true
+		if lock.Until.After(status.Until) {
+			status.Until = lock.Until
+		}
+	}
+	return status, nil
+}
+
+// newLock returns the lock breach trips, or nil if its rule already
+// tripped this period. A rule whose last lock this period was
+// overridden trips again once limits.Retrips says so.
+func (r *CircuitBreakerRepository) newLock(rules *limits.Rules, breach limits.Breach, now time.Time) (*models.TradingLock, error) {
+	id := TRADING_LOCK_PREFIX + breach.Rule + "_" + breach.Period.Key
+	prior, err := r.locks.ListPrefix(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading locks: %w", err)
+	}
+
+	reason := breach.Reason
+	if len(prior) > 0 {
+		last := prior[0]
+		for _, lock := range prior[1:] {
+			if lock.TrippedAt.After(last.TrippedAt) {
+				last = lock
+			}
+		}
+		if last.OverrideID == "" || !limits.Retrips(rules, breach, last.LossAtOverride) {
+			return nil, nil
+		}
+		id = fmt.Sprintf("%s_%d", id, len(prior)+1)
+		reason = fmt.Sprintf("%s ($%.2f more since the override)", reason, breach.Loss-last.LossAtOverride)
+	}
+
+	return &models.TradingLock{
+		ID:        id,
+		Rule:      breach.Rule,
+		Period:    breach.Period.Key,
+		Reason:    reason,
+		TrippedAt: now,
+		Until:     limits.LockUntil(rules, breach.Period, now),
+		Loss:      round2(breach.Loss),
+	}, nil
+}
+
+func (r *CircuitBreakerRepository) activeLocks(now time.Time) ([]*models.TradingLock, error) {
+	all, err := r.locks.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading locks: %w", err)
+	}
+	active := []*models.TradingLock{}
+	for _, lock := range all {
+		if lock.IsActive(now) {
+			active = append(active, lock)
+		}
+	}
+	return active, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/limits"
+	"trading-dashboard/pkg/models"
+)
+
+type breakerFixture struct {
+	breaker *CircuitBreakerRepository
+	trades  *TradeRepository
+	events  *TradeEventRepository
+}
+
+func newBreakerFixture(t *testing.T, rules *limits.Rules) *breakerFixture {
+	t.Helper()
+	store := database.NewMemoryStore()
+	trades := NewTradeRepository(store, nil, nil, nil)
+	events := NewTradeEventRepository(store, trades)
+	breaker := NewCircuitBreakerRepository(store, NewRiskRepository(store, NewRiskScoringRepository(store)), events)
+	if err := breaker.SaveLossLimitRules(rules); err != nil {
+		t.Fatalf("SaveLossLimitRules: %v", err)
+	}
+	return &breakerFixture{breaker, trades, events}
+}
+
+// lose opens and closes one call now, realizing a loss of open-close per share
+func (f *breakerFixture) lose(t *testing.T, open, close float64) {
+	t.Helper()
+	now := time.Now()
+	trade := &models.Trade{Ticker: "AAPL", EntryDate: now}
+	if err := f.trades.SaveTrade(trade); err != nil {
+		t.Fatalf("SaveTrade: %v", err)
+	}
+	leg := models.Leg{OptionType: models.OptionCall, Strike: 200, Expiration: now.AddDate(0, 1, 0), Quantity: 1, Side: models.SideLong, FillPrice: open}
+	if err := f.events.RecordTradeEvent(&models.TradeEvent{TradeID: trade.ID, Type: models.EventOpen, Date: now, Fills: []models.Leg{leg}}); err != nil {
+		t.Fatalf("record open: %v", err)
+	}
+	leg.Side, leg.FillPrice = models.SideShort, close
+	if err := f.events.RecordTradeEvent(&models.TradeEvent{TradeID: trade.ID, Type: models.EventClose, Date: now, Fills: []models.Leg{leg}}); err != nil {
+		t.Fatalf("record close: %v", err)
+	}
+}
+
+func (f *breakerFixture) savedLocks(t *testing.T) []*models.TradingLock {
+	t.Helper()
+	locks, err := f.breaker.locks.List()
+	if err != nil {
+		t.Fatalf("list locks: %v", err)
+	}
+	return locks
+}
+
+func TestGetTradingStatusSavesNothing(t *testing.T) {
+	f := newBreakerFixture(t, &limits.Rules{DailyLossLimit: 100})
+	f.lose(t, 2, 0.5)
+
+	for i := 0; i < 2; i++ {
+		status, err := f.breaker.GetTradingStatus()
+		if err != nil {
+			t.Fatalf("GetTradingStatus: %v", err)
+		}
+		if !status.Locked || len(status.Locks) != 1 || status.RealizedToday != -150 {
+			t.Errorf("status = %+v, want locked by one pending lock after losing $150", status)
+		}
+	}
+	if locks := f.savedLocks(t); len(locks) != 0 {
+		t.Errorf("GetTradingStatus saved %d locks", len(locks))
+	}
+
+	if err := f.breaker.CheckCanOpen(); !errors.Is(err, ErrTradingLocked) {
+		t.Fatalf("CheckCanOpen = %v, want ErrTradingLocked", err)
+	}
+	if err := f.breaker.CheckCanOpen(); !errors.Is(err, ErrTradingLocked) {
+		t.Fatalf("second CheckCanOpen = %v, want ErrTradingLocked", err)
+	}
+	locks := f.savedLocks(t)
+	if len(locks) != 1 || locks[0].Rule != limits.RuleDailyLoss || locks[0].Loss != 150 {
+		t.Errorf("saved locks = %+v, want one daily loss lock at $150", locks)
+	}
+}
+
+func TestOverriddenLockTripsAgain(t *testing.T) {
+	f := newBreakerFixture(t, &limits.Rules{DailyLossLimit: 100})
+	f.lose(t, 2, 0.5)
+
+	// The override trips the pending lock itself before lifting it
+	override, err := f.breaker.OverrideLocks("hedging an open position")
+	if err != nil {
+		t.Fatalf("OverrideLocks: %v", err)
+	}
+	locks := f.savedLocks(t)
+	if len(locks) != 1 || locks[0].OverrideID != override.ID || locks[0].LossAtOverride != 150 {
+		t.Fatalf("locks = %+v, want one overridden at a $150 loss", locks)
+	}
+	if err := f.breaker.CheckCanOpen(); err != nil {
+		t.Fatalf("CheckCanOpen after override = %v", err)
+	}
+
+	// $50 more is still within the limit past the override
+	f.lose(t, 1, 0.5)
+	if err := f.breaker.CheckCanOpen(); err != nil {
+		t.Fatalf("CheckCanOpen $50 past the override = %v", err)
+	}
+
+	// $110 past the override reaches it again
+	f.lose(t, 1, 0.4)
+	if err := f.breaker.CheckCanOpen(); !errors.Is(err, ErrTradingLocked) {
+		t.Fatalf("CheckCanOpen $110 past the override = %v, want ErrTradingLocked", err)
+	}
+	locks = f.savedLocks(t)
+	if len(locks) != 2 {
+		t.Fatalf("saved %d locks, want 2", len(locks))
+	}
+	retripped := locks[1]
+	if !strings.HasSuffix(retripped.ID, "_2") || retripped.OverrideID != "" || !strings.Contains(retripped.Reason, "$110.00 more since the override") {
+		t.Errorf("second lock = %+v, want trip 2 for the $110 since the override", retripped)
+	}
+}
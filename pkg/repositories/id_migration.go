@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/database"
+)
+
+// ID_MAP_PREFIX stores old ID -> new ID for records re-keyed by MigrateLegacyIDs
+const ID_MAP_PREFIX = "idmap_"
+
+// legacyKeyedPrefixes are the collections that used timestamp keys
+// ("trade__2025-04-25T10:00:00-04:00") or ticker and day keys
+// ("stock_AAPL_20250425") before IDs became ULIDs
+var legacyKeyedPrefixes = []string{RISK_PREFIX, TRADE_PREFIX, STOCK_PREFIX}
+
+// newIDMap returns the collection holding the old -> new ID mapping
+func newIDMap(store database.Store) *database.Collection[string] {
+	return database.NewCollection[string](store, ID_MAP_PREFIX)
+}
+
+// MigrateLegacyIDs re-keys risk assessments, trades and stock ratings
+// that were saved with legacy keys. Each record gets an ID carrying its original
+// timestamp so ordering is preserved, and the old ID is remembered so
+// lookups by old ID keep working. It runs as schema migration 1 and is
+// safe to run more than once.
+// The returned map holds the IDs changed by this run.
+func MigrateLegacyIDs(store database.Store) (map[string]string, error) {
+	idMap := newIDMap(store)
+	migrated := make(map[string]string)
+
+	for _, prefix := range legacyKeyedPrefixes {
+		legacy := make(map[string][]byte)
+		err := store.Scan(prefix, func(key string, value []byte) error {
+			if !database.IsID(prefix, key) {
+				legacy[key] = value
+			}
+			return nil
+		})
+		if err != nil {
+			return migrated, fmt.Errorf("failed to scan %s records: %w", prefix, err)
+		}
+
+		for oldID, raw := range legacy {
+			// A mapping means an earlier run copied the record but did not
+			// get to delete the old key
+			if _, err := idMap.Get(ID_MAP_PREFIX + oldID); err == nil {
+				if err := store.Delete(oldID); err != nil {
+					return migrated, err
+				}
+				continue
+			}
+
+			newID := database.NewIDAt(prefix, legacyKeyTime(prefix, oldID))
+			if err := rekey(store, idMap, oldID, newID, raw); err != nil {
+				return migrated, fmt.Errorf("failed to re-key %s: %w", oldID, err)
+			}
+			migrated[oldID] = newID
+			log.Printf("DEBUG: Re-keyed %s -> %s", oldID, newID)
+		}
+	}
+
+	if len(migrated) > 0 {
+		log.Printf("SUCCESS: Re-keyed %d records with legacy IDs", len(migrated))
+	}
+	return migrated, nil
+}
+
+// rekey copies a record to newID (updating its "id" field), records the
+// mapping and removes the old key
+func rekey(store database.Store, idMap *database.Collection[string], oldID, newID string, raw []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	fields["id"], _ = json.Marshal(newID)
+
+	if err := database.Set(store, newID, fields); err != nil {
+		return err
+	}
+	if err := idMap.Put(ID_MAP_PREFIX+oldID, &newID); err != nil {
+		return err
+	}
+	return store.Delete(oldID)
+}
+
+// legacyKeyTime recovers the timestamp from a key made by the old
+// GenerateKey (prefix + "_" + RFC3339) or the day of a stock rating key
+// (prefix + ticker + "_" + YYYYMMDD), falling back to now
+func legacyKeyTime(prefix, key string) time.Time {
+	stamp := strings.TrimPrefix(strings.TrimPrefix(key, prefix), "_")
+	if t, err := time.Parse(time.RFC3339, stamp); err == nil {
+		return t
+	}
+	if i := strings.LastIndex(stamp, "_"); i >= 0 {
+		if t, err := time.Parse("20060102", stamp[i+1:]); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// resolveLegacyID returns the current ID for an ID that was re-keyed by
+// MigrateLegacyIDs, or database.ErrNotFound
+func resolveLegacyID(idMap *database.Collection[string], oldID string) (string, error) {
+	newID, err := idMap.Get(ID_MAP_PREFIX + oldID)
+	if errors.Is(err, database.ErrNotFound) {
+		return "", database.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return *newID, nil
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+func TestMigrateLegacyIDs(t *testing.T) {
+	store := database.NewMemoryStore()
+	eastern := time.FixedZone("EDT", -4*60*60)
+	riskStamp := time.Date(2025, 4, 25, 21, 0, 0, 0, eastern)
+	records := map[string]any{
+		"risk__2025-04-25T21:00:00-04:00": &models.RiskAssessment{ID: "risk__2025-04-25T21:00:00-04:00", Date: riskStamp, Emotional: -1},
+		"trade__2025-04-28T10:00:00-04:00": &models.Trade{ID: "trade__2025-04-28T10:00:00-04:00", Ticker: "AAPL",
+			EntryDate: time.Date(2025, 4, 28, 10, 0, 0, 0, eastern)},
+		"stock_AAPL_20250425": &models.StockRating{ID: "stock_AAPL_20250425", Ticker: "AAPL",
+			Date: time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), StockSentiment: 2},
+	}
+	for key, record := range records {
+		if err := database.Set(store, key, record); err != nil {
+			t.Fatalf("seed %s: %v", key, err)
+		}
+	}
+
+	migrated, err := MigrateLegacyIDs(store)
+	if err != nil {
+		t.Fatalf("MigrateLegacyIDs: %v", err)
+	}
+	tests := []struct {
+		oldID, prefix string
+		stamp         time.Time
+	}{
+		{"risk__2025-04-25T21:00:00-04:00", RISK_PREFIX, riskStamp},
+		{"trade__2025-04-28T10:00:00-04:00", TRADE_PREFIX, time.Date(2025, 4, 28, 10, 0, 0, 0, eastern)},
+		{"stock_AAPL_20250425", STOCK_PREFIX, time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		newID := migrated[tt.oldID]
+		if !database.IsID(tt.prefix, newID) {
+			t.Errorf("%s re-keyed to %q, want a ULID", tt.oldID, newID)
+			continue
+		}
+		if got, _ := database.IDTime(tt.prefix, newID); !got.Equal(tt.stamp) {
+			t.Errorf("%s carries %s, want the old key's %s", newID, got, tt.stamp)
+		}
+		if resolved, err := resolveLegacyID(newIDMap(store), tt.oldID); err != nil || resolved != newID {
+			t.Errorf("resolveLegacyID(%s) = %q, %v; want %s", tt.oldID, resolved, err, newID)
+		}
+		if _, err := store.Get(tt.oldID); err == nil {
+			t.Errorf("old key %s still stored", tt.oldID)
+		}
+		var fields map[string]any
+		if err := database.Get(store, newID, &fields); err != nil || fields["id"] != newID {
+			t.Errorf("record under %s has id %v (%v), want it updated", newID, fields["id"], err)
+		}
+	}
+
+	again, err := MigrateLegacyIDs(store)
+	if err != nil {
+		t.Fatalf("second MigrateLegacyIDs: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("second run re-keyed %v", again)
+	}
+}
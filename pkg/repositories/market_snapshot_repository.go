@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+// SNAPSHOT_PREFIX stores one market snapshot per day as
+// snapshot_<YYYY-MM-DD>, so listing them returns them in date order
+const SNAPSHOT_PREFIX = "snapshot_"
+
+// MarketSnapshotRepository stores the daily market and sector sentiments
+type MarketSnapshotRepository struct {
+	snapshots *database.Collection[models.MarketSnapshot]
+}
+
+// NewMarketSnapshotRepository creates a market snapshot repository on top of store
+func NewMarketSnapshotRepository(store database.Store) *MarketSnapshotRepository {
+	return &MarketSnapshotRepository{
+		snapshots: database.NewCollection[models.MarketSnapshot](store, SNAPSHOT_PREFIX),
+	}
+}
+
+// marketSnapshotID returns the ID of the snapshot for day (YYYY-MM-DD)
+func marketSnapshotID(day string) string {
+	return SNAPSHOT_PREFIX + day
+}
+
+// SaveMarketSnapshot saves the snapshot for its day, replacing the day's
+// previous one. The ID passed in is ignored.
+func (r *MarketSnapshotRepository) SaveMarketSnapshot(snapshot *models.MarketSnapshot) error {
+	if err := snapshot.Validate(); err != nil {
+		return err
+	}
+	if snapshot.Date.IsZero() {
+		snapshot.Date = time.Now()
+	}
+	snapshot.ID = marketSnapshotID(snapshot.Day())
+	snapshot.UpdatedAt = time.Now()
+	return r.snapshots.Put(snapshot.ID, snapshot)
+}
+
+// GetMarketSnapshot retrieves a snapshot by ID
+func (r *MarketSnapshotRepository) GetMarketSnapshot(id string) (*models.MarketSnapshot, error) {
+	snapshot, err := r.snapshots.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// GetMarketSnapshotForDay retrieves the snapshot for a day (YYYY-MM-DD),
+// or an error wrapping database.ErrNotFound
+func (r *MarketSnapshotRepository) GetMarketSnapshotForDay(day string) (*models.MarketSnapshot, error) {
+	snapshot, err := r.snapshots.Get(marketSnapshotID(day))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market snapshot for %s: %w", day, err)
+	}
+	return snapshot, nil
+}
+
+// GetMarketSnapshots retrieves the snapshots from startDay to endDay
+// (YYYY-MM-DD, inclusive; empty for no bound), oldest first
+func (r *MarketSnapshotRepository) GetMarketSnapshots(startDay, endDay string) ([]*models.MarketSnapshot, error) {
+	all, err := r.snapshots.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market snapshots: %w", err)
+	}
+	snapshots := make([]*models.MarketSnapshot, 0, len(all))
+	for _, snapshot := range all {
+		day := snapshot.Day()
+		if (startDay == "" || day >= startDay) && (endDay == "" || day <= endDay) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"trading-dashboard/pkg/database"
+)
+
+// Schema migrations for the data owned by the repositories. They run in
+// version order when the database is opened; add new ones at the end
+// and never renumber or edit a migration that has shipped.
+func init() {
+	database.RegisterMigration(database.Migration{
+		Version: 1,
+		Name:    "re-key timestamp IDs as ULIDs",
+		Up: func(store database.Store) error {
+			_, err := MigrateLegacyIDs(store)
+			return err
+		},
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 2,
+		Name:    "tag risk scores with their scoring version",
+		Up:      tagLegacyRiskScores,
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 3,
+		Name:    "merge risk assessments saved for the same day",
+		Up:      mergeSameDayRiskAssessments,
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 4,
+		Name:    "move market and sector sentiments into daily snapshots",
+		Up:      splitMarketSnapshots,
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 5,
+		Name:    "seed the chart pattern library and keep ratings' pattern points",
+		Up:      seedChartPatterns,
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 6,
+		Name:    "record the pattern bias ratings were scored with",
+		Up:      tagRatingPatternBias,
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 7,
+		Name:    "link trades to the rating and risk assessment current at entry",
+		Up:      linkTradeEntryContexts,
+	})
+	database.RegisterMigration(database.Migration{
+		Version: 8,
+		Name:    "store enthusiasm scores instead of recalculating them on read",
+		Up:      storeEnthusiasmScores,
+	})
+}
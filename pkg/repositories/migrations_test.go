@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+// seedLegacyStore writes records the way the app stored them before any
+// migration ran: timestamp and ticker/day keys, sentiments copied into
+// each rating and days taken in the zone they were entered in
+func seedLegacyStore(t *testing.T, store database.Store) {
+	t.Helper()
+	eastern := time.FixedZone("EDT", -4*60*60)
+	records := map[string]any{
+		// Evening check-ins on the 25th in New York: the 26th in UTC
+		"risk__2025-04-25T21:00:00-04:00": &models.RiskAssessment{ID: "risk__2025-04-25T21:00:00-04:00",
+			Date: time.Date(2025, 4, 25, 21, 0, 0, 0, eastern), Emotional: -1},
+		"risk__2025-04-25T22:00:00-04:00": &models.RiskAssessment{ID: "risk__2025-04-25T22:00:00-04:00",
+			Date: time.Date(2025, 4, 25, 22, 0, 0, 0, eastern), Emotional: 2},
+		// Check-ins late on the 27th and early on the 28th in New York: both the 28th in UTC
+		"risk__2025-04-27T21:00:00-04:00": &models.RiskAssessment{ID: "risk__2025-04-27T21:00:00-04:00",
+			Date: time.Date(2025, 4, 27, 21, 0, 0, 0, eastern), Emotional: 1},
+		"risk__2025-04-28T09:00:00-04:00": &models.RiskAssessment{ID: "risk__2025-04-28T09:00:00-04:00",
+			Date: time.Date(2025, 4, 28, 9, 0, 0, 0, eastern), Emotional: 3},
+		"trade__2025-04-28T10:00:00-04:00": &models.Trade{ID: "trade__2025-04-28T10:00:00-04:00",
+			Ticker: "AAPL", EntryDate: time.Date(2025, 4, 28, 10, 0, 0, 0, eastern)},
+		"stock_AAPL_20250425": &models.StockRating{ID: "stock_AAPL_20250425", Ticker: "AAPL",
+			Date: time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC), StockSentiment: 2, MarketSentiment: 1, Technology: 2},
+	}
+	for key, record := range records {
+		if err := database.Set(store, key, record); err != nil {
+			t.Fatalf("seed %s: %v", key, err)
+		}
+	}
+}
+
+func TestMigrateLegacyStore(t *testing.T) {
+	store := database.NewMemoryStore()
+	seedLegacyStore(t, store)
+
+	report, err := database.Migrate(store, database.MigrateOptions{})
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if report.ToVersion != database.LatestSchemaVersion() {
+		t.Fatalf("migrated to version %d, want %d", report.ToVersion, database.LatestSchemaVersion())
+	}
+
+	risks := NewRiskRepository(store, NewRiskScoringRepository(store))
+	assessments, err := risks.GetAllRiskAssessments()
+	if err != nil {
+		t.Fatalf("GetAllRiskAssessments: %v", err)
+	}
+	if len(assessments) != 2 {
+		t.Fatalf("%d assessments, want each day's check-ins merged into one", len(assessments))
+	}
+	day, err := risks.GetRiskAssessmentForDay("2025-04-26")
+	if err != nil {
+		t.Fatalf("GetRiskAssessmentForDay: %v", err)
+	}
+	if !database.IsID(RISK_PREFIX, day.ID) || day.Emotional != 2 {
+		t.Errorf("assessment = %+v, want the later check-in under a ULID", day)
+	}
+
+	stocks := newTestStockRepository(t, store)
+	rating, err := stocks.GetStockRating("stock_AAPL_20250425")
+	if err != nil {
+		t.Fatalf("GetStockRating by legacy ID: %v", err)
+	}
+	if !database.IsID(STOCK_PREFIX, rating.ID) {
+		t.Errorf("rating ID %q is not a ULID", rating.ID)
+	}
+	if rating.SnapshotID != marketSnapshotID("2025-04-25") || rating.MarketSentiment != 1 {
+		t.Errorf("rating snapshot %q with market %d, want the day's snapshot with market 1", rating.SnapshotID, rating.MarketSentiment)
+	}
+	if rating.EnthusiasmVersion != models.EnthusiasmVersion || len(rating.EnthusiasmBreakdown) == 0 {
+		t.Errorf("enthusiasm version %q with %d components, want it stored", rating.EnthusiasmVersion, len(rating.EnthusiasmBreakdown))
+	}
+
+	trades := NewTradeRepository(store, nil, nil, nil)
+	trade, err := trades.GetTrade("trade__2025-04-28T10:00:00-04:00")
+	if err != nil {
+		t.Fatalf("GetTrade by legacy ID: %v", err)
+	}
+	if !trade.EntryContext.HasRating() || trade.EntryContext.StockRatingID != rating.ID {
+		t.Errorf("trade entry context %+v, want it linked to rating %s", trade.EntryContext, rating.ID)
+	}
+	entryDay, err := risks.GetRiskAssessmentForDay("2025-04-28")
+	if err != nil {
+		t.Fatalf("GetRiskAssessmentForDay: %v", err)
+	}
+	if entryDay.Emotional != 3 || trade.EntryContext.RiskAssessmentID != entryDay.ID {
+		t.Errorf("trade links assessment %q, want the entry day's merged assessment %s", trade.EntryContext.RiskAssessmentID, entryDay.ID)
+	}
+
+	// Migrations only ever run once
+	again, err := database.Migrate(store, database.MigrateOptions{})
+	if err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	if len(again.Applied) != 0 {
+		t.Errorf("second Migrate applied %v", again.Applied)
+	}
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/marketdata"
+)
+
+// BAR_PREFIX stores price bars as bar_<TICKER>/<interval>/<time term>,
+// so a ticker's bars of one interval are stored in time order
+const BAR_PREFIX = "bar_"
+
+// SERIES_PREFIX stores a summary of each ticker and interval as
+// barseries_<TICKER>/<interval>
+const SERIES_PREFIX = "barseries_"
+
+// barBatchSize is how many bars are written per transaction
+const barBatchSize = 1000
+
+// PriceRepository stores OHLCV price bars
+type PriceRepository struct {
+	store  database.Store
+	series *database.Collection[marketdata.Series]
+}
+
+// NewPriceRepository creates a price repository on top of store
+func NewPriceRepository(store database.Store) *PriceRepository {
+	return &PriceRepository{
+		store:  store,
+		series: database.NewCollection[marketdata.Series](store, SERIES_PREFIX),
+	}
+}
+
+// barSeriesKey returns the key prefix of a ticker's bars of one interval
+func barSeriesKey(ticker string, interval marketdata.Interval) string {
+	return BAR_PREFIX + strings.ToUpper(ticker) + "/" + string(interval) + "/"
+}
+
+// SaveBars stores bars, replacing any stored bar of the same ticker,
+// interval and time. Daily and weekly bars are stamped with
+// marketdata.DayStamp. Every bar is checked before any is written. Bars
+// are written in batches; if a batch fails, the series summaries still
+// count the batches written before it.
+func (r *PriceRepository) SaveBars(bars []marketdata.Bar) error {
+	checked := make([]marketdata.Bar, len(bars))
+	for i, bar := range bars {
+		bar.Ticker = strings.ToUpper(strings.TrimSpace(bar.Ticker))
+		if !bar.Interval.Intraday() {
+			bar.Time = marketdata.DayStamp(bar.Time)
+		}
+		if err := bar.Validate(); err != nil {
+			return fmt.Errorf("bar %s %s: %w", bar.Ticker, bar.Time.Format(time.RFC3339), err)
+		}
+		checked[i] = bar
+	}
+
+	touched := make(map[string]marketdata.Series)
+	var saveErr error
+	for start := 0; start < len(checked); start += barBatchSize {
+		batch := checked[start:min(start+barBatchSize, len(checked))]
+		saveErr = r.store.Update(func(tx database.Tx) error {
+			for i := range batch {
+				bar := &batch[i]
+				data, err := json.Marshal(bar)
+				if err != nil {
+					return err
+				}
+				if err := tx.Set(barSeriesKey(bar.Ticker, bar.Interval)+database.TimeTerm(bar.Time), data); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if saveErr != nil {
+			break
+		}
+		for _, bar := range batch {
+			touched[barSeriesKey(bar.Ticker, bar.Interval)] = marketdata.Series{Ticker: bar.Ticker, Interval: bar.Interval}
+		}
+	}
+
+	for _, s := range touched {
+		if err := r.updateSeries(s.Ticker, s.Interval); err != nil && saveErr == nil {
+			saveErr = err
+		}
+	}
+	return saveErr
+}
+
+// updateSeries recounts the stored bars of a ticker and interval
+func (r *PriceRepository) updateSeries(ticker string, interval marketdata.Interval) error {
+	summary := &marketdata.Series{Ticker: ticker, Interval: interval, UpdatedAt: time.Now()}
+	err := r.scanBars(ticker, interval, time.Time{}, time.Time{}, func(bar *marketdata.Bar) error {
+		if summary.Bars == 0 {
+			summary.First = bar.Time
+		}
+		summary.Last = bar.Time
+		summary.Bars++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	id := SERIES_PREFIX + ticker + "/" + string(interval)
+	if summary.Bars == 0 {
+		return r.series.Delete(id)
+	}
+	return r.series.Put(id, summary)
+}
+
+// ImportBarsCSV reads bars from CSV (see marketdata.ParseCSV) and stores
+// them. Nothing is stored if any row is invalid.
+func (r *PriceRepository) ImportBarsCSV(data io.Reader, opts marketdata.ImportOptions) (*marketdata.ImportResult, error) {
+	bars, result, err := marketdata.ParseCSV(data, opts)
+	if err != nil {
+		return nil, apperror.Wrap(apperror.Invalid, err)
+	}
+	if err := r.SaveBars(bars); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scanBars passes the bars of a ticker and interval from start to end
+// (inclusive; zero for no bound) to fn in time order
+func (r *PriceRepository) scanBars(ticker string, interval marketdata.Interval, start, end time.Time, fn func(bar *marketdata.Bar) error) error {
+	prefix := barSeriesKey(ticker, interval)
+	from, to := prefix, prefix[:len(prefix)-1]+"0" // '0' sorts right after '/'
+	if !start.IsZero() {
+		from = prefix + database.TimeTerm(start)
+	}
+	if !end.IsZero() {
+		to = prefix + database.TimeTerm(end.Add(time.Nanosecond))
+	}
+	return r.store.ScanRange(from, to, func(key string, value []byte) error {
+		var bar marketdata.Bar
+		if err := json.Unmarshal(value, &bar); err != nil {
+			return fmt.Errorf("failed to decode bar %s: %w", key, err)
+		}
+		return fn(&bar)
+	})
+}
+
+// GetBars retrieves a ticker's bars of one interval from start to end
+// (inclusive; zero for no bound), oldest first
+func (r *PriceRepository) GetBars(ticker string, interval marketdata.Interval, start, end time.Time) ([]marketdata.Bar, error) {
+	bars := []marketdata.Bar{}
+	err := r.scanBars(ticker, interval, start, end, func(bar *marketdata.Bar) error {
+		bars = append(bars, *bar)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s bars: %w", ticker, interval, err)
+	}
+	return bars, nil
+}
+
+// GetLatestBar retrieves a ticker's last bar of one interval starting at
+// or before asOf, or an error wrapping database.ErrNotFound
+func (r *PriceRepository) GetLatestBar(ticker string, interval marketdata.Interval, asOf time.Time) (*marketdata.Bar, error) {
+	// Scan the ten days before asOf first; fall back to the whole history
+	for _, from := range []time.Time{asOf.AddDate(0, 0, -10), {}} {
+		bars, err := r.GetBars(ticker, interval, from, asOf)
+		if err != nil {
+			return nil, err
+		}
+		if bar, ok := marketdata.Latest(bars, asOf); ok {
+			return &bar, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s %s bar at or before %s: %w", ticker, interval, asOf.Format(time.RFC3339), database.ErrNotFound)
+}
+
+// GetPriceSeries lists the stored tickers and intervals
+func (r *PriceRepository) GetPriceSeries() ([]*marketdata.Series, error) {
+	series, err := r.series.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price series: %w", err)
+	}
+	return series, nil
+}
+
+// DeleteBars deletes a ticker's bars of one interval
+func (r *PriceRepository) DeleteBars(ticker string, interval marketdata.Interval) error {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	var keys []string
+	err := r.store.Scan(barSeriesKey(ticker, interval), func(key string, _ []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(keys); start += barBatchSize {
+		batch := keys[start:min(start+barBatchSize, len(keys))]
+		err := r.store.Update(func(tx database.Tx) error {
+			for _, key := range batch {
+				if err := tx.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return r.updateSeries(ticker, interval)
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/marketdata"
+)
+
+// failingStore fails every Update after the first ok ones
+type failingStore struct {
+	database.Store
+	ok int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (s *failingStore) Update(fn func(tx database.Tx) error) error {
+	if s.ok == 0 {
+		return errWriteFailed
+	}
+	s.ok--
+	return s.Store.Update(fn)
+}
+
+func testBars(ticker string, n int) []marketdata.Bar {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]marketdata.Bar, n)
+	for i := range bars {
+		bars[i] = marketdata.Bar{Ticker: ticker, Interval: marketdata.Daily, Time: start.AddDate(0, 0, i),
+			Open: 10, High: 11, Low: 9, Close: 10.5, Volume: 100}
+	}
+	return bars
+}
+
+func TestSaveBarsChecksEveryBarFirst(t *testing.T) {
+	store := database.NewMemoryStore()
+	prices := NewPriceRepository(store)
+
+	bars := testBars("abc", 2*barBatchSize+10)
+	bars[len(bars)-1].High = 1 // Below the low
+	if err := prices.SaveBars(bars); err == nil {
+		t.Fatal("SaveBars accepted an invalid bar")
+	}
+	stored, err := prices.GetBars("ABC", marketdata.Daily, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("GetBars: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("%d bars stored despite the invalid one", len(stored))
+	}
+	if bars[0].Ticker != "abc" {
+		t.Errorf("caller's bars were modified: ticker %q", bars[0].Ticker)
+	}
+}
+
+func TestSaveBarsCountsCommittedBatches(t *testing.T) {
+	store := &failingStore{Store: database.NewMemoryStore(), ok: 1}
+	prices := NewPriceRepository(store)
+
+	err := prices.SaveBars(testBars("ABC", barBatchSize+10))
+	if !errors.Is(err, errWriteFailed) {
+		t.Fatalf("SaveBars error = %v, want the failed write", err)
+	}
+
+	store.ok = 100 // Let the summary be written
+	if err := prices.updateSeries("ABC", marketdata.Daily); err != nil {
+		t.Fatalf("updateSeries: %v", err)
+	}
+	series, err := prices.GetPriceSeries()
+	if err != nil {
+		t.Fatalf("GetPriceSeries: %v", err)
+	}
+	if len(series) != 1 || series[0].Bars != barBatchSize {
+		t.Fatalf("series = %+v, want one series of %d bars", series, barBatchSize)
+	}
+}
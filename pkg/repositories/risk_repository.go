@@ -1,32 +1,174 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"trading-dashboard/pkg/apperror"
 	"trading-dashboard/pkg/database"
 	"trading-dashboard/pkg/models"
 )
 
 const RISK_PREFIX = "risk_"
 
-// SaveRiskAssessment saves a risk assessment to the database
-func SaveRiskAssessment(assessment *models.RiskAssessment) error {
-	// Calculate the overall score
-	assessment.CalculateOverallScore()
+// RISK_REVISION_PREFIX stores earlier versions of edited assessments as
+// riskrev_<assessment ID>/<revision ULID>
+const RISK_REVISION_PREFIX = "riskrev_"
 
-	// If no ID is set, generate one
-	if assessment.ID == "" {
-		assessment.ID = database.GenerateKey(RISK_PREFIX)
+// riskDayIndex files assessments under their trading day
+const riskDayIndex = "day"
+
+// ErrNoRiskAssessments is returned when no risk assessment has been saved
+var ErrNoRiskAssessments = apperror.New(apperror.NotFound, "no risk assessments found")
+
+// RiskRepository stores daily risk assessments
+type RiskRepository struct {
+	assessments *database.Collection[models.RiskAssessment]
+	revisions   *database.Collection[models.RiskAssessmentRevision]
+	idMap       *database.Collection[string]
+	scoring     *RiskScoringRepository
+}
+
+// NewRiskRepository creates a risk repository on top of store; new
+// assessments are scored with scoring's active model
+func NewRiskRepository(store database.Store, scoring *RiskScoringRepository) *RiskRepository {
+	return &RiskRepository{
+		assessments: newRiskAssessments(store),
+		revisions:   database.NewCollection[models.RiskAssessmentRevision](store, RISK_REVISION_PREFIX),
+		idMap:       newIDMap(store),
+		scoring:     scoring,
 	}
+}
+
+// newRiskAssessments returns the assessment collection with its day index
+func newRiskAssessments(store database.Store) *database.Collection[models.RiskAssessment] {
+	return database.NewCollection[models.RiskAssessment](store, RISK_PREFIX).
+		AddIndex(riskDayIndex, func(ra *models.RiskAssessment) []string {
+			return []string{ra.TradingDay()}
+		})
+}
 
-	// Save the assessment to BadgerDB
-	return database.Set(assessment.ID, assessment)
+// riskRevisionPrefix returns the key prefix of an assessment's revisions
+func riskRevisionPrefix(assessmentID string) string {
+	return RISK_REVISION_PREFIX + assessmentID + "/"
+}
+
+// EnsureIndexes builds the trading day index for assessments saved
+// before it existed
+func (r *RiskRepository) EnsureIndexes() error {
+	return r.assessments.EnsureIndexes()
+}
+
+// SaveRiskAssessment saves the assessment for its trading day. If the
+// day already has one it is updated (keeping its ID) and the previous
+// version is kept as a revision; otherwise a new assessment is created.
+// The ID passed in is ignored, so a form reloaded from another day
+// cannot overwrite that day's check-in.
+func (r *RiskRepository) SaveRiskAssessment(assessment *models.RiskAssessment) error {
+	if err := assessment.Validate(); err != nil {
+		return err
+	}
+	if assessment.Date.IsZero() {
+		assessment.Date = time.Now()
+	}
+
+	// Calculate the overall score with the active scoring model
+	model, err := r.scoring.GetActiveRiskScoringModel()
+	if err != nil {
+		return err
+	}
+	assessment.CalculateOverallScore(model)
+
+	existing, err := r.GetRiskAssessmentForDay(assessment.TradingDay())
+	switch {
+	case err == nil:
+		assessment.ID = existing.ID
+		if err := r.saveRevision(existing, time.Now()); err != nil {
+			return err
+		}
+	case errors.Is(err, database.ErrNotFound):
+		assessment.ID = database.NewID(RISK_PREFIX)
+	default:
+		return err
+	}
+
+	// Save the assessment to the store
+	return r.assessments.Put(assessment.ID, assessment)
+}
+
+// saveRevision keeps previous as a revision of the assessment
+func (r *RiskRepository) saveRevision(previous *models.RiskAssessment, editedAt time.Time) error {
+	revision := &models.RiskAssessmentRevision{
+		ID:           database.NewID(riskRevisionPrefix(previous.ID)),
+		AssessmentID: previous.ID,
+		EditedAt:     editedAt,
+		Previous:     *previous,
+	}
+	return r.revisions.Put(revision.ID, revision)
+}
+
+// GetRiskAssessmentForDay retrieves the assessment for a trading day
+// (YYYY-MM-DD), or an error wrapping database.ErrNotFound
+func (r *RiskRepository) GetRiskAssessmentForDay(day string) (*models.RiskAssessment, error) {
+	found, err := r.assessments.Lookup(riskDayIndex, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk assessment for %s: %w", day, err)
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no risk assessment for %s: %w", day, database.ErrNotFound)
+	}
+	return found[len(found)-1], nil
+}
+
+// GetRiskAssessmentHistory retrieves the earlier versions of an
+// assessment, oldest first
+func (r *RiskRepository) GetRiskAssessmentHistory(id string) ([]*models.RiskAssessmentRevision, error) {
+	assessment, err := r.GetRiskAssessment(id)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := r.revisions.ListPrefix(riskRevisionPrefix(assessment.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk assessment history: %w", err)
+	}
+	return revisions, nil
+}
+
+// CheckInStatus says whether a day's risk assessment has been completed
+type CheckInStatus struct {
+	Date       string                 `json:"date"` // YYYY-MM-DD
+	Missing    bool                   `json:"missing"`
+	Assessment *models.RiskAssessment `json:"assessment"` // nil when missing
+}
+
+// GetCheckInStatus reports whether the assessment for the trading day
+// containing now has been saved
+func (r *RiskRepository) GetCheckInStatus(now time.Time) (*CheckInStatus, error) {
+	day := models.TradingDay(now)
+	status := &CheckInStatus{Date: day}
+	assessment, err := r.GetRiskAssessmentForDay(day)
+	switch {
+	case err == nil:
+		status.Assessment = assessment
+	case errors.Is(err, database.ErrNotFound):
+		status.Missing = true
+	default:
+		return nil, err
+	}
+	return status, nil
 }
 
 // GetRiskAssessment retrieves a risk assessment by ID
-func GetRiskAssessment(id string) (*models.RiskAssessment, error) {
-	assessment := &models.RiskAssessment{}
-	err := database.Get(id, assessment)
+func (r *RiskRepository) GetRiskAssessment(id string) (*models.RiskAssessment, error) {
+	assessment, err := r.assessments.Get(id)
+	if errors.Is(err, database.ErrNotFound) {
+		// Fall back to IDs re-keyed by MigrateLegacyIDs
+		if newID, mapErr := resolveLegacyID(r.idMap, id); mapErr == nil {
+			assessment, err = r.assessments.Get(newID)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get risk assessment: %w", err)
 	}
@@ -34,14 +176,14 @@ func GetRiskAssessment(id string) (*models.RiskAssessment, error) {
 }
 
 // GetLatestRiskAssessment retrieves the latest risk assessment
-func GetLatestRiskAssessment() (*models.RiskAssessment, error) {
-	assessments, err := GetAllRiskAssessments()
+func (r *RiskRepository) GetLatestRiskAssessment() (*models.RiskAssessment, error) {
+	assessments, err := r.GetAllRiskAssessments()
 	if err != nil {
 		return nil, err
 	}
 
 	if len(assessments) == 0 {
-		return nil, fmt.Errorf("no risk assessments found")
+		return nil, ErrNoRiskAssessments
 	}
 
 	// Since we're getting all assessments, sort them by date (newest first)
@@ -60,9 +202,8 @@ func GetLatestRiskAssessment() (*models.RiskAssessment, error) {
 }
 
 // GetAllRiskAssessments retrieves all risk assessments
-func GetAllRiskAssessments() ([]*models.RiskAssessment, error) {
-	var assessments []*models.RiskAssessment
-	err := database.GetByPrefix(RISK_PREFIX, &assessments)
+func (r *RiskRepository) GetAllRiskAssessments() ([]*models.RiskAssessment, error) {
+	assessments, err := r.assessments.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get risk assessments: %w", err)
 	}
@@ -70,6 +211,51 @@ func GetAllRiskAssessments() ([]*models.RiskAssessment, error) {
 }
 
 // DeleteRiskAssessment deletes a risk assessment by ID
-func DeleteRiskAssessment(id string) error {
-	return database.Delete(id)
+func (r *RiskRepository) DeleteRiskAssessment(id string) error {
+	return r.assessments.Delete(id)
+}
+
+// mergeSameDayRiskAssessments keeps one assessment per trading day: the
+// latest saved (by date, then ID) stays and the others become its
+// revisions. It runs as schema migration 3.
+func mergeSameDayRiskAssessments(store database.Store) error {
+	assessments := newRiskAssessments(store)
+	revisions := database.NewCollection[models.RiskAssessmentRevision](store, RISK_REVISION_PREFIX)
+
+	all, err := assessments.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan risk assessments: %w", err)
+	}
+	byDay := make(map[string][]*models.RiskAssessment)
+	for _, ra := range all {
+		byDay[ra.TradingDay()] = append(byDay[ra.TradingDay()], ra)
+	}
+
+	for _, same := range byDay {
+		if len(same) < 2 {
+			continue
+		}
+		sort.SliceStable(same, func(i, j int) bool {
+			if !same[i].Date.Equal(same[j].Date) {
+				return same[i].Date.Before(same[j].Date)
+			}
+			return same[i].ID < same[j].ID
+		})
+		kept := same[len(same)-1]
+		for _, older := range same[:len(same)-1] {
+			revision := &models.RiskAssessmentRevision{
+				ID:           database.NewID(riskRevisionPrefix(kept.ID)),
+				AssessmentID: kept.ID,
+				EditedAt:     older.Date,
+				Previous:     *older,
+			}
+			if err := revisions.Put(revision.ID, revision); err != nil {
+				return err
+			}
+			if err := assessments.Delete(older.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+// RISK_MODEL_PREFIX stores scoring models as riskmodel_<version>
+const RISK_MODEL_PREFIX = "riskmodel_"
+
+// activeRiskModelKey holds the version of the model new assessments use
+const activeRiskModelKey = "meta_risk_scoring_active"
+
+// RiskScoringRepository stores risk scoring models
+type RiskScoringRepository struct {
+	store  database.Store
+	models *database.Collection[models.RiskScoringModel]
+}
+
+// NewRiskScoringRepository creates a scoring model repository on top of store
+func NewRiskScoringRepository(store database.Store) *RiskScoringRepository {
+	return &RiskScoringRepository{
+		store:  store,
+		models: database.NewCollection[models.RiskScoringModel](store, RISK_MODEL_PREFIX),
+	}
+}
+
+// SaveRiskScoringModel saves a model and makes it the active one.
+// Versions are immutable: saving a different model under an existing
+// version is refused, while re-saving an identical one just activates it.
+func (r *RiskScoringRepository) SaveRiskScoringModel(model *models.RiskScoringModel) error {
+	if model.Penalties == nil {
+		model.Penalties = []models.RiskPenalty{}
+	}
+	if err := model.Validate(); err != nil {
+		return apperror.Wrap(apperror.Invalid, err)
+	}
+
+	id := RISK_MODEL_PREFIX + model.Version
+	existing, err := r.models.Get(id)
+	switch {
+	case err == nil:
+		if !sameModel(existing, model) {
+			return apperror.Newf(apperror.Conflict, "scoring model version %q already exists with a different formula; use a new version", model.Version)
+		}
+	case errors.Is(err, database.ErrNotFound):
+		if err := r.models.Put(id, model); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	return database.Set(r.store, activeRiskModelKey, model.Version)
+}
+
+// GetRiskScoringModel retrieves a model by version
+func (r *RiskScoringRepository) GetRiskScoringModel(version string) (*models.RiskScoringModel, error) {
+	model, err := r.models.Get(RISK_MODEL_PREFIX + version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scoring model %q: %w", version, err)
+	}
+	return model, nil
+}
+
+// GetActiveRiskScoringModel returns the model new assessments are scored
+// with; the default model until one is saved
+func (r *RiskScoringRepository) GetActiveRiskScoringModel() (*models.RiskScoringModel, error) {
+	var version string
+	err := database.Get(r.store, activeRiskModelKey, &version)
+	if errors.Is(err, database.ErrNotFound) {
+		return models.DefaultRiskScoringModel(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetRiskScoringModel(version)
+}
+
+// GetAllRiskScoringModels retrieves every saved model
+func (r *RiskScoringRepository) GetAllRiskScoringModels() ([]*models.RiskScoringModel, error) {
+	all, err := r.models.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scoring models: %w", err)
+	}
+	return all, nil
+}
+
+// sameModel compares two models by their JSON form (map keys are sorted)
+func sameModel(a, b *models.RiskScoringModel) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(x, y)
+}
+
+// tagLegacyRiskScores marks assessments saved before scoring models
+// existed with LegacyRiskScoringVersion. Their scores are kept as they
+// were calculated, not recomputed. It runs as schema migration 2.
+func tagLegacyRiskScores(store database.Store) error {
+	assessments := database.NewCollection[models.RiskAssessment](store, RISK_PREFIX)
+	all, err := assessments.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan risk assessments: %w", err)
+	}
+	for _, assessment := range all {
+		if assessment.ScoringVersion != "" {
+			continue
+		}
+		assessment.ScoringVersion = models.LegacyRiskScoringVersion
+		if err := assessments.Put(assessment.ID, assessment); err != nil {
+			return fmt.Errorf("failed to tag %s: %w", assessment.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+// SECTOR_PREFIX stores ticker sectors entered or imported by the user as
+// sector_<TICKER>. They take precedence over the bundled table.
+const SECTOR_PREFIX = "sector_"
+
+// builtInSectorsCSV is the bundled ticker -> GICS sector reference table
+//
+//go:embed data/sectors.csv
+var builtInSectorsCSV string
+
+// SectorRepository maps tickers to sectors
+type SectorRepository struct {
+	sectors *database.Collection[models.TickerSector]
+	builtIn map[string]string
+}
+
+// NewSectorRepository creates a sector repository on top of store
+func NewSectorRepository(store database.Store) *SectorRepository {
+	builtIn := make(map[string]string)
+	entries, err := parseSectorsCSV(strings.NewReader(builtInSectorsCSV))
+	if err != nil {
+		panic(fmt.Sprintf("repositories: bad bundled sector table: %v", err))
+	}
+	for _, entry := range entries {
+		builtIn[entry.Ticker] = entry.Sector
+	}
+	return &SectorRepository{
+		sectors: database.NewCollection[models.TickerSector](store, SECTOR_PREFIX),
+		builtIn: builtIn,
+	}
+}
+
+// GetTickerSector returns a ticker's sector, or an error wrapping
+// database.ErrNotFound if it is not in the table
+func (r *SectorRepository) GetTickerSector(ticker string) (*models.TickerSector, error) {
+	ticker = normalizeTicker(ticker)
+	entry, err := r.sectors.Get(SECTOR_PREFIX + ticker)
+	if err == nil {
+		return entry, nil
+	}
+	if !errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get sector of %s: %w", ticker, err)
+	}
+	if sector, ok := r.builtIn[ticker]; ok {
+		return &models.TickerSector{Ticker: ticker, Sector: sector, BuiltIn: true}, nil
+	}
+	return nil, fmt.Errorf("no sector for %s: %w", ticker, database.ErrNotFound)
+}
+
+// GetAllTickerSectors returns the whole table, user entries replacing
+// bundled ones, sorted by ticker
+func (r *SectorRepository) GetAllTickerSectors() ([]*models.TickerSector, error) {
+	edited, err := r.sectors.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticker sectors: %w", err)
+	}
+	byTicker := make(map[string]*models.TickerSector, len(r.builtIn)+len(edited))
+	for ticker, sector := range r.builtIn {
+		byTicker[ticker] = &models.TickerSector{Ticker: ticker, Sector: sector, BuiltIn: true}
+	}
+	for _, entry := range edited {
+		byTicker[entry.Ticker] = entry
+	}
+
+	all := make([]*models.TickerSector, 0, len(byTicker))
+	for _, entry := range byTicker {
+		all = append(all, entry)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Ticker < all[j].Ticker })
+	return all, nil
+}
+
+// SetTickerSector assigns a ticker to a sector (a sector or GICS sector name)
+func (r *SectorRepository) SetTickerSector(ticker, sector string) (*models.TickerSector, error) {
+	entry, err := newTickerSector(ticker, sector)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.sectors.Put(SECTOR_PREFIX+entry.Ticker, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteTickerSector removes a user entry; a bundled entry for the
+// ticker applies again
+func (r *SectorRepository) DeleteTickerSector(ticker string) error {
+	return r.sectors.Delete(SECTOR_PREFIX + normalizeTicker(ticker))
+}
+
+// ImportTickerSectors reads "ticker,sector" CSV lines (a header row is
+// optional) and saves every entry. Nothing is saved if any line is
+// invalid. It returns the number of entries imported.
+func (r *SectorRepository) ImportTickerSectors(data io.Reader) (int, error) {
+	entries, err := parseSectorsCSV(data)
+	if err != nil {
+		return 0, apperror.Wrap(apperror.Invalid, err)
+	}
+	for _, entry := range entries {
+		if err := r.sectors.Put(SECTOR_PREFIX+entry.Ticker, entry); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+// parseSectorsCSV reads "ticker,sector" lines, skipping a header row
+func parseSectorsCSV(data io.Reader) ([]*models.TickerSector, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var entries []*models.TickerSector
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "ticker") {
+			continue
+		}
+		entry, err := newTickerSector(record[0], record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newTickerSector(ticker, sector string) (*models.TickerSector, error) {
+	ticker = normalizeTicker(ticker)
+	if ticker == "" {
+		return nil, apperror.Newf(apperror.Invalid, "ticker is required")
+	}
+	name, ok := models.NormalizeSector(sector)
+	if !ok {
+		return nil, apperror.Newf(apperror.Invalid, "unknown sector %q for %s", sector, ticker)
+	}
+	return &models.TickerSector{Ticker: ticker, Sector: name}, nil
+}
+
+func normalizeTicker(ticker string) string {
+	return strings.ToUpper(strings.TrimSpace(ticker))
+}
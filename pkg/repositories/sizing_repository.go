@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/sizing"
+)
+
+// sizingRulesKey holds the position-sizing rules
+const sizingRulesKey = "meta_sizing_rules"
+
+// SizingRepository stores position-sizing rules and sizes trades with
+// them against the latest risk assessment
+type SizingRepository struct {
+	store database.Store
+	risks *RiskRepository
+}
+
+// NewSizingRepository creates a sizing repository on top of store
+func NewSizingRepository(store database.Store, risks *RiskRepository) *SizingRepository {
+	return &SizingRepository{store: store, risks: risks}
+}
+
+// GetSizingRules returns the saved rules, or the defaults
+func (r *SizingRepository) GetSizingRules() (*sizing.Rules, error) {
+	rules := sizing.DefaultRules()
+	err := database.Get(r.store, sizingRulesKey, rules)
+	if errors.Is(err, database.ErrNotFound) {
+		return sizing.DefaultRules(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sizing rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SaveSizingRules validates and saves the rules
+func (r *SizingRepository) SaveSizingRules(rules *sizing.Rules) error {
+	if err := rules.Validate(); err != nil {
+		return apperror.Wrap(apperror.Invalid, err)
+	}
+	return database.Set(r.store, sizingRulesKey, rules)
+}
+
+// Recommend sizes a trade with the saved rules and the latest risk assessment
+func (r *SizingRepository) Recommend(req sizing.Request) (*sizing.Recommendation, error) {
+	rules, err := r.GetSizingRules()
+	if err != nil {
+		return nil, err
+	}
+	return r.recommend(rules, req)
+}
+
+func (r *SizingRepository) recommend(rules *sizing.Rules, req sizing.Request) (*sizing.Recommendation, error) {
+	assessment, err := r.risks.GetLatestRiskAssessment()
+	if errors.Is(err, ErrNoRiskAssessments) {
+		assessment, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec, err := sizing.Recommend(rules, assessment, req)
+	switch {
+	case errors.Is(err, sizing.ErrNoEquity):
+		return nil, apperror.Wrap(apperror.Conflict, err)
+	case err != nil:
+		return nil, apperror.Wrap(apperror.Invalid, err)
+	}
+	return rec, nil
+}
+
+// CheckTrade compares a new trade's size with the recommendation and
+// records the result on the trade. It returns an error only when the
+// trade is too large and the rules refuse oversized trades. Trades are
+// not checked when enforcement is off, the account equity is not set,
+// or the trade has no legs or max loss.
+func (r *SizingRepository) CheckTrade(trade *models.Trade) error {
+	rules, err := r.GetSizingRules()
+	if err != nil {
+		return err
+	}
+	units := trade.Units()
+	if rules.Enforcement == sizing.EnforceOff || rules.AccountEquity <= 0 || units == 0 || trade.MaxLoss <= 0 {
+		return nil
+	}
+
+	rec, err := r.recommend(rules, sizing.Request{MaxLossPerUnit: trade.MaxLoss / float64(units)})
+	if err != nil {
+		return err
+	}
+	trade.SizeCheck = &models.SizeCheck{
+		CheckedAt:        time.Now(),
+		Units:            units,
+		RecommendedUnits: rec.Units,
+		Exceeded:         units > rec.Units,
+		Reasons:          rec.Reasons,
+	}
+	if !trade.SizeCheck.Exceeded {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%s %s is %d units; the recommended size is %d", trade.Ticker, trade.StrategyType, units, rec.Units)
+	if rules.Enforcement == sizing.EnforceRefuse {
+		return apperror.Newf(apperror.Invalid, "trade refused: %s", msg)
+	}
+	log.Printf("WARNING: Oversized trade: %s", msg)
+	return nil
+}
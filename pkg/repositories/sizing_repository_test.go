@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"testing"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/sizing"
+	"trading-dashboard/pkg/validation"
+)
+
+func TestRecommendErrorCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		equity float64
+		req    sizing.Request
+		code   apperror.Code
+		field  string
+	}{
+		{"equity not set", 0, sizing.Request{MaxLossPerUnit: 30}, apperror.Conflict, ""},
+		{"no max loss", 10000, sizing.Request{}, apperror.Invalid, "maxLossPerUnit"},
+		{"negative max loss", 10000, sizing.Request{MaxLossPerUnit: -5}, apperror.Invalid, "maxLossPerUnit"},
+	}
+	for _, tt := range tests {
+		store := database.NewMemoryStore()
+		repo := NewSizingRepository(store, NewRiskRepository(store, NewRiskScoringRepository(store)))
+		rules := sizing.DefaultRules()
+		rules.AccountEquity = tt.equity
+		if err := repo.SaveSizingRules(rules); err != nil {
+			t.Fatalf("%s: SaveSizingRules: %v", tt.name, err)
+		}
+
+		_, err := repo.Recommend(tt.req)
+		if code := apperror.CodeOf(err); code != tt.code {
+			t.Errorf("%s: Recommend error %v has code %q, want %q", tt.name, err, code, tt.code)
+		}
+		if tt.field == "" {
+			continue
+		}
+		if fields, ok := validation.Fields(err); !ok || len(fields) != 1 || fields[0].Field != tt.field {
+			t.Errorf("%s: fields = %+v, want one error on %s", tt.name, fields, tt.field)
+		}
+	}
+}
@@ -1,70 +1,369 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"trading-dashboard/pkg/database"
 	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/validation"
 )
 
+// STOCK_PREFIX stores stock ratings as stock_<ULID>. Ratings saved
+// before IDs became ULIDs were keyed stock_<TICKER>_<YYYYMMDD>.
 const STOCK_PREFIX = "stock_"
 
-// SaveStockRating saves a stock rating to the database
-func SaveStockRating(rating *models.StockRating) error {
+// STOCK_REVISION_PREFIX stores earlier versions of ratings as
+// stockrev_<rating ID>/<revision ULID>
+const STOCK_REVISION_PREFIX = "stockrev_"
+
+// stockTickerIndex files stock ratings by upper-cased ticker
+const stockTickerIndex = "ticker"
+
+// StockRepository stores stock ratings
+type StockRepository struct {
+	ratings   *database.Collection[models.StockRating]
+	idMap     *database.Collection[string]
+	revisions *database.Collection[models.StockRatingRevision]
+	sectors   *SectorRepository
+	snapshots *MarketSnapshotRepository
+	patterns  *ChartPatternRepository
+}
+
+// NewStockRepository creates a stock rating repository on top of store.
+// Ratings saved without a sector take the ticker's from sectors unless
+// sectors is nil; their market and sector sentiments live in snapshots
+// and their chart patterns are scored from patterns.
+func NewStockRepository(store database.Store, sectors *SectorRepository, snapshots *MarketSnapshotRepository, patterns *ChartPatternRepository) *StockRepository {
+	return &StockRepository{
+		ratings:   newStockRatings(store),
+		idMap:     newIDMap(store),
+		revisions: database.NewCollection[models.StockRatingRevision](store, STOCK_REVISION_PREFIX),
+		sectors:   sectors,
+		snapshots: snapshots,
+		patterns:  patterns,
+	}
+}
+
+// newStockRatings returns the rating collection with its ticker index
+func newStockRatings(store database.Store) *database.Collection[models.StockRating] {
+	return database.NewCollection[models.StockRating](store, STOCK_PREFIX).
+		AddIndex(stockTickerIndex, func(sr *models.StockRating) []string {
+			return []string{strings.ToUpper(sr.Ticker)}
+		})
+}
+
+// stockRevisionPrefix returns the key prefix of a rating's revisions
+func stockRevisionPrefix(ratingID string) string {
+	return STOCK_REVISION_PREFIX + ratingID + "/"
+}
+
+// EnsureIndexes builds the ticker index for ratings saved before it existed
+func (r *StockRepository) EnsureIndexes() error {
+	return r.ratings.EnsureIndexes()
+}
+
+// SaveStockRating saves a stock rating to the database. There is one
+// rating per ticker and day: rating a ticker again the same day updates
+// that rating and keeps the previous version as a revision.
+//
+// A rating with a SnapshotID takes its market and sector sentiments from
+// that snapshot; otherwise the sentiments it carries are saved as its
+// day's snapshot, replacing the day's previous one.
+func (r *StockRepository) SaveStockRating(rating *models.StockRating) error {
+	if err := rating.Validate(); err != nil {
+		return err
+	}
+	rating.Ticker = strings.ToUpper(strings.TrimSpace(rating.Ticker))
+	if rating.Date.IsZero() {
+		rating.Date = time.Now()
+	}
+
+	// If no ID is set, use the ticker's rating for the day
+	if rating.ID == "" {
+		same, err := r.GetStockRatingsByTicker(rating.Ticker)
+		if err != nil {
+			return err
+		}
+		for _, stored := range same {
+			if stored.TradingDay() == rating.TradingDay() {
+				rating.ID = stored.ID
+			}
+		}
+		if rating.ID == "" {
+			rating.ID = database.NewID(STOCK_PREFIX)
+		}
+	}
+
+	// Read the version being replaced before its snapshot can change
+	var previous *models.StockRating
+	stored, err := r.ratings.Get(rating.ID)
+	switch {
+	case err == nil:
+		if _, err := r.withSnapshots(stored); err != nil {
+			return err
+		}
+		previous = stored
+	case !errors.Is(err, database.ErrNotFound):
+		return err
+	}
+
+	if rating.SnapshotID != "" {
+		snapshot, err := r.snapshots.GetMarketSnapshot(rating.SnapshotID)
+		if err != nil {
+			return err
+		}
+		if snapshot.Day() != rating.TradingDay() {
+			var v validation.Validator
+			v.Add("snapshotId", validation.CodeInvalid,
+				fmt.Sprintf("market snapshot is for %s but the rating is for %s", snapshot.Day(), rating.TradingDay()))
+			return v.Err()
+		}
+		rating.ApplyMarketSnapshot(snapshot)
+	} else {
+		snapshot := rating.MarketSnapshot()
+		if err := r.snapshots.SaveMarketSnapshot(snapshot); err != nil {
+			return err
+		}
+		rating.ApplyMarketSnapshot(snapshot)
+	}
+
+	// Look up the ticker's sector so its sentiment counts towards enthusiasm
+	if sector, ok := models.NormalizeSector(rating.Sector); ok {
+		rating.Sector = sector
+	} else if r.sectors != nil {
+		entry, err := r.sectors.GetTickerSector(rating.Ticker)
+		if err == nil {
+			rating.Sector = entry.Sector
+		} else if !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+	}
+
+	// Score the chart pattern; an edited rating keeps its earlier points
+	if err := r.patterns.ScoreRating(rating, previous); err != nil {
+		return err
+	}
+
 	// Calculate enthusiasm rating
 	rating.CalculateEnthusiasm()
 
-	// If no ID is set, generate one
-	if rating.ID == "" {
-		// Generate key with ticker included for easier filtering
-		rating.ID = fmt.Sprintf("%s%s_%s", STOCK_PREFIX, rating.Ticker, rating.Date.Format("20060102"))
+	now := time.Now()
+	if previous != nil {
+		revision := &models.StockRatingRevision{
+			ID:       database.NewID(stockRevisionPrefix(previous.ID)),
+			RatingID: previous.ID,
+			EditedAt: now,
+			Previous: *previous,
+		}
+		if err := r.revisions.Put(revision.ID, revision); err != nil {
+			return err
+		}
 	}
+	rating.UpdatedAt = now
 
-	// Save the rating to BadgerDB
-	return database.Set(rating.ID, rating)
+	// Save the rating to the store without the snapshot's sentiments,
+	// which are filled in when it is read
+	record := *rating
+	record.ApplyMarketSnapshot(&models.MarketSnapshot{ID: rating.SnapshotID})
+	return r.ratings.Put(record.ID, &record)
+}
+
+// withSnapshots fills in the ratings' market and sector sentiments from
+// their snapshots. Their enthusiasm stays as it was stored at save time.
+func (r *StockRepository) withSnapshots(ratings ...*models.StockRating) ([]*models.StockRating, error) {
+	cache := make(map[string]*models.MarketSnapshot)
+	for _, rating := range ratings {
+		if rating.SnapshotID != "" {
+			snapshot, ok := cache[rating.SnapshotID]
+			if !ok {
+				var err error
+				snapshot, err = r.snapshots.GetMarketSnapshot(rating.SnapshotID)
+				if err != nil {
+					return nil, fmt.Errorf("rating %s: %w", rating.ID, err)
+				}
+				cache[rating.SnapshotID] = snapshot
+			}
+			rating.ApplyMarketSnapshot(snapshot)
+		}
+	}
+	return ratings, nil
 }
 
 // GetStockRating retrieves a stock rating by ID
-func GetStockRating(id string) (*models.StockRating, error) {
-	rating := &models.StockRating{}
-	err := database.Get(id, rating)
+func (r *StockRepository) GetStockRating(id string) (*models.StockRating, error) {
+	rating, err := r.ratings.Get(id)
+	if errors.Is(err, database.ErrNotFound) {
+		// Fall back to IDs re-keyed by MigrateLegacyIDs
+		if newID, mapErr := resolveLegacyID(r.idMap, id); mapErr == nil {
+			rating, err = r.ratings.Get(newID)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stock rating: %w", err)
 	}
+	if _, err := r.withSnapshots(rating); err != nil {
+		return nil, err
+	}
 	return rating, nil
 }
 
 // GetStockRatingsByTicker retrieves all stock ratings for a specific ticker
-func GetStockRatingsByTicker(ticker string) ([]*models.StockRating, error) {
-	// Get all stock ratings
-	allRatings, err := GetAllStockRatings()
+func (r *StockRepository) GetStockRatingsByTicker(ticker string) ([]*models.StockRating, error) {
+	ratings, err := r.ratings.Lookup(stockTickerIndex, strings.ToUpper(ticker))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get stock ratings by ticker: %w", err)
 	}
+	return r.withSnapshots(ratings...)
+}
 
-	// Filter ratings by ticker
-	var filteredRatings []*models.StockRating
-	for _, rating := range allRatings {
-		if strings.EqualFold(rating.Ticker, ticker) {
-			filteredRatings = append(filteredRatings, rating)
+// GetStockRatingAsOf retrieves a ticker's latest rating on or before day
+// (YYYY-MM-DD), or an error wrapping database.ErrNotFound
+func (r *StockRepository) GetStockRatingAsOf(ticker, day string) (*models.StockRating, error) {
+	ratings, err := r.GetStockRatingsByTicker(ticker)
+	if err != nil {
+		return nil, err
+	}
+	var latest *models.StockRating
+	for _, rating := range ratings {
+		if rating.TradingDay() <= day && (latest == nil || newerRating(rating, latest)) {
+			latest = rating
 		}
 	}
-
-	return filteredRatings, nil
+	if latest == nil {
+		return nil, fmt.Errorf("no rating for %s on or before %s: %w", ticker, day, database.ErrNotFound)
+	}
+	return latest, nil
 }
 
 // GetAllStockRatings retrieves all stock ratings
-func GetAllStockRatings() ([]*models.StockRating, error) {
-	var ratings []*models.StockRating
-	err := database.GetByPrefix(STOCK_PREFIX, &ratings)
+func (r *StockRepository) GetAllStockRatings() ([]*models.StockRating, error) {
+	ratings, err := r.ratings.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stock ratings: %w", err)
 	}
-	return ratings, nil
+	return r.withSnapshots(ratings...)
+}
+
+// GetStockRatingHistory retrieves the earlier versions of a rating,
+// oldest first
+func (r *StockRepository) GetStockRatingHistory(id string) ([]*models.StockRatingRevision, error) {
+	rating, err := r.GetStockRating(id)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := r.revisions.ListPrefix(stockRevisionPrefix(rating.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock rating history: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetStockRatingVersions retrieves every version of a ticker's ratings,
+// current and revised, in the order they were saved
+func (r *StockRepository) GetStockRatingVersions(ticker string) ([]*models.StockRating, error) {
+	ratings, err := r.GetStockRatingsByTicker(ticker)
+	if err != nil {
+		return nil, err
+	}
+	var versions []*models.StockRating
+	for _, rating := range ratings {
+		revisions, err := r.revisions.ListPrefix(stockRevisionPrefix(rating.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stock rating history: %w", err)
+		}
+		for _, revision := range revisions {
+			previous := revision.Previous
+			versions = append(versions, &previous)
+		}
+		versions = append(versions, rating)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].SavedAt().Before(versions[j].SavedAt())
+	})
+	return versions, nil
 }
 
 // DeleteStockRating deletes a stock rating by ID
-func DeleteStockRating(id string) error {
-	return database.Delete(id)
+func (r *StockRepository) DeleteStockRating(id string) error {
+	return r.ratings.Delete(id)
+}
+
+// splitMarketSnapshots moves the market and sector sentiments copied into
+// every stock rating into one snapshot per day. When a day's ratings
+// disagree, the snapshot takes the sentiments most of them were saved
+// with (the first by ID on a tie).
+func splitMarketSnapshots(store database.Store) error {
+	ratings := newStockRatings(store)
+	snapshots := database.NewCollection[models.MarketSnapshot](store, SNAPSHOT_PREFIX)
+
+	all, err := ratings.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan stock ratings: %w", err)
+	}
+	byDay := make(map[string][]*models.StockRating)
+	for _, rating := range all {
+		if rating.SnapshotID == "" {
+			byDay[rating.TradingDay()] = append(byDay[rating.TradingDay()], rating)
+		}
+	}
+
+	for day, same := range byDay {
+		sort.Slice(same, func(i, j int) bool { return same[i].ID < same[j].ID })
+		counts := make(map[[12]int]int)
+		var snapshot *models.MarketSnapshot
+		for _, rating := range same {
+			candidate := rating.MarketSnapshot()
+			key := candidate.Sentiments()
+			counts[key]++
+			if snapshot == nil || counts[key] > counts[snapshot.Sentiments()] {
+				snapshot = candidate
+			}
+		}
+		snapshot.ID = marketSnapshotID(day)
+		snapshot.UpdatedAt = time.Now()
+		if _, err := snapshots.Get(snapshot.ID); errors.Is(err, database.ErrNotFound) {
+			if err := snapshots.Put(snapshot.ID, snapshot); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		for _, rating := range same {
+			rating.ApplyMarketSnapshot(&models.MarketSnapshot{ID: snapshot.ID})
+			if err := ratings.Put(rating.ID, rating); err != nil {
+				return fmt.Errorf("failed to split %s: %w", rating.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// storeEnthusiasmScores stores the enthusiasm of ratings saved while it
+// was recalculated on every read. Each gets the score and breakdown it
+// was last shown with: the current formula over its day's snapshot.
+func storeEnthusiasmScores(store database.Store) error {
+	repo := NewStockRepository(store, nil, NewMarketSnapshotRepository(store), nil)
+	all, err := repo.ratings.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan stock ratings: %w", err)
+	}
+	for _, rating := range all {
+		if rating.EnthusiasmVersion != "" {
+			continue
+		}
+		if _, err := repo.withSnapshots(rating); err != nil {
+			return err
+		}
+		rating.CalculateEnthusiasm()
+		rating.ApplyMarketSnapshot(&models.MarketSnapshot{ID: rating.SnapshotID})
+		if err := repo.ratings.Put(rating.ID, rating); err != nil {
+			return fmt.Errorf("failed to store enthusiasm of %s: %w", rating.ID, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+func newTestStockRepository(t *testing.T, store database.Store) *StockRepository {
+	t.Helper()
+	patterns := NewChartPatternRepository(store)
+	if err := seedChartPatterns(store); err != nil {
+		t.Fatalf("seedChartPatterns: %v", err)
+	}
+	return NewStockRepository(store, NewSectorRepository(store), NewMarketSnapshotRepository(store), patterns)
+}
+
+func TestStockRatingKeepsEnthusiasmWhenSnapshotChanges(t *testing.T) {
+	store := database.NewMemoryStore()
+	stocks := newTestStockRepository(t, store)
+	day := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	first := &models.StockRating{Ticker: "AAPL", Date: day, StockSentiment: 2, MarketSentiment: 2, Pattern: models.PatternCupAndHandle}
+	if err := stocks.SaveStockRating(first); err != nil {
+		t.Fatalf("save first rating: %v", err)
+	}
+	want := first.EnthusiasmRating
+
+	// A later rating the same day without a snapshot ID replaces the day's snapshot
+	second := &models.StockRating{Ticker: "MSFT", Date: day, StockSentiment: 1, MarketSentiment: -3}
+	if err := stocks.SaveStockRating(second); err != nil {
+		t.Fatalf("save second rating: %v", err)
+	}
+
+	got, err := stocks.GetStockRating(first.ID)
+	if err != nil {
+		t.Fatalf("GetStockRating: %v", err)
+	}
+	if got.MarketSentiment != -3 {
+		t.Errorf("market sentiment = %d, want the day's snapshot value -3", got.MarketSentiment)
+	}
+	if got.EnthusiasmRating != want || got.EnthusiasmVersion != models.EnthusiasmVersion {
+		t.Errorf("enthusiasm = %d (version %q), want the saved %d (version %q)",
+			got.EnthusiasmRating, got.EnthusiasmVersion, want, models.EnthusiasmVersion)
+	}
+	if len(got.EnthusiasmBreakdown) == 0 {
+		t.Error("breakdown was not stored")
+	}
+}
+
+func TestStockRatingsUseULIDsAndOneRatingPerDay(t *testing.T) {
+	store := database.NewMemoryStore()
+	stocks := newTestStockRepository(t, store)
+	day := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	first := &models.StockRating{Ticker: "aapl", Date: day, StockSentiment: 1}
+	if err := stocks.SaveStockRating(first); err != nil {
+		t.Fatalf("save first rating: %v", err)
+	}
+	if !database.IsID(STOCK_PREFIX, first.ID) {
+		t.Errorf("rating ID %q is not a ULID", first.ID)
+	}
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		sameID bool
+	}{
+		{"later the same day", day.Add(15 * time.Hour), true},
+		{"next day", day.AddDate(0, 0, 1), false},
+	}
+	for _, tt := range tests {
+		rating := &models.StockRating{Ticker: "AAPL", Date: tt.date, StockSentiment: 3}
+		if err := stocks.SaveStockRating(rating); err != nil {
+			t.Fatalf("%s: save rating: %v", tt.name, err)
+		}
+		if got := rating.ID == first.ID; got != tt.sameID {
+			t.Errorf("%s: saved as %s, reused the day's ID = %v, want %v", tt.name, rating.ID, got, tt.sameID)
+		}
+	}
+
+	history, err := stocks.GetStockRatingHistory(first.ID)
+	if err != nil {
+		t.Fatalf("GetStockRatingHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("%d revisions, want 1", len(history))
+	}
+}
+
+func TestStockRatingLegacyIDStillResolves(t *testing.T) {
+	store := database.NewMemoryStore()
+	day := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+	oldID := "stock_AAPL_20250425"
+	legacy := &models.StockRating{ID: oldID, Ticker: "AAPL", Date: day, StockSentiment: 2}
+	if err := database.Set(store, oldID, legacy); err != nil {
+		t.Fatalf("seed legacy rating: %v", err)
+	}
+	migrated, err := MigrateLegacyIDs(store)
+	if err != nil {
+		t.Fatalf("MigrateLegacyIDs: %v", err)
+	}
+	newID := migrated[oldID]
+
+	stocks := newTestStockRepository(t, store)
+	if err := stocks.EnsureIndexes(); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+	got, err := stocks.GetStockRating(oldID)
+	if err != nil || got.ID != newID {
+		t.Fatalf("GetStockRating(%s) = %+v, %v; want the rating re-keyed to %s", oldID, got, err, newID)
+	}
+	if _, err := stocks.GetStockRatingHistory(oldID); err != nil {
+		t.Errorf("GetStockRatingHistory(%s): %v", oldID, err)
+	}
+
+	// Rating the ticker again that day updates the re-keyed rating
+	update := &models.StockRating{Ticker: "AAPL", Date: day, StockSentiment: 3}
+	if err := stocks.SaveStockRating(update); err != nil {
+		t.Fatalf("save rating: %v", err)
+	}
+	if update.ID != newID {
+		t.Errorf("same-day rating saved as %s, want %s", update.ID, newID)
+	}
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+// TRADE_EVENT_PREFIX stores lifecycle events as event_<trade ID>/<event ULID>
+// so each trade's events can be read with one prefix scan
+const TRADE_EVENT_PREFIX = "event_"
+
+// TradeEventRepository stores trade lifecycle events
+type TradeEventRepository struct {
+	store  database.Store
+	events *database.Collection[models.TradeEvent]
+	trades *TradeRepository
+}
+
+// NewTradeEventRepository creates a trade event repository on top of store
+func NewTradeEventRepository(store database.Store, trades *TradeRepository) *TradeEventRepository {
+	return &TradeEventRepository{
+		store:  store,
+		events: database.NewCollection[models.TradeEvent](store, TRADE_EVENT_PREFIX),
+		trades: trades,
+	}
+}
+
+// tradeEventPrefix returns the key prefix of a trade's events
+func tradeEventPrefix(tradeID string) string {
+	return TRADE_EVENT_PREFIX + tradeID + "/"
+}
+
+// RecordTradeEvent appends an event to a trade. The event is rejected if
+// replaying it after the existing events leaves the position inconsistent.
+func (r *TradeEventRepository) RecordTradeEvent(event *models.TradeEvent) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if event.Date.IsZero() {
+		event.Date = time.Now()
+	}
+
+	trade, err := r.trades.GetTrade(event.TradeID)
+	if err != nil {
+		return err
+	}
+	// Legacy IDs resolve to the migrated trade; file the event under its current ID
+	event.TradeID = trade.ID
+
+	// Assign the ID first: replay orders same-date events by ID, and the
+	// new event must sort after the stored ones as it will once saved
+	if event.ID == "" {
+		event.ID = database.NewID(tradeEventPrefix(trade.ID))
+	}
+
+	existing, err := r.GetTradeEvents(trade.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := models.BuildPosition(trade, append(existing, event), time.Now()); err != nil {
+		return apperror.Newf(apperror.Invalid, "invalid %s event: %w", event.Type, err)
+	}
+	return r.events.Put(event.ID, event)
+}
+
+// OpensContracts reports whether recording event would add contracts to
+// its trade's current position
+func (r *TradeEventRepository) OpensContracts(event *models.TradeEvent) (bool, error) {
+	pos, err := r.GetPosition(event.TradeID)
+	if err != nil {
+		return false, err
+	}
+	return event.OpensContracts(pos), nil
+}
+
+// GetTradeEvents retrieves a trade's events in the order they were recorded
+func (r *TradeEventRepository) GetTradeEvents(tradeID string) ([]*models.TradeEvent, error) {
+	events, err := r.events.ListPrefix(tradeEventPrefix(tradeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trade events: %w", err)
+	}
+	return events, nil
+}
+
+// GetPosition derives a trade's current position from its events
+func (r *TradeEventRepository) GetPosition(tradeID string) (*models.Position, error) {
+	trade, err := r.trades.GetTrade(tradeID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := r.GetTradeEvents(trade.ID)
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildPosition(trade, events, time.Now())
+}
+
+// GetAllPositions derives the position of every trade, keyed by trade
+// ID. Trades whose events no longer replay cleanly (for example after
+// their legs were edited) are logged and left out.
+func (r *TradeEventRepository) GetAllPositions() ([]*models.Trade, map[string]*models.Position, error) {
+	trades, err := r.trades.GetAllTrades()
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := r.events.List()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trade events: %w", err)
+	}
+	byTrade := make(map[string][]*models.TradeEvent)
+	for _, event := range events {
+		byTrade[event.TradeID] = append(byTrade[event.TradeID], event)
+	}
+
+	now := time.Now()
+	positions := make(map[string]*models.Position, len(trades))
+	for _, trade := range trades {
+		pos, err := models.BuildPosition(trade, byTrade[trade.ID], now)
+		if err != nil {
+			log.Printf("ERROR: Skipping position for trade %s: %v", trade.ID, err)
+			continue
+		}
+		positions[trade.ID] = pos
+	}
+	return trades, positions, nil
+}
+
+// DeleteTrade deletes a trade and its events in one transaction, so no
+// events are left behind without their trade
+func (r *TradeEventRepository) DeleteTrade(tradeID string) error {
+	trade, err := r.trades.GetTrade(tradeID)
+	if err != nil {
+		return err
+	}
+	events, err := r.GetTradeEvents(trade.ID)
+	if err != nil {
+		return err
+	}
+	err = r.store.Update(func(tx database.Tx) error {
+		for _, event := range events {
+			if err := r.events.DeleteTx(tx, event.ID); err != nil {
+				return fmt.Errorf("failed to delete trade event %s: %w", event.ID, err)
+			}
+		}
+		return r.trades.trades.DeleteTx(tx, trade.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete trade %s: %w", trade.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+)
+
+func TestRecordTradeEventSameDayOpenThenClose(t *testing.T) {
+	store := database.NewMemoryStore()
+	trades := NewTradeRepository(store, nil, nil, nil)
+	events := NewTradeEventRepository(store, trades)
+
+	day := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+	exp := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	trade := &models.Trade{Ticker: "AAPL", EntryDate: day}
+	if err := trades.SaveTrade(trade); err != nil {
+		t.Fatalf("SaveTrade: %v", err)
+	}
+
+	leg := models.Leg{OptionType: "call", Strike: 200, Expiration: exp, Quantity: 1, Side: "long", FillPrice: 3}
+	open := &models.TradeEvent{TradeID: trade.ID, Type: models.EventOpen, Date: day, Fills: []models.Leg{leg}}
+	if err := events.RecordTradeEvent(open); err != nil {
+		t.Fatalf("record open: %v", err)
+	}
+
+	leg.Side, leg.FillPrice = "short", 4
+	closing := &models.TradeEvent{TradeID: trade.ID, Type: models.EventClose, Date: day, Fills: []models.Leg{leg}}
+	if err := events.RecordTradeEvent(closing); err != nil {
+		t.Fatalf("record same-day close: %v", err)
+	}
+
+	pos, err := events.GetPosition(trade.ID)
+	if err != nil {
+		t.Fatalf("GetPosition: %v", err)
+	}
+	if pos.Status != models.PositionClosed {
+		t.Errorf("status = %s, want %s", pos.Status, models.PositionClosed)
+	}
+}
+
+func TestOpensContracts(t *testing.T) {
+	store := database.NewMemoryStore()
+	trades := NewTradeRepository(store, nil, nil, nil)
+	events := NewTradeEventRepository(store, trades)
+
+	day := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+	exp := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	held := models.Leg{OptionType: "call", Strike: 200, Expiration: exp, Quantity: 2, Side: "short", FillPrice: 3}
+	trade := &models.Trade{Ticker: "AAPL", EntryDate: day, Legs: []models.Leg{held}}
+	if err := trades.SaveTrade(trade); err != nil {
+		t.Fatalf("SaveTrade: %v", err)
+	}
+
+	buyBack := held
+	buyBack.Side = "long"
+	rolled := held
+	rolled.Expiration = exp.AddDate(0, 1, 0)
+	tooMany := buyBack
+	tooMany.Quantity = 3
+
+	tests := []struct {
+		name  string
+		event models.TradeEvent
+		want  bool
+	}{
+		{"adjust adds to the held leg", models.TradeEvent{Type: models.EventAdjust, Fills: []models.Leg{held}}, true},
+		{"roll to a later expiration", models.TradeEvent{Type: models.EventRoll, Fills: []models.Leg{buyBack, rolled}}, true},
+		{"adjust buys back part", models.TradeEvent{Type: models.EventAdjust, Fills: []models.Leg{{OptionType: "call", Strike: 200, Expiration: exp, Quantity: 1, Side: "long"}}}, false},
+		{"adjust flips the leg long", models.TradeEvent{Type: models.EventAdjust, Fills: []models.Leg{tooMany}}, true},
+		{"partial close", models.TradeEvent{Type: models.EventPartialClose, Fills: []models.Leg{buyBack}}, false},
+		{"expire", models.TradeEvent{Type: models.EventExpire}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.TradeID = trade.ID
+			got, err := events.OpensContracts(&tt.event)
+			if err != nil {
+				t.Fatalf("OpensContracts: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("OpensContracts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteTradeDeletesEvents(t *testing.T) {
+	store := database.NewMemoryStore()
+	trades := NewTradeRepository(store, nil, nil, nil)
+	events := NewTradeEventRepository(store, trades)
+
+	day := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+	leg := models.Leg{OptionType: "put", Strike: 180, Expiration: day.AddDate(0, 2, 0), Quantity: 1, Side: "long", FillPrice: 2}
+	trade := &models.Trade{Ticker: "AAPL", EntryDate: day}
+	if err := trades.SaveTrade(trade); err != nil {
+		t.Fatalf("SaveTrade: %v", err)
+	}
+	open := &models.TradeEvent{TradeID: trade.ID, Type: models.EventOpen, Date: day, Fills: []models.Leg{leg}}
+	if err := events.RecordTradeEvent(open); err != nil {
+		t.Fatalf("record open: %v", err)
+	}
+
+	if err := events.DeleteTrade(trade.ID); err != nil {
+		t.Fatalf("DeleteTrade: %v", err)
+	}
+	if _, err := trades.GetTrade(trade.ID); err == nil {
+		t.Error("trade still stored")
+	}
+	left, err := events.GetTradeEvents(trade.ID)
+	if err != nil {
+		t.Fatalf("GetTradeEvents: %v", err)
+	}
+	if len(left) != 0 {
+		t.Errorf("%d events left behind", len(left))
+	}
+	byTicker, err := trades.GetTradesByTicker("AAPL")
+	if err != nil || len(byTicker) != 0 {
+		t.Errorf("trades by ticker = %v, %v; want the index entry removed", byTicker, err)
+	}
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,70 +12,149 @@ import (
 
 const TRADE_PREFIX = "trade_"
 
+// Trade index names
+const (
+	tradeTickerIndex    = "ticker"
+	tradeEntryDateIndex = "entryDate"
+)
+
+// TradeRepository stores trades
+type TradeRepository struct {
+	trades *database.Collection[models.Trade]
+	idMap  *database.Collection[string]
+	sizing *SizingRepository
+	risks  *RiskRepository
+	stocks *StockRepository
+}
+
+// NewTradeRepository creates a trade repository on top of store. New
+// trades are checked against the recommended position size unless
+// sizing is nil, and trades are linked to the stock rating and risk
+// assessment current at entry unless risks or stocks is nil.
+func NewTradeRepository(store database.Store, sizing *SizingRepository, risks *RiskRepository, stocks *StockRepository) *TradeRepository {
+	return &TradeRepository{
+		trades: database.NewCollection[models.Trade](store, TRADE_PREFIX).
+			AddIndex(tradeTickerIndex, func(t *models.Trade) []string {
+				return []string{strings.ToUpper(t.Ticker)}
+			}).
+			AddIndex(tradeEntryDateIndex, func(t *models.Trade) []string {
+				return []string{database.TimeTerm(t.EntryDate)}
+			}),
+		idMap:  newIDMap(store),
+		sizing: sizing,
+		risks:  risks,
+		stocks: stocks,
+	}
+}
+
+// EnsureIndexes builds the ticker and entry date indexes for trades
+// saved before the indexes existed
+func (r *TradeRepository) EnsureIndexes() error {
+	return r.trades.EnsureIndexes()
+}
+
 // SaveTrade saves a trade to the database
-func SaveTrade(trade *models.Trade) error {
-	// If no ID is set, generate one
+func (r *TradeRepository) SaveTrade(trade *models.Trade) error {
+	// The calendar shows the nearest leg expiry when none was entered
+	if trade.ExpirationDate.IsZero() && len(trade.Legs) > 0 {
+		trade.ExpirationDate = trade.NearestExpiration()
+	}
+
+	// Reject bad fields and legs that don't form the declared strategy
+	if err := trade.Validate(); err != nil {
+		return err
+	}
+
+	// If no ID is set this is a new trade: check its size and generate an ID
 	if trade.ID == "" {
-		trade.ID = database.GenerateKey(TRADE_PREFIX)
+		if r.sizing != nil {
+			if err := r.sizing.CheckTrade(trade); err != nil {
+				return err
+			}
+		}
+		trade.ID = database.NewID(TRADE_PREFIX)
+	}
+
+	// Record the rating and assessment the trade was entered on
+	if trade.EntryContext == nil {
+		if err := r.linkEntryContext(trade); err != nil {
+			return err
+		}
+	}
+
+	// Save the trade to the store
+	return r.trades.Put(trade.ID, trade)
+}
+
+// linkEntryContext sets the trade's entry context from the ticker's
+// latest rating on or before the entry day and the entry day's risk
+// assessment. It leaves the context nil unless both repositories are set.
+func (r *TradeRepository) linkEntryContext(trade *models.Trade) error {
+	if r.risks == nil || r.stocks == nil {
+		return nil
+	}
+	day := models.TradingDay(trade.EntryDate)
+	entry := &models.EntryContext{LinkedAt: time.Now()}
+
+	rating, err := r.stocks.GetStockRatingAsOf(trade.Ticker, day)
+	switch {
+	case err == nil:
+		entry.StockRatingID = rating.ID
+		entry.RatingDate = rating.TradingDay()
+		entry.EnthusiasmRating = rating.EnthusiasmRating
+	case !errors.Is(err, database.ErrNotFound):
+		return err
+	}
+
+	assessment, err := r.risks.GetRiskAssessmentForDay(day)
+	switch {
+	case err == nil:
+		entry.RiskAssessmentID = assessment.ID
+		entry.RiskScore = assessment.OverallScore
+	case !errors.Is(err, database.ErrNotFound):
+		return err
 	}
 
-	// Save the trade to BadgerDB
-	return database.Set(trade.ID, trade)
+	trade.EntryContext = entry
+	return nil
 }
 
 // GetTrade retrieves a trade by ID
-func GetTrade(id string) (*models.Trade, error) {
-	trade := &models.Trade{}
-	err := database.Get(id, trade)
+func (r *TradeRepository) GetTrade(id string) (*models.Trade, error) {
+	trade, err := r.trades.Get(id)
+	if errors.Is(err, database.ErrNotFound) {
+		// Fall back to IDs re-keyed by MigrateLegacyIDs
+		if newID, mapErr := resolveLegacyID(r.idMap, id); mapErr == nil {
+			trade, err = r.trades.Get(newID)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trade: %w", err)
 	}
 	return trade, nil
 }
 
-// GetTradesByDateRange retrieves trades within a date range
-func GetTradesByDateRange(startDate, endDate time.Time) ([]*models.Trade, error) {
-	// Get all trades
-	allTrades, err := GetAllTrades()
+// GetTradesByDateRange retrieves trades with an entry date within a date range (inclusive)
+func (r *TradeRepository) GetTradesByDateRange(startDate, endDate time.Time) ([]*models.Trade, error) {
+	trades, err := r.trades.Range(tradeEntryDateIndex, database.TimeTerm(startDate), database.TimeTerm(endDate))
 	if err != nil {
-		return nil, err
-	}
-
-	// Filter trades by date range
-	var filteredTrades []*models.Trade
-	for _, trade := range allTrades {
-		if (trade.EntryDate.Equal(startDate) || trade.EntryDate.After(startDate)) &&
-			(trade.EntryDate.Equal(endDate) || trade.EntryDate.Before(endDate)) {
-			filteredTrades = append(filteredTrades, trade)
-		}
+		return nil, fmt.Errorf("failed to get trades by date range: %w", err)
 	}
-
-	return filteredTrades, nil
+	return trades, nil
 }
 
 // GetTradesByTicker retrieves trades for a specific ticker
-func GetTradesByTicker(ticker string) ([]*models.Trade, error) {
-	// Get all trades
-	allTrades, err := GetAllTrades()
+func (r *TradeRepository) GetTradesByTicker(ticker string) ([]*models.Trade, error) {
+	trades, err := r.trades.Lookup(tradeTickerIndex, strings.ToUpper(ticker))
 	if err != nil {
-		return nil, err
-	}
-
-	// Filter trades by ticker
-	var filteredTrades []*models.Trade
-	for _, trade := range allTrades {
-		if strings.EqualFold(trade.Ticker, ticker) {
-			filteredTrades = append(filteredTrades, trade)
-		}
+		return nil, fmt.Errorf("failed to get trades by ticker: %w", err)
 	}
-
-	return filteredTrades, nil
+	return trades, nil
 }
 
 // GetAllTrades retrieves all trades
-func GetAllTrades() ([]*models.Trade, error) {
-	var trades []*models.Trade
-	err := database.GetByPrefix(TRADE_PREFIX, &trades)
+func (r *TradeRepository) GetAllTrades() ([]*models.Trade, error) {
+	trades, err := r.trades.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trades: %w", err)
 	}
@@ -82,6 +162,37 @@ func GetAllTrades() ([]*models.Trade, error) {
 }
 
 // DeleteTrade deletes a trade by ID
-func DeleteTrade(id string) error {
-	return database.Delete(id)
+func (r *TradeRepository) DeleteTrade(id string) error {
+	return r.trades.Delete(id)
+}
+
+// linkTradeEntryContexts links trades saved before entry contexts
+// existed to the rating and assessment current at their entry
+func linkTradeEntryContexts(store database.Store) error {
+	risks := NewRiskRepository(store, NewRiskScoringRepository(store))
+	stocks := NewStockRepository(store, nil, NewMarketSnapshotRepository(store), NewChartPatternRepository(store))
+	if err := risks.EnsureIndexes(); err != nil {
+		return err
+	}
+	if err := stocks.EnsureIndexes(); err != nil {
+		return err
+	}
+	repo := NewTradeRepository(store, nil, risks, stocks)
+
+	all, err := repo.trades.List()
+	if err != nil {
+		return fmt.Errorf("failed to scan trades: %w", err)
+	}
+	for _, trade := range all {
+		if trade.EntryContext != nil {
+			continue
+		}
+		if err := repo.linkEntryContext(trade); err != nil {
+			return fmt.Errorf("failed to link %s: %w", trade.ID, err)
+		}
+		if err := repo.trades.Put(trade.ID, trade); err != nil {
+			return fmt.Errorf("failed to link %s: %w", trade.ID, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trading-dashboard/pkg/apperror"
+	"trading-dashboard/pkg/database"
+	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/validation"
+)
+
+const WATCHLIST_PREFIX = "watchlist_"
+
+// WatchlistRepository stores watchlists and ranks tickers for them
+type WatchlistRepository struct {
+	watchlists *database.Collection[models.Watchlist]
+	stocks     *StockRepository
+}
+
+// NewWatchlistRepository creates a watchlist repository on top of store;
+// tickers are ranked by their latest rating in stocks
+func NewWatchlistRepository(store database.Store, stocks *StockRepository) *WatchlistRepository {
+	return &WatchlistRepository{
+		watchlists: database.NewCollection[models.Watchlist](store, WATCHLIST_PREFIX),
+		stocks:     stocks,
+	}
+}
+
+// SaveWatchlist creates a watchlist, or replaces it if its ID is set.
+// Names must be unique, ignoring case.
+func (r *WatchlistRepository) SaveWatchlist(watchlist *models.Watchlist) error {
+	watchlist.Name = strings.TrimSpace(watchlist.Name)
+	if err := watchlist.Validate(); err != nil {
+		return err
+	}
+
+	all, err := r.GetAllWatchlists()
+	if err != nil {
+		return err
+	}
+	var existing *models.Watchlist
+	for _, other := range all {
+		if other.ID == watchlist.ID {
+			existing = other
+		} else if strings.EqualFold(other.Name, watchlist.Name) {
+			return apperror.Newf(apperror.Conflict, "a watchlist named %q already exists", other.Name)
+		}
+	}
+
+	now := time.Now()
+	if watchlist.ID == "" {
+		watchlist.ID = database.NewID(WATCHLIST_PREFIX)
+		watchlist.CreatedAt = now
+	} else if existing == nil {
+		return fmt.Errorf("failed to get watchlist: %w", database.ErrNotFound)
+	} else {
+		watchlist.CreatedAt = existing.CreatedAt
+	}
+	for i := range watchlist.Items {
+		item := &watchlist.Items[i]
+		item.Ticker = strings.ToUpper(strings.TrimSpace(item.Ticker))
+		if item.AddedAt.IsZero() {
+			item.AddedAt = now
+		}
+	}
+	watchlist.UpdatedAt = now
+	return r.watchlists.Put(watchlist.ID, watchlist)
+}
+
+// GetWatchlist retrieves a watchlist by ID
+func (r *WatchlistRepository) GetWatchlist(id string) (*models.Watchlist, error) {
+	watchlist, err := r.watchlists.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+	return watchlist, nil
+}
+
+// GetAllWatchlists retrieves every watchlist
+func (r *WatchlistRepository) GetAllWatchlists() ([]*models.Watchlist, error) {
+	watchlists, err := r.watchlists.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlists: %w", err)
+	}
+	return watchlists, nil
+}
+
+// DeleteWatchlist deletes a watchlist by ID
+func (r *WatchlistRepository) DeleteWatchlist(id string) error {
+	return r.watchlists.Delete(id)
+}
+
+// AddWatchlistItem adds a ticker to a watchlist, or updates its levels
+// and notes if it is already listed
+func (r *WatchlistRepository) AddWatchlistItem(watchlistID string, item models.WatchlistItem) (*models.Watchlist, error) {
+	watchlist, err := r.GetWatchlist(watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	if existing := watchlist.Item(item.Ticker); existing != nil {
+		item.AddedAt = existing.AddedAt
+		*existing = item
+	} else {
+		watchlist.Items = append(watchlist.Items, item)
+	}
+	if err := r.SaveWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// RemoveWatchlistItem removes a ticker from a watchlist
+func (r *WatchlistRepository) RemoveWatchlistItem(watchlistID, ticker string) (*models.Watchlist, error) {
+	watchlist, err := r.GetWatchlist(watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	items := watchlist.Items[:0]
+	for _, item := range watchlist.Items {
+		if item.Ticker != ticker {
+			items = append(items, item)
+		}
+	}
+	watchlist.Items = items
+	if err := r.SaveWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// RankTickers ranks tickers by the enthusiasm of their latest rating,
+// highest first, keeping those that match query. Ties go to the more
+// recent rating, then the ticker name.
+func (r *WatchlistRepository) RankTickers(query models.RankQuery) ([]*models.RankedTicker, error) {
+	var sector string
+	if query.Sector != "" {
+		var ok bool
+		if sector, ok = models.NormalizeSector(query.Sector); !ok {
+			var v validation.Validator
+			v.Add("sector", validation.CodeInvalid, fmt.Sprintf("unknown sector %q", query.Sector))
+			return nil, v.Err()
+		}
+	}
+	var watchlist *models.Watchlist
+	if query.WatchlistID != "" {
+		var err error
+		if watchlist, err = r.GetWatchlist(query.WatchlistID); err != nil {
+			return nil, err
+		}
+	}
+
+	ratings, err := r.stocks.GetAllStockRatings()
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*models.StockRating)
+	for _, rating := range ratings {
+		ticker := strings.ToUpper(rating.Ticker)
+		if current, ok := latest[ticker]; !ok || newerRating(rating, current) {
+			latest[ticker] = rating
+		}
+	}
+
+	ranked := make([]*models.RankedTicker, 0, len(latest))
+	for ticker, rating := range latest {
+		if sector != "" && rating.Sector != sector {
+			continue
+		}
+		if query.Pattern != "" && !strings.EqualFold(rating.Pattern, strings.TrimSpace(query.Pattern)) {
+			continue
+		}
+		entry := &models.RankedTicker{Ticker: ticker, Rating: rating}
+		if watchlist != nil {
+			if entry.Item = watchlist.Item(ticker); entry.Item == nil {
+				continue
+			}
+		}
+		ranked = append(ranked, entry)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i].Rating, ranked[j].Rating
+		if a.EnthusiasmRating != b.EnthusiasmRating {
+			return a.EnthusiasmRating > b.EnthusiasmRating
+		}
+		if newerRating(a, b) != newerRating(b, a) {
+			return newerRating(a, b)
+		}
+		return ranked[i].Ticker < ranked[j].Ticker
+	})
+	if query.Limit > 0 && len(ranked) > query.Limit {
+		ranked = ranked[:query.Limit]
+	}
+	for i, entry := range ranked {
+		entry.Rank = i + 1
+	}
+	return ranked, nil
+}
+
+// newerRating reports whether a was rated after b
+func newerRating(a, b *models.StockRating) bool {
+	if a.TradingDay() != b.TradingDay() {
+		return a.TradingDay() > b.TradingDay()
+	}
+	return a.SavedAt().After(b.SavedAt())
+}
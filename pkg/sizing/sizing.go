@@ -0,0 +1,195 @@
+// Package sizing recommends position sizes from account equity, the
+// latest risk assessment and a trade's maximum loss
+package sizing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/validation"
+)
+
+// ErrNoEquity is returned by Recommend until the account equity is set
+var ErrNoEquity = errors.New("account equity is not configured")
+
+// Sizing methods
+const (
+	MethodFixedFractional = "fixed_fractional" // Risk a fixed percent of equity
+	MethodKelly           = "kelly"            // Fractional Kelly, capped at RiskPercent
+	MethodVolatility      = "volatility"       // RiskPercent scaled by target/current volatility
+)
+
+// Enforcement modes for trades that exceed the recommended size
+const (
+	EnforceOff    = "off"
+	EnforceWarn   = "warn"
+	EnforceRefuse = "refuse"
+)
+
+// Rules configure the sizing engine
+type Rules struct {
+	AccountEquity float64 `json:"accountEquity"` // Dollars; 0 disables sizing checks
+	Method        string  `json:"method"`
+	RiskPercent   float64 `json:"riskPercent"` // Percent of equity risked per trade at full size
+	// Kelly inputs, e.g. from the performance statistics report
+	KellyWinRate     float64 `json:"kellyWinRate"`     // Percent of trades won
+	KellyPayoffRatio float64 `json:"kellyPayoffRatio"` // Average win / average loss
+	KellyFraction    float64 `json:"kellyFraction"`    // 0.5 for half Kelly
+	// TargetVolatility is the annualized volatility (percent) at which the
+	// volatility method risks the full RiskPercent
+	TargetVolatility float64 `json:"targetVolatility"`
+	// MinScoreScale is the smallest share of full size the risk score can
+	// scale a position down to
+	MinScoreScale float64 `json:"minScoreScale"`
+	Enforcement   string  `json:"enforcement"`
+}
+
+// DefaultRules risk 1% per trade, warn on oversized trades and leave
+// checks disabled until the account equity is set
+func DefaultRules() *Rules {
+	return &Rules{
+		Method:           MethodFixedFractional,
+		RiskPercent:      1,
+		KellyFraction:    0.5,
+		TargetVolatility: 20,
+		MinScoreScale:    0.1,
+		Enforcement:      EnforceWarn,
+	}
+}
+
+// Validate checks the rules
+func (r *Rules) Validate() error {
+	switch r.Method {
+	case MethodFixedFractional, MethodKelly, MethodVolatility:
+	default:
+		return fmt.Errorf("unknown sizing method %q", r.Method)
+	}
+	switch r.Enforcement {
+	case EnforceOff, EnforceWarn, EnforceRefuse:
+	default:
+		return fmt.Errorf("unknown enforcement %q", r.Enforcement)
+	}
+	if r.AccountEquity < 0 {
+		return fmt.Errorf("account equity cannot be negative")
+	}
+	if r.RiskPercent <= 0 || r.RiskPercent > 100 {
+		return fmt.Errorf("risk percent must be between 0 and 100")
+	}
+	if r.MinScoreScale < 0 || r.MinScoreScale > 1 {
+		return fmt.Errorf("minimum score scale must be between 0 and 1")
+	}
+	if r.Method == MethodKelly {
+		if r.KellyWinRate <= 0 || r.KellyWinRate >= 100 || r.KellyPayoffRatio <= 0 {
+			return fmt.Errorf("kelly sizing needs a win rate between 0 and 100 and a positive payoff ratio")
+		}
+		if r.KellyFraction <= 0 || r.KellyFraction > 1 {
+			return fmt.Errorf("kelly fraction must be between 0 and 1")
+		}
+	}
+	if r.Method == MethodVolatility && r.TargetVolatility <= 0 {
+		return fmt.Errorf("volatility sizing needs a positive target volatility")
+	}
+	return nil
+}
+
+// Request describes the trade being sized
+type Request struct {
+	MaxLossPerUnit float64 `json:"maxLossPerUnit"` // Dollars lost per contract/spread/share at worst
+	Volatility     float64 `json:"volatility"`     // Annualized percent; used by the volatility method
+}
+
+// Recommendation is a position size and how it was reached
+type Recommendation struct {
+	Units          int      `json:"units"`       // Contracts, spreads or shares
+	RiskBudget     float64  `json:"riskBudget"`  // Dollars that may be lost
+	RiskPercent    float64  `json:"riskPercent"` // Of equity, after method and score scaling
+	ScoreScale     float64  `json:"scoreScale"`
+	Score          float64  `json:"score"`
+	AssessmentID   string   `json:"assessmentId"` // Empty without an assessment
+	Method         string   `json:"method"`
+	Reasons        []string `json:"reasons"`
+	MaxLossPerUnit float64  `json:"maxLossPerUnit"`
+}
+
+// Recommend sizes a trade. assessment is the latest risk assessment, or
+// nil if there is none (the size is then scaled down to MinScoreScale).
+func Recommend(rules *Rules, assessment *models.RiskAssessment, req Request) (*Recommendation, error) {
+	if rules.AccountEquity <= 0 {
+		return nil, ErrNoEquity
+	}
+	if req.MaxLossPerUnit <= 0 {
+		var v validation.Validator
+		v.Add("maxLossPerUnit", validation.CodeOutOfRange, "max loss per unit must be positive")
+		return nil, v.Err()
+	}
+
+	rec := &Recommendation{Method: rules.Method, MaxLossPerUnit: req.MaxLossPerUnit}
+	riskPercent := methodRiskPercent(rules, req, rec)
+
+	if assessment == nil {
+		rec.ScoreScale = rules.MinScoreScale
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("no risk assessment: size scaled to %.0f%%", rec.ScoreScale*100))
+	} else {
+		rec.Score = assessment.OverallScore
+		rec.AssessmentID = assessment.ID
+		rec.ScoreScale = ScoreScale(assessment.OverallScore, rules.MinScoreScale)
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("risk score %.2f (%s, %s): size scaled to %.0f%%",
+			assessment.OverallScore, assessment.ScoringVersion, assessment.Date.Format("2006-01-02"), rec.ScoreScale*100))
+	}
+
+	rec.RiskPercent = round(riskPercent * rec.ScoreScale)
+	rec.RiskBudget = round(rules.AccountEquity * riskPercent / 100 * rec.ScoreScale)
+	rec.Units = int(math.Floor(rec.RiskBudget / req.MaxLossPerUnit))
+	rec.Reasons = append(rec.Reasons, fmt.Sprintf("risk budget $%.2f (%.2f%% of $%.2f) / max loss $%.2f per unit = %d",
+		rec.RiskBudget, rec.RiskPercent, rules.AccountEquity, req.MaxLossPerUnit, rec.Units))
+	return rec, nil
+}
+
+// ScoreScale maps a risk score from -3..+3 linearly to the share of full
+// size (as on the risk dashboard), never below minScale
+func ScoreScale(score, minScale float64) float64 {
+	scale := (score + 3) / 6
+	return round(math.Max(minScale, math.Min(1, scale)))
+}
+
+// methodRiskPercent returns the percent of equity the sizing method
+// risks at full size and records why
+func methodRiskPercent(rules *Rules, req Request, rec *Recommendation) float64 {
+	switch rules.Method {
+	case MethodKelly:
+		// Kelly fraction f* = W - (1 - W) / R
+		w := rules.KellyWinRate / 100
+		kelly := (w - (1-w)/rules.KellyPayoffRatio) * rules.KellyFraction * 100
+		if kelly <= 0 {
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("kelly: no edge at %.1f%% wins and %.2f payoff; nothing to risk", rules.KellyWinRate, rules.KellyPayoffRatio))
+			return 0
+		}
+		if kelly > rules.RiskPercent {
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("kelly: %.2f%% capped at %.2f%%", kelly, rules.RiskPercent))
+			return rules.RiskPercent
+		}
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("kelly: %.2f%% (%.0f%% of full Kelly)", kelly, rules.KellyFraction*100))
+		return kelly
+
+	case MethodVolatility:
+		if req.Volatility <= 0 {
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("volatility: none given, risking the fixed %.2f%%", rules.RiskPercent))
+			return rules.RiskPercent
+		}
+		// Scale down for volatile underlyings, never above the base risk
+		scale := math.Min(1, rules.TargetVolatility/req.Volatility)
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("volatility: %.1f%% vs target %.1f%% scales risk to %.2f%%",
+			req.Volatility, rules.TargetVolatility, rules.RiskPercent*scale))
+		return rules.RiskPercent * scale
+
+	default:
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("fixed fractional: %.2f%% of equity", rules.RiskPercent))
+		return rules.RiskPercent
+	}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
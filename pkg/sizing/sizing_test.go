@@ -0,0 +1,151 @@
+package sizing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/models"
+	"trading-dashboard/pkg/validation"
+)
+
+func assessment(score float64) *models.RiskAssessment {
+	return &models.RiskAssessment{ID: "risk_1", Date: time.Date(2025, 4, 25, 14, 0, 0, 0, time.UTC), OverallScore: score}
+}
+
+// rules returns DefaultRules with $10,000 of equity, changed by edit
+func rules(edit func(r *Rules)) *Rules {
+	r := DefaultRules()
+	r.AccountEquity = 10000
+	if edit != nil {
+		edit(r)
+	}
+	return r
+}
+
+func TestRecommend(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       *Rules
+		assessment  *models.RiskAssessment
+		req         Request
+		units       int
+		budget      float64
+		riskPercent float64
+	}{
+		{"full size at the top score", rules(nil), assessment(3), Request{MaxLossPerUnit: 30}, 3, 100, 1},
+		{"neutral score halves the size", rules(nil), assessment(0), Request{MaxLossPerUnit: 30}, 1, 50, 0.5},
+		{"lowest score stops at the minimum scale", rules(nil), assessment(-3), Request{MaxLossPerUnit: 30}, 0, 10, 0.1},
+		{"no assessment uses the minimum scale", rules(nil), nil, Request{MaxLossPerUnit: 2.5}, 4, 10, 0.1},
+		{"units round down", rules(nil), assessment(3), Request{MaxLossPerUnit: 33.34}, 2, 100, 1},
+		{"kelly below the cap", rules(func(r *Rules) {
+			r.Method, r.RiskPercent, r.KellyWinRate, r.KellyPayoffRatio = MethodKelly, 20, 60, 1.5
+		}), assessment(3), Request{MaxLossPerUnit: 500}, 3, 1666.67, 16.67},
+		{"kelly capped at the risk percent", rules(func(r *Rules) {
+			r.Method, r.KellyWinRate, r.KellyPayoffRatio = MethodKelly, 60, 1.5
+		}), assessment(3), Request{MaxLossPerUnit: 30}, 3, 100, 1},
+		{"kelly without an edge", rules(func(r *Rules) {
+			r.Method, r.KellyWinRate, r.KellyPayoffRatio = MethodKelly, 30, 1
+		}), assessment(3), Request{MaxLossPerUnit: 30}, 0, 0, 0},
+		{"volatility above target scales down", rules(func(r *Rules) { r.Method = MethodVolatility }),
+			assessment(3), Request{MaxLossPerUnit: 20, Volatility: 40}, 2, 50, 0.5},
+		{"volatility below target never scales up", rules(func(r *Rules) { r.Method = MethodVolatility }),
+			assessment(3), Request{MaxLossPerUnit: 20, Volatility: 10}, 5, 100, 1},
+		{"volatility not given", rules(func(r *Rules) { r.Method = MethodVolatility }),
+			assessment(3), Request{MaxLossPerUnit: 20}, 5, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Recommend(tt.rules, tt.assessment, tt.req)
+			if err != nil {
+				t.Fatalf("Recommend: %v", err)
+			}
+			if rec.Units != tt.units || rec.RiskBudget != tt.budget || rec.RiskPercent != tt.riskPercent {
+				t.Errorf("got %d units, $%v budget, %v%%; want %d, $%v, %v%%",
+					rec.Units, rec.RiskBudget, rec.RiskPercent, tt.units, tt.budget, tt.riskPercent)
+			}
+			if len(rec.Reasons) != 3 {
+				t.Errorf("reasons = %q, want one each for the method, score and budget", rec.Reasons)
+			}
+			if tt.assessment != nil && rec.AssessmentID != tt.assessment.ID {
+				t.Errorf("assessment ID = %q, want %q", rec.AssessmentID, tt.assessment.ID)
+			}
+		})
+	}
+}
+
+func TestRecommendRejectsMissingInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		equity   float64
+		req      Request
+		noEquity bool // Otherwise a field error on maxLossPerUnit
+	}{
+		{"no equity", 0, Request{MaxLossPerUnit: 30}, true},
+		{"negative equity", -100, Request{MaxLossPerUnit: 30}, true},
+		{"no max loss", 10000, Request{}, false},
+		{"negative max loss", 10000, Request{MaxLossPerUnit: -5}, false},
+	}
+	for _, tt := range tests {
+		r := rules(func(r *Rules) { r.AccountEquity = tt.equity })
+		rec, err := Recommend(r, assessment(3), tt.req)
+		if tt.noEquity {
+			if !errors.Is(err, ErrNoEquity) {
+				t.Errorf("%s: Recommend = %+v, %v; want ErrNoEquity", tt.name, rec, err)
+			}
+			continue
+		}
+		if fields, ok := validation.Fields(err); !ok || fields[0].Field != "maxLossPerUnit" {
+			t.Errorf("%s: Recommend = %+v, %v; want a maxLossPerUnit field error", tt.name, rec, err)
+		}
+	}
+}
+
+func TestScoreScale(t *testing.T) {
+	tests := []struct {
+		score, minScale, want float64
+	}{
+		{3, 0.1, 1},
+		{0, 0.1, 0.5},
+		{1.5, 0.1, 0.75},
+		{-2, 0.1, 0.17},
+		{-3, 0.1, 0.1},
+		{-3, 0, 0},
+		{5, 0.1, 1},
+	}
+	for _, tt := range tests {
+		if got := ScoreScale(tt.score, tt.minScale); got != tt.want {
+			t.Errorf("ScoreScale(%v, %v) = %v, want %v", tt.score, tt.minScale, got, tt.want)
+		}
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(r *Rules)
+		ok   bool
+	}{
+		{"defaults", nil, true},
+		{"no equity yet", func(r *Rules) { r.AccountEquity = 0 }, true},
+		{"negative equity", func(r *Rules) { r.AccountEquity = -1 }, false},
+		{"unknown method", func(r *Rules) { r.Method = "martingale" }, false},
+		{"unknown enforcement", func(r *Rules) { r.Enforcement = "block" }, false},
+		{"zero risk", func(r *Rules) { r.RiskPercent = 0 }, false},
+		{"risk over 100%", func(r *Rules) { r.RiskPercent = 101 }, false},
+		{"scale above 1", func(r *Rules) { r.MinScoreScale = 1.5 }, false},
+		{"kelly without a win rate", func(r *Rules) { r.Method = MethodKelly; r.KellyPayoffRatio = 1.5 }, false},
+		{"kelly with full fraction", func(r *Rules) {
+			r.Method, r.KellyWinRate, r.KellyPayoffRatio, r.KellyFraction = MethodKelly, 55, 1.2, 1
+		}, true},
+		{"kelly fraction above 1", func(r *Rules) {
+			r.Method, r.KellyWinRate, r.KellyPayoffRatio, r.KellyFraction = MethodKelly, 55, 1.2, 2
+		}, false},
+		{"volatility without a target", func(r *Rules) { r.Method, r.TargetVolatility = MethodVolatility, 0 }, false},
+	}
+	for _, tt := range tests {
+		if err := rules(tt.edit).Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"time"
+
+	"trading-dashboard/pkg/models"
+)
+
+// Threshold crossing directions
+const (
+	CrossedAbove = "above"
+	CrossedBelow = "below"
+)
+
+// RatingPoint is one saved version of a ticker's rating
+type RatingPoint struct {
+	SavedAt          time.Time `json:"savedAt"`
+	Date             string    `json:"date"` // Day rated, YYYY-MM-DD
+	RatingID         string    `json:"ratingId"`
+	Current          bool      `json:"current"` // False for versions replaced by a later save
+	EnthusiasmRating int       `json:"enthusiasmRating"`
+	StockSentiment   int       `json:"stockSentiment"`
+	SectorSentiment  int       `json:"sectorSentiment"`
+	MarketSentiment  int       `json:"marketSentiment"`
+	Pattern          string    `json:"pattern"`
+	Direction        string    `json:"direction"`
+}
+
+// ThresholdCrossing is a save that moved the enthusiasm rating to the
+// other side of the threshold
+type ThresholdCrossing struct {
+	SavedAt  time.Time `json:"savedAt"`
+	Date     string    `json:"date"`
+	RatingID string    `json:"ratingId"`
+	From     int       `json:"from"`
+	To       int       `json:"to"`
+	Crossed  string    `json:"crossed"` // above or below
+}
+
+// RatingTrend is how a ticker's rating changed over time
+type RatingTrend struct {
+	Ticker    string              `json:"ticker"`
+	Threshold int                 `json:"threshold"`
+	Points    []RatingPoint       `json:"points"` // Oldest first
+	Crossings []ThresholdCrossing `json:"crossings"`
+	// Above is whether the latest rating is at or above the threshold
+	Above bool `json:"above"`
+	// LatestCrossing is the last crossing, nil if there was none
+	LatestCrossing *ThresholdCrossing `json:"latestCrossing"`
+}
+
+// BuildRatingTrend builds a ticker's trend from every version of its
+// ratings in the order they were saved. A rating is above the threshold
+// when its enthusiasm is at least threshold.
+func BuildRatingTrend(ticker string, versions []*models.StockRating, threshold int) *RatingTrend {
+	trend := &RatingTrend{
+		Ticker:    ticker,
+		Threshold: threshold,
+		Points:    make([]RatingPoint, 0, len(versions)),
+		Crossings: []ThresholdCrossing{},
+	}
+
+	// A version is current unless a later version of the same rating follows
+	latest := make(map[string]int)
+	for i, v := range versions {
+		latest[v.ID] = i
+	}
+
+	for i, v := range versions {
+		sector, _ := v.SectorSentiment()
+		trend.Points = append(trend.Points, RatingPoint{
+			SavedAt:          v.SavedAt(),
+			Date:             v.TradingDay(),
+			RatingID:         v.ID,
+			Current:          latest[v.ID] == i,
+			EnthusiasmRating: v.EnthusiasmRating,
+			StockSentiment:   v.StockSentiment,
+			SectorSentiment:  sector,
+			MarketSentiment:  v.MarketSentiment,
+			Pattern:          v.Pattern,
+			Direction:        v.Direction,
+		})
+
+		above := v.EnthusiasmRating >= threshold
+		if i > 0 && above != trend.Above {
+			crossing := ThresholdCrossing{
+				SavedAt:  v.SavedAt(),
+				Date:     v.TradingDay(),
+				RatingID: v.ID,
+				From:     versions[i-1].EnthusiasmRating,
+				To:       v.EnthusiasmRating,
+				Crossed:  CrossedBelow,
+			}
+			if above {
+				crossing.Crossed = CrossedAbove
+			}
+			trend.Crossings = append(trend.Crossings, crossing)
+		}
+		trend.Above = above
+	}
+
+	if n := len(trend.Crossings); n > 0 {
+		trend.LatestCrossing = &trend.Crossings[n-1]
+	}
+	return trend
+}
@@ -0,0 +1,185 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"trading-dashboard/pkg/models"
+)
+
+// riskFactor reads one score from a risk assessment
+type riskFactor struct {
+	name  string
+	score func(ra *models.RiskAssessment) float64
+}
+
+// riskFactors are the assessment scores checked against trade outcomes:
+// the overall score, then every factor
+func riskFactors() []riskFactor {
+	factors := []riskFactor{{"overallScore", func(ra *models.RiskAssessment) float64 { return ra.OverallScore }}}
+	for _, name := range models.RiskFactors {
+		factors = append(factors, riskFactor{name, func(ra *models.RiskAssessment) float64 {
+			return float64(ra.FactorScores()[name])
+		}})
+	}
+	return factors
+}
+
+// AssessedDay is one day's assessment joined with the closed trades
+// entered that day
+type AssessedDay struct {
+	Date       string                 `json:"date"` // YYYY-MM-DD
+	Assessment *models.RiskAssessment `json:"assessment"`
+	Trades     int                    `json:"trades"`
+	Wins       int                    `json:"wins"`
+	NetPnl     float64                `json:"netPnl"`
+}
+
+// ScoreBucket is the outcome of trades entered on days a factor had one
+// score. Fractional overall scores are rounded to the nearest integer.
+type ScoreBucket struct {
+	Score      int     `json:"score"`
+	Days       int     `json:"days"`
+	Trades     int     `json:"trades"`
+	WinRate    float64 `json:"winRate"` // Percent of trades
+	TotalPnl   float64 `json:"totalPnl"`
+	AveragePnl float64 `json:"averagePnl"` // Per trade
+}
+
+// FactorCorrelation relates one assessment factor to trade P&L
+type FactorCorrelation struct {
+	Factor string `json:"factor"`
+	// Correlation is the Pearson correlation between the factor score and
+	// the P&L of each trade entered that day, from -1 to 1. It is 0 with
+	// fewer than two trades or when either side never varies.
+	Correlation float64       `json:"correlation"`
+	Samples     int           `json:"samples"`
+	Buckets     []ScoreBucket `json:"buckets"` // Lowest score first
+}
+
+// RiskCorrelationReport checks the daily risk assessment against results
+type RiskCorrelationReport struct {
+	Days    []AssessedDay       `json:"days"` // Oldest first
+	Factors []FactorCorrelation `json:"factors"`
+	// UnassessedTrades are closed trades entered on a day without an assessment
+	UnassessedTrades int `json:"unassessedTrades"`
+}
+
+// CorrelateRisk joins each day's risk assessment with the closed trades
+// entered that day, both bucketed by models.TradingDay. When a day has
+// several assessments the latest one counts.
+func CorrelateRisk(assessments []*models.RiskAssessment, trades []*models.Trade, positions map[string]*models.Position) *RiskCorrelationReport {
+	byDay := make(map[string]*models.RiskAssessment)
+	for _, ra := range assessments {
+		day := models.TradingDay(ra.Date)
+		if latest, ok := byDay[day]; !ok || ra.Date.After(latest.Date) {
+			byDay[day] = ra
+		}
+	}
+
+	report := &RiskCorrelationReport{Days: []AssessedDay{}, Factors: []FactorCorrelation{}}
+	days := make(map[string]*AssessedDay)
+	type sample struct {
+		assessment *models.RiskAssessment
+		pnl        float64
+	}
+	var samples []sample
+
+	for _, trade := range trades {
+		pos, ok := positions[trade.ID]
+		if !ok || pos.Status != models.PositionClosed {
+			continue
+		}
+		day := models.TradingDay(trade.EntryDate)
+		ra, ok := byDay[day]
+		if !ok {
+			report.UnassessedTrades++
+			continue
+		}
+
+		d := days[day]
+		if d == nil {
+			d = &AssessedDay{Date: day, Assessment: ra}
+			days[day] = d
+		}
+		d.Trades++
+		if pos.RealizedPnl > 0 {
+			d.Wins++
+		}
+		d.NetPnl += pos.RealizedPnl
+		samples = append(samples, sample{ra, pos.RealizedPnl})
+	}
+
+	for _, d := range days {
+		d.NetPnl = round(d.NetPnl)
+		report.Days = append(report.Days, *d)
+	}
+	sort.Slice(report.Days, func(i, j int) bool { return report.Days[i].Date < report.Days[j].Date })
+
+	for _, factor := range riskFactors() {
+		xs := make([]float64, len(samples))
+		ys := make([]float64, len(samples))
+		buckets := make(map[int]*ScoreBucket)
+		bucketDays := make(map[int]map[string]bool)
+		wins := make(map[int]int)
+
+		for i, s := range samples {
+			value := factor.score(s.assessment)
+			xs[i], ys[i] = value, s.pnl
+			score := int(math.Round(value))
+
+			b := buckets[score]
+			if b == nil {
+				b = &ScoreBucket{Score: score}
+				buckets[score] = b
+				bucketDays[score] = make(map[string]bool)
+			}
+			b.Trades++
+			b.TotalPnl += s.pnl
+			if s.pnl > 0 {
+				wins[score]++
+			}
+			bucketDays[score][s.assessment.TradingDay()] = true
+		}
+
+		fc := FactorCorrelation{Factor: factor.name, Correlation: pearson(xs, ys), Samples: len(samples), Buckets: []ScoreBucket{}}
+		for score, b := range buckets {
+			b.Days = len(bucketDays[score])
+			b.WinRate = round(float64(wins[score]) / float64(b.Trades) * 100)
+			b.AveragePnl = round(b.TotalPnl / float64(b.Trades))
+			b.TotalPnl = round(b.TotalPnl)
+			fc.Buckets = append(fc.Buckets, *b)
+		}
+		sort.Slice(fc.Buckets, func(i, j int) bool { return fc.Buckets[i].Score < fc.Buckets[j].Score })
+		report.Factors = append(report.Factors, fc)
+	}
+	return report
+}
+
+// pearson returns the correlation coefficient of xs and ys, or 0 when it
+// is undefined
+func pearson(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return 0
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return math.Round(cov/math.Sqrt(varX*varY)*1000) / 1000
+}
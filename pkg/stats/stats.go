@@ -0,0 +1,243 @@
+// Package stats computes trade journal performance statistics from
+// closed positions
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"trading-dashboard/pkg/models"
+)
+
+// TradingDaysPerYear annualizes the daily Sharpe and Sortino ratios
+const TradingDaysPerYear = 252
+
+// Summary is the performance of a set of closed trades. P&L figures are
+// in dollars, net of commissions and fees.
+type Summary struct {
+	Trades              int     `json:"trades"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	WinRate             float64 `json:"winRate"` // Percent of trades
+	NetPnl              float64 `json:"netPnl"`
+	AverageWin          float64 `json:"averageWin"`
+	AverageLoss         float64 `json:"averageLoss"` // Negative
+	Expectancy          float64 `json:"expectancy"`  // Average P&L per trade
+	ProfitFactor        float64 `json:"profitFactor"`
+	MaxDrawdown         float64 `json:"maxDrawdown"` // Largest peak-to-trough fall in cumulative P&L
+	LongestLosingStreak int     `json:"longestLosingStreak"`
+	TradingDays         int     `json:"tradingDays"` // Days with realized P&L
+	SharpeRatio         float64 `json:"sharpeRatio"`
+	SortinoRatio        float64 `json:"sortinoRatio"`
+}
+
+// Group is the summary of the trades sharing one key
+type Group struct {
+	Key     string  `json:"key"`
+	Summary Summary `json:"summary"`
+}
+
+// Report is the overall summary and its breakdowns
+type Report struct {
+	Overall     Summary `json:"overall"`
+	ByStrategy  []Group `json:"byStrategy"`
+	ByDirection []Group `json:"byDirection"`
+	BySector    []Group `json:"bySector"`
+	ByTicker    []Group `json:"byTicker"`
+	// ByEnthusiasm groups trades by the enthusiasm of the stock rating
+	// they were entered on, and ByRiskScore by the entry day's overall
+	// risk score rounded to the nearest integer. Both are ordered by
+	// score, with unlinked trades last under "Unknown".
+	ByEnthusiasm []Group `json:"byEnthusiasm"`
+	ByRiskScore  []Group `json:"byRiskScore"`
+}
+
+// outcome is one closed trade
+type outcome struct {
+	trade    *models.Trade
+	position *models.Position
+}
+
+// Compute builds the report from closed positions (see
+// TradeEventRepository.GetAllPositions); open trades are ignored. Empty
+// direction or sector values are grouped under "Unknown".
+func Compute(trades []*models.Trade, positions map[string]*models.Position) *Report {
+	var closed []outcome
+	for _, trade := range trades {
+		pos, ok := positions[trade.ID]
+		if ok && pos.Status == models.PositionClosed {
+			closed = append(closed, outcome{trade, pos})
+		}
+	}
+	// Drawdown and streaks follow the order trades were closed in
+	sort.SliceStable(closed, func(i, j int) bool {
+		return closed[i].position.ClosedAt.Before(closed[j].position.ClosedAt)
+	})
+
+	return &Report{
+		Overall:     summarize(closed),
+		ByStrategy:  groupBy(closed, func(t *models.Trade) string { return t.StrategyType }),
+		ByDirection: groupBy(closed, func(t *models.Trade) string { return t.Direction }),
+		BySector:    groupBy(closed, func(t *models.Trade) string { return t.Sector }),
+		ByTicker:    groupBy(closed, func(t *models.Trade) string { return strings.ToUpper(t.Ticker) }),
+		ByEnthusiasm: groupByScore(closed, func(t *models.Trade) (int, bool) {
+			if !t.EntryContext.HasRating() {
+				return 0, false
+			}
+			return t.EntryContext.EnthusiasmRating, true
+		}),
+		ByRiskScore: groupByScore(closed, func(t *models.Trade) (int, bool) {
+			if !t.EntryContext.HasRiskAssessment() {
+				return 0, false
+			}
+			return int(math.Round(t.EntryContext.RiskScore)), true
+		}),
+	}
+}
+
+func groupBy(closed []outcome, key func(t *models.Trade) string) []Group {
+	members := make(map[string][]outcome)
+	for _, o := range closed {
+		k := strings.TrimSpace(key(o.trade))
+		if k == "" {
+			k = "Unknown"
+		}
+		members[k] = append(members[k], o)
+	}
+
+	groups := make([]Group, 0, len(members))
+	for k, m := range members {
+		groups = append(groups, Group{Key: k, Summary: summarize(m)})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
+
+// groupByScore groups closed trades by an integer score, in score
+// order, with trades that have no score last under "Unknown"
+func groupByScore(closed []outcome, score func(t *models.Trade) (int, bool)) []Group {
+	members := make(map[int][]outcome)
+	var unknown []outcome
+	for _, o := range closed {
+		if s, ok := score(o.trade); ok {
+			members[s] = append(members[s], o)
+		} else {
+			unknown = append(unknown, o)
+		}
+	}
+
+	scores := make([]int, 0, len(members))
+	for s := range members {
+		scores = append(scores, s)
+	}
+	sort.Ints(scores)
+	groups := make([]Group, 0, len(scores)+1)
+	for _, s := range scores {
+		groups = append(groups, Group{Key: strconv.Itoa(s), Summary: summarize(members[s])})
+	}
+	if len(unknown) > 0 {
+		groups = append(groups, Group{Key: "Unknown", Summary: summarize(unknown)})
+	}
+	return groups
+}
+
+// summarize computes a Summary for closed trades sorted by close date
+func summarize(closed []outcome) Summary {
+	s := Summary{Trades: len(closed)}
+	if len(closed) == 0 {
+		return s
+	}
+
+	var grossWin, grossLoss, cumulative, peak float64
+	streak := 0
+	daily := make(map[string]float64)
+	for _, o := range closed {
+		pnl := o.position.RealizedPnl
+		s.NetPnl += pnl
+		switch {
+		case pnl > 0:
+			s.Wins++
+			grossWin += pnl
+			streak = 0
+		case pnl < 0:
+			s.Losses++
+			grossLoss -= pnl
+			streak++
+			s.LongestLosingStreak = max(s.LongestLosingStreak, streak)
+		default:
+			streak = 0
+		}
+
+		cumulative += pnl
+		peak = math.Max(peak, cumulative)
+		s.MaxDrawdown = math.Max(s.MaxDrawdown, peak-cumulative)
+
+		for _, r := range o.position.Realizations {
+			daily[models.TradingDay(r.Date)] += r.Net
+		}
+	}
+
+	s.WinRate = round(float64(s.Wins) / float64(s.Trades) * 100)
+	if s.Wins > 0 {
+		s.AverageWin = round(grossWin / float64(s.Wins))
+	}
+	if s.Losses > 0 {
+		s.AverageLoss = round(-grossLoss / float64(s.Losses))
+	}
+	s.Expectancy = round(s.NetPnl / float64(s.Trades))
+	// Without losses the profit factor is undefined and left at 0
+	if grossLoss > 0 {
+		s.ProfitFactor = round(grossWin / grossLoss)
+	}
+	s.NetPnl = round(s.NetPnl)
+	s.MaxDrawdown = round(s.MaxDrawdown)
+
+	returns := make([]float64, 0, len(daily))
+	for _, pnl := range daily {
+		returns = append(returns, pnl)
+	}
+	s.TradingDays = len(returns)
+	s.SharpeRatio, s.SortinoRatio = ratios(returns)
+	return s
+}
+
+// ratios returns the annualized Sharpe and Sortino ratios of daily P&L,
+// with a zero risk-free rate. Either is 0 when it is undefined (fewer
+// than two days, or no variation/downside).
+func ratios(daily []float64) (sharpe, sortino float64) {
+	n := float64(len(daily))
+	if len(daily) < 2 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, r := range daily {
+		mean += r
+	}
+	mean /= n
+
+	var variance, downside float64
+	for _, r := range daily {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	stdDev := math.Sqrt(variance / (n - 1))
+	downsideDev := math.Sqrt(downside / n)
+
+	annualize := math.Sqrt(TradingDaysPerYear)
+	if stdDev > 0 {
+		sharpe = round(mean / stdDev * annualize)
+	}
+	if downsideDev > 0 {
+		sortino = round(mean / downsideDev * annualize)
+	}
+	return sharpe, sortino
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"trading-dashboard/pkg/models"
+)
+
+var start = time.Date(2025, 4, 1, 15, 0, 0, 0, time.UTC)
+
+// closed returns a position closed day days after start with one realization of pnl
+func closed(tradeID string, day int, pnl float64) *models.Position {
+	at := start.AddDate(0, 0, day)
+	return &models.Position{TradeID: tradeID, Status: models.PositionClosed, RealizedPnl: pnl, ClosedAt: at,
+		Realizations: []models.Realization{{Date: at, EventType: models.EventClose, Gross: pnl, Net: pnl}}}
+}
+
+// outcomes pairs trades with positions closed on consecutive days
+func outcomes(pnls ...float64) []outcome {
+	var out []outcome
+	for i, pnl := range pnls {
+		trade := &models.Trade{ID: "trade_" + string(rune('a'+i))}
+		out = append(out, outcome{trade, closed(trade.ID, i, pnl)})
+	}
+	return out
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []outcome
+		want Summary
+	}{
+		{"no trades", nil, Summary{}},
+		{"wins, losses and a scratch", outcomes(100, -50, -25, 75, 0), Summary{
+			Trades: 5, Wins: 2, Losses: 2, WinRate: 40, NetPnl: 100, AverageWin: 87.5, AverageLoss: -37.5,
+			Expectancy: 20, ProfitFactor: 2.33, MaxDrawdown: 75, LongestLosingStreak: 2, TradingDays: 5,
+			SharpeRatio: 4.91, SortinoRatio: 12.7}},
+		// Without losses the profit factor and Sortino ratio are undefined
+		{"only wins", outcomes(10, 30), Summary{
+			Trades: 2, Wins: 2, WinRate: 100, NetPnl: 40, AverageWin: 20, Expectancy: 20, TradingDays: 2,
+			SharpeRatio: 22.45}},
+		// One day of P&L is too little for either ratio
+		{"one loss", outcomes(-12.345), Summary{
+			Trades: 1, Losses: 1, NetPnl: -12.35, AverageLoss: -12.35, Expectancy: -12.35,
+			MaxDrawdown: 12.35, LongestLosingStreak: 1, TradingDays: 1}},
+		{"drawdown from a later peak", outcomes(-10, 50, -20, -20, 5), Summary{
+			Trades: 5, Wins: 2, Losses: 3, WinRate: 40, NetPnl: 5, AverageWin: 27.5, AverageLoss: -16.67,
+			Expectancy: 1, ProfitFactor: 1.1, MaxDrawdown: 40, LongestLosingStreak: 2, TradingDays: 5,
+			SharpeRatio: 0.54, SortinoRatio: 1.18}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarize(tt.in); got != tt.want {
+				t.Errorf("summarize =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeBucketsByTradingDay(t *testing.T) {
+	// Two trades closed the same UTC day count as one day of P&L, even
+	// when one closed in the evening in New York the day before
+	eastern := time.FixedZone("EDT", -4*60*60)
+	evening := time.Date(2025, 4, 1, 21, 0, 0, 0, eastern)
+	morning := time.Date(2025, 4, 2, 9, 0, 0, 0, time.UTC)
+	in := []outcome{
+		{&models.Trade{ID: "trade_a"}, &models.Position{Status: models.PositionClosed, RealizedPnl: 10, ClosedAt: evening,
+			Realizations: []models.Realization{{Date: evening, Net: 10}}}},
+		{&models.Trade{ID: "trade_b"}, &models.Position{Status: models.PositionClosed, RealizedPnl: 20, ClosedAt: morning,
+			Realizations: []models.Realization{{Date: morning, Net: 20}}}},
+	}
+	if got := summarize(in); got.TradingDays != 1 {
+		t.Errorf("TradingDays = %d, want 1", got.TradingDays)
+	}
+}
+
+func TestRatios(t *testing.T) {
+	tests := []struct {
+		name            string
+		daily           []float64
+		sharpe, sortino float64
+	}{
+		{"too few days", []float64{50}, 0, 0},
+		{"no variation", []float64{10, 10, 10}, 0, 0},
+		{"no losing days", []float64{10, 30}, 22.45, 0},
+		{"mixed", []float64{30, -10}, 5.61, 22.45},
+	}
+	for _, tt := range tests {
+		sharpe, sortino := ratios(tt.daily)
+		if sharpe != tt.sharpe || sortino != tt.sortino {
+			t.Errorf("%s: ratios = %v, %v; want %v, %v", tt.name, sharpe, sortino, tt.sharpe, tt.sortino)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	trades := []*models.Trade{
+		{ID: "trade_1", Ticker: "aapl", StrategyType: "Long Call", Direction: models.DirectionBullish, Sector: "Technology",
+			EntryContext: &models.EntryContext{StockRatingID: "stock_1", EnthusiasmRating: 7, RiskAssessmentID: "risk_1", RiskScore: 2.5}},
+		{ID: "trade_2", Ticker: "AAPL", StrategyType: "Long Call", Direction: models.DirectionBullish,
+			EntryContext: &models.EntryContext{StockRatingID: "stock_2", EnthusiasmRating: -2}},
+		{ID: "trade_3", Ticker: "MSFT", StrategyType: "Short Put", Direction: "  ", Sector: "Technology"},
+		// Still open, so left out of every figure
+		{ID: "trade_4", Ticker: "TSLA", StrategyType: "Short Put"},
+		// No position at all
+		{ID: "trade_5", Ticker: "NVDA", StrategyType: "Short Put"},
+	}
+	positions := map[string]*models.Position{
+		// Closed out of order; drawdown follows the close dates
+		"trade_1": closed("trade_1", 2, 100),
+		"trade_2": closed("trade_2", 0, -40),
+		"trade_3": closed("trade_3", 1, 20),
+		"trade_4": {TradeID: "trade_4", Status: models.PositionOpen},
+	}
+	report := Compute(trades, positions)
+
+	if report.Overall.Trades != 3 || report.Overall.NetPnl != 80 || report.Overall.MaxDrawdown != 40 {
+		t.Errorf("overall = %+v, want 3 trades netting 80 with a 40 drawdown", report.Overall)
+	}
+
+	keys := func(groups []Group) []string {
+		var out []string
+		for _, g := range groups {
+			out = append(out, fmt.Sprintf("%s:%d", g.Key, g.Summary.Trades))
+		}
+		return out
+	}
+	tests := []struct {
+		name   string
+		groups []Group
+		want   []string
+	}{
+		{"by strategy", report.ByStrategy, []string{"Long Call:2", "Short Put:1"}},
+		{"by direction", report.ByDirection, []string{"Unknown:1", "bullish:2"}},
+		{"by sector", report.BySector, []string{"Technology:2", "Unknown:1"}},
+		{"by ticker", report.ByTicker, []string{"AAPL:2", "MSFT:1"}},
+		{"by enthusiasm", report.ByEnthusiasm, []string{"-2:1", "7:1", "Unknown:1"}},
+		{"by risk score", report.ByRiskScore, []string{"3:1", "Unknown:2"}},
+	}
+	for _, tt := range tests {
+		if got := keys(tt.groups); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package validation collects field-level input errors so forms can
+// highlight each bad field
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error codes
+const (
+	CodeRequired   = "required"
+	CodeOutOfRange = "out_of_range"
+	CodeInvalid    = "invalid"
+	CodeOrder      = "order"    // A value must come before/after another field
+	CodeStrategy   = "strategy" // Legs don't form the declared strategy
+)
+
+// FieldError is one invalid field. Field uses the JSON field name, with
+// list items as "legs[0].strike".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors are the field errors of one value
+type Errors []FieldError
+
+// Error joins the messages
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fields returns the field errors in err, if it holds any
+func Fields(err error) (Errors, bool) {
+	var fields Errors
+	if errors.As(err, &fields) {
+		return fields, true
+	}
+	return nil, false
+}
+
+// Validator accumulates field errors
+type Validator struct {
+	errs Errors
+}
+
+// Add records an error for field
+func (v *Validator) Add(field, code, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required checks that a string field is not blank
+func (v *Validator) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, fmt.Sprintf("%s is required", field))
+	}
+}
+
+// IntRange checks that value is between min and max inclusive
+func (v *Validator) IntRange(field string, value, min, max int) {
+	if value < min || value > max {
+		v.Add(field, CodeOutOfRange, fmt.Sprintf("%s must be between %d and %d, got %d", field, min, max, value))
+	}
+}
+
+// NotNegative checks that value is zero or more
+func (v *Validator) NotNegative(field string, value float64) {
+	if value < 0 {
+		v.Add(field, CodeOutOfRange, fmt.Sprintf("%s cannot be negative", field))
+	}
+}
+
+// Nested adds the errors of a nested value (e.g. one leg of a trade),
+// prefixing field names with field and messages with label
+func (v *Validator) Nested(field, label string, err error) {
+	if err == nil {
+		return
+	}
+	fields, ok := Fields(err)
+	if !ok {
+		v.Add(field, CodeInvalid, fmt.Sprintf("%s: %v", label, err))
+		return
+	}
+	for _, fe := range fields {
+		v.Add(field+"."+fe.Field, fe.Code, fmt.Sprintf("%s: %s", label, fe.Message))
+	}
+}
+
+// Err returns the accumulated errors, or nil if there are none
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
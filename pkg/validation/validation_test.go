@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(v *Validator)
+		want  Errors
+	}{
+		{"nothing checked", func(v *Validator) {}, nil},
+		{"required present", func(v *Validator) { v.Required("ticker", "AAPL") }, nil},
+		{"required blank", func(v *Validator) { v.Required("ticker", "  ") },
+			Errors{{"ticker", CodeRequired, "ticker is required"}}},
+		{"range bounds are inclusive", func(v *Validator) { v.IntRange("rating", -3, -3, 3); v.IntRange("rating", 3, -3, 3) }, nil},
+		{"out of range", func(v *Validator) { v.IntRange("rating", 4, -3, 3) },
+			Errors{{"rating", CodeOutOfRange, "rating must be between -3 and 3, got 4"}}},
+		{"zero is not negative", func(v *Validator) { v.NotNegative("commission", 0) }, nil},
+		{"negative", func(v *Validator) { v.NotNegative("commission", -0.01) },
+			Errors{{"commission", CodeOutOfRange, "commission cannot be negative"}}},
+		{"errors keep their order", func(v *Validator) {
+			v.Required("ticker", "")
+			v.NotNegative("fees", -1)
+			v.Add("exitDate", CodeOrder, "exitDate must be after entryDate")
+		}, Errors{
+			{"ticker", CodeRequired, "ticker is required"},
+			{"fees", CodeOutOfRange, "fees cannot be negative"},
+			{"exitDate", CodeOrder, "exitDate must be after entryDate"},
+		}},
+	}
+	for _, tt := range tests {
+		var v Validator
+		tt.check(&v)
+		err := v.Err()
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: Err = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		fields, ok := Fields(err)
+		if !ok || !reflect.DeepEqual(fields, tt.want) {
+			t.Errorf("%s: fields = %+v, want %+v", tt.name, fields, tt.want)
+		}
+	}
+}
+
+func TestNested(t *testing.T) {
+	leg := Errors{{"strike", CodeOutOfRange, "strike cannot be negative"}}
+	tests := []struct {
+		name string
+		err  error
+		want Errors
+	}{
+		{"valid leg", nil, nil},
+		{"field errors are prefixed", leg, Errors{{"legs[1].strike", CodeOutOfRange, "leg 2: strike cannot be negative"}}},
+		{"wrapped field errors", fmt.Errorf("checking leg: %w", leg),
+			Errors{{"legs[1].strike", CodeOutOfRange, "leg 2: strike cannot be negative"}}},
+		{"plain error", errors.New("unknown option type"), Errors{{"legs[1]", CodeInvalid, "leg 2: unknown option type"}}},
+	}
+	for _, tt := range tests {
+		var v Validator
+		v.Nested("legs[1]", "leg 2", tt.err)
+		fields, _ := Fields(v.Err())
+		if !reflect.DeepEqual(fields, tt.want) {
+			t.Errorf("%s: fields = %+v, want %+v", tt.name, fields, tt.want)
+		}
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	err := Errors{{"ticker", CodeRequired, "ticker is required"}, {"fees", CodeOutOfRange, "fees cannot be negative"}}
+	if got, want := err.Error(), "ticker is required; fees cannot be negative"; got != want {
+		t.Errorf("Error = %q, want %q", got, want)
+	}
+	if _, ok := Fields(errors.New("plain")); ok {
+		t.Error("Fields found field errors in a plain error")
+	}
+}